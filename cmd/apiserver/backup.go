@@ -0,0 +1,401 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"github.com/spf13/cobra"
+	"io"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"os"
+	"sigs.k8s.io/yaml"
+)
+
+type backupResource struct {
+	Kind     string
+	Resource string
+	// Version is the version exported; imports use the version of each object
+	Version string
+}
+
+// backupResources are exported and imported in this order, Configs first
+// since Foos refer to them, FooDefaults last so that they are not applied to
+// the Foos being imported.
+var backupResources = []backupResource{
+	{Kind: "Config", Resource: "configs", Version: v1alpha1.SchemeGroupVersion.Version},
+	{Kind: "Foo", Resource: "foos", Version: v1beta1.SchemeGroupVersion.Version},
+	{Kind: "FooDefaults", Resource: "foodefaults", Version: v1beta1.SchemeGroupVersion.Version},
+}
+
+func (r backupResource) groupResource() schema.GroupResource {
+	return demo.Resource(r.Resource)
+}
+
+const (
+	formatYAML   = "yaml"
+	formatNDJSON = "ndjson"
+
+	conflictFail      = "fail"
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+
+	// every export starts with a header of this kind and version; import
+	// only reads the versions it knows
+	exportKind       = "DemoExport"
+	exportAPIVersion = "export.demo.k8s.io/v1"
+)
+
+// backupOptions are shared by export and import. They talk to the demo API
+// through the aggregator of the cluster in kubeconfig.
+type backupOptions struct {
+	KubeConfig string
+	Namespace  string
+	File       string
+}
+
+func (o *backupOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.KubeConfig, "kubeconfig", o.KubeConfig, "The kubeconfig of the cluster that serves the demo API (defaults to the usual kubeconfig loading rules)")
+	cmd.Flags().StringVarP(&o.Namespace, "namespace", "n", o.Namespace, "Only handle objects in this namespace (defaults to all namespaces)")
+}
+
+func (o *backupOptions) client() (dynamic.Interface, error) {
+	return newDynamicClient(o.KubeConfig)
+}
+
+// newDynamicClient returns a client for the cluster in kubeconfig, or the one
+// found by the usual loading rules if kubeconfig is empty.
+func newDynamicClient(kubeconfig string) (dynamic.Interface, error) {
+	config, err := loadClientConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(config)
+}
+
+func loadClientConfig(kubeconfig string) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load kubeconfig: %v", err)
+	}
+	return config, nil
+}
+
+type exportOptions struct {
+	backupOptions
+	Format   string
+	PageSize int64
+}
+
+func newExportCommand() *cobra.Command {
+	o := &exportOptions{Format: formatYAML, PageSize: 500}
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write all demo resources to a file",
+		Long: "Write all demo resources to a file, as a YAML stream or as one JSON object per line. " +
+			"The file starts with a " + exportKind + " header naming the version of the format, " + exportAPIVersion + ". " +
+			"All resources are read at the resourceVersion of the first list, so the file is one consistent snapshot.",
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.run(c.Context(), c.OutOrStdout(), c.ErrOrStderr())
+		},
+	}
+	o.addFlags(cmd)
+	cmd.Flags().StringVarP(&o.File, "file", "f", o.File, "The file to write to (defaults to stdout)")
+	cmd.Flags().StringVarP(&o.Format, "output", "o", o.Format, "The output format, one of: yaml, ndjson")
+	cmd.Flags().Int64Var(&o.PageSize, "page-size", o.PageSize, "The number of objects to read per request")
+	return cmd
+}
+
+func (o *exportOptions) run(ctx context.Context, stdout, stderr io.Writer) error {
+	if o.Format != formatYAML && o.Format != formatNDJSON {
+		return fmt.Errorf("unsupported output format %q, must be one of: %s, %s", o.Format, formatYAML, formatNDJSON)
+	}
+	client, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	out := stdout
+	if len(o.File) > 0 {
+		f, err := os.Create(o.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+	header := &unstructured.Unstructured{}
+	header.SetAPIVersion(exportAPIVersion)
+	header.SetKind(exportKind)
+	if err := writeObject(w, o.Format, header); err != nil {
+		return err
+	}
+
+	// all resources share the revisions of one etcd, so the first list pins
+	// the snapshot every other resource is read at
+	resourceVersion := ""
+	for _, resource := range backupResources {
+		gvr := schema.GroupVersionResource{Group: demo.GroupName, Version: resource.Version, Resource: resource.Resource}
+		count := 0
+		opts := metav1.ListOptions{Limit: o.PageSize}
+		if len(resourceVersion) > 0 {
+			opts.ResourceVersion = resourceVersion
+			opts.ResourceVersionMatch = metav1.ResourceVersionMatchExact
+		}
+		for {
+			list, err := client.Resource(gvr).Namespace(o.Namespace).List(ctx, opts)
+			if apierrors.IsNotFound(err) {
+				fmt.Fprintf(stderr, "skipping %s: not served\n", gvr)
+				break
+			}
+			if apierrors.IsResourceExpired(err) {
+				return fmt.Errorf("unable to list %s: resourceVersion %s was compacted during the export, run it again", gvr, resourceVersion)
+			}
+			if err != nil {
+				return fmt.Errorf("unable to list %s: %v", gvr, err)
+			}
+			if len(resourceVersion) == 0 {
+				resourceVersion = list.GetResourceVersion()
+			}
+			for i := range list.Items {
+				if err := writeObject(w, o.Format, &list.Items[i]); err != nil {
+					return err
+				}
+				count++
+			}
+			// the continue token pins every page to the resourceVersion of the first
+			opts.Continue = list.GetContinue()
+			opts.ResourceVersion, opts.ResourceVersionMatch = "", ""
+			if len(opts.Continue) == 0 {
+				fmt.Fprintf(stderr, "exported %d %s at resourceVersion %s\n", count, gvr.GroupResource(), list.GetResourceVersion())
+				break
+			}
+		}
+	}
+	return w.Flush()
+}
+
+func writeObject(w io.Writer, format string, obj *unstructured.Unstructured) error {
+	switch format {
+	case formatNDJSON:
+		data, err := json.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	default:
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "---\n%s", data)
+		return err
+	}
+}
+
+type importOptions struct {
+	backupOptions
+	ConflictPolicy string
+	DryRun         bool
+}
+
+func newImportCommand() *cobra.Command {
+	o := &importOptions{backupOptions: backupOptions{File: "-"}, ConflictPolicy: conflictFail}
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Restore demo resources written by export",
+		Long: "Restore demo resources written by export, in a version of the format this command reads. " +
+			"Server-set metadata such as resourceVersion and uid is dropped, and so are owner references, since the owners get new uids. " +
+			"Objects go through the usual defaulting, validation and admission, and every object is reported.",
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.run(c.Context(), c.InOrStdin(), c.OutOrStdout())
+		},
+	}
+	o.addFlags(cmd)
+	cmd.Flags().StringVarP(&o.File, "file", "f", o.File, "The file to read, YAML or NDJSON; - reads stdin")
+	cmd.Flags().StringVar(&o.ConflictPolicy, "conflict-policy", o.ConflictPolicy, "What to do with objects that already exist, one of: fail, skip, overwrite")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun, "If true, only report what would happen; the server still validates every object")
+	return cmd
+}
+
+func (o *importOptions) run(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	if !sets.New(conflictFail, conflictSkip, conflictOverwrite).Has(o.ConflictPolicy) {
+		return fmt.Errorf("unsupported conflict policy %q, must be one of: %s, %s, %s", o.ConflictPolicy, conflictFail, conflictSkip, conflictOverwrite)
+	}
+	in := stdin
+	if o.File != "-" {
+		f, err := os.Open(o.File)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	objs, err := readObjects(in)
+	if err != nil {
+		return err
+	}
+	client, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	suffix := ""
+	if o.DryRun {
+		suffix = " (dry run)"
+	}
+	results := map[string]int{}
+	failed := 0
+	for _, resource := range backupResources {
+		for _, obj := range objs {
+			if obj.GetKind() != resource.Kind {
+				continue
+			}
+			if len(o.Namespace) > 0 && obj.GetNamespace() != o.Namespace {
+				continue
+			}
+			gvr := obj.GroupVersionKind().GroupVersion().WithResource(resource.Resource)
+			result, err := o.restore(ctx, client.Resource(gvr), obj)
+			name := obj.GetNamespace() + "/" + obj.GetName()
+			if err != nil {
+				failed++
+				fmt.Fprintf(stdout, "%s %s failed: %v\n", resource.groupResource(), name, err)
+				continue
+			}
+			results[result]++
+			fmt.Fprintf(stdout, "%s %s %s%s\n", resource.groupResource(), name, result, suffix)
+		}
+	}
+	fmt.Fprintf(stdout, "%d created, %d updated, %d skipped, %d failed%s\n", results["created"], results["updated"], results["skipped"], failed, suffix)
+	if failed > 0 {
+		return fmt.Errorf("%d objects could not be imported", failed)
+	}
+	return nil
+}
+
+// restore creates obj, handling an existing object as the conflict policy says.
+func (o *importOptions) restore(ctx context.Context, client dynamic.NamespaceableResourceInterface, obj *unstructured.Unstructured) (string, error) {
+	var dryRun []string
+	if o.DryRun {
+		dryRun = []string{metav1.DryRunAll}
+	}
+	resources := client.Namespace(obj.GetNamespace())
+
+	_, err := resources.Create(ctx, obj, metav1.CreateOptions{DryRun: dryRun})
+	if err == nil {
+		return "created", nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return "", err
+	}
+	switch o.ConflictPolicy {
+	case conflictSkip:
+		return "skipped", nil
+	case conflictOverwrite:
+		existing, err := resources.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		if _, err := resources.Update(ctx, obj, metav1.UpdateOptions{DryRun: dryRun}); err != nil {
+			return "", err
+		}
+		return "updated", nil
+	default:
+		return "", err
+	}
+}
+
+// readObjects decodes a YAML stream or NDJSON written by export into objects
+// without the metadata the server sets. Lists are flattened.
+func readObjects(r io.Reader) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	var objs []*unstructured.Unstructured
+	header := false
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) && header {
+				return objs, nil
+			}
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("the input is empty, it was not written by export")
+			}
+			return nil, fmt.Errorf("unable to decode input: %v", err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if !header {
+			if obj.GetKind() != exportKind {
+				return nil, fmt.Errorf("the input does not start with a %s header, it was not written by export", exportKind)
+			}
+			if obj.GetAPIVersion() != exportAPIVersion {
+				return nil, fmt.Errorf("unsupported export version %q, must be %s", obj.GetAPIVersion(), exportAPIVersion)
+			}
+			header = true
+			continue
+		}
+		items := []*unstructured.Unstructured{obj}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, err
+			}
+			items = items[:0]
+			for i := range list.Items {
+				items = append(items, &list.Items[i])
+			}
+		}
+		for _, item := range items {
+			if !isBackupKind(item.GroupVersionKind()) {
+				return nil, fmt.Errorf("unexpected %s %q in input", item.GroupVersionKind(), item.GetName())
+			}
+			objs = append(objs, stripServerFields(item))
+		}
+	}
+}
+
+func isBackupKind(gvk schema.GroupVersionKind) bool {
+	if gvk.Group != demo.GroupName {
+		return false
+	}
+	for _, resource := range backupResources {
+		if resource.Kind == gvk.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// stripServerFields drops the metadata the server sets. Owner references go
+// too: they refer to uids that are gone after a restore, and the garbage
+// collector deletes objects whose owners all no longer exist.
+func stripServerFields(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	obj.SetSelfLink("")
+	obj.SetGeneration(0)
+	obj.SetCreationTimestamp(metav1.Time{})
+	obj.SetManagedFields(nil)
+	obj.SetOwnerReferences(nil)
+	return obj
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"github.com/guodoliu/apiserver/pkg/generated/clientset/versioned"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeKubeconfig writes a kubeconfig for the test server at config and
+// returns its path.
+func writeKubeconfig(t *testing.T, config *rest.Config) string {
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters["demo"] = &clientcmdapi.Cluster{Server: config.Host, InsecureSkipTLSVerify: true}
+	kubeconfig.AuthInfos["demo"] = &clientcmdapi.AuthInfo{}
+	kubeconfig.Contexts["demo"] = &clientcmdapi.Context{Cluster: "demo", AuthInfo: "demo"}
+	kubeconfig.CurrentContext = "demo"
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := clientcmd.WriteToFile(*kubeconfig, path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// executeCommand runs cmd with args and returns what it wrote to stdout.
+func executeCommand(cmd *cobra.Command, args ...string) (string, error) {
+	out := &bytes.Buffer{}
+	cmd.SetArgs(args)
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	err := cmd.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+// createBackupObjects creates a Config, a Foo that uses it and FooDefaults.
+func createBackupObjects(t *testing.T, client versioned.Interface) {
+	ctx := context.Background()
+	config := &v1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: "shared"}, Spec: v1alpha1.ConfigSpec{Msg: "from the config"}}
+	if _, err := client.DemoV1alpha1().Configs("default").Create(ctx, config, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	foo := &v1beta1.Foo{
+		ObjectMeta: metav1.ObjectMeta{Name: "one", Labels: map[string]string{"app": "one"}},
+		Spec:       v1beta1.FooSpec{Image: "busybox:1.36", Config: v1beta1.FooConfig{Msg: "hello"}, ConfigName: "shared"},
+	}
+	if _, err := client.DemoV1beta1().Foos("default").Create(ctx, foo, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	defaults := &v1beta1.FooDefaults{ObjectMeta: metav1.ObjectMeta{Name: "tag"}, Spec: v1beta1.FooDefaultsSpec{ImageTag: "1.36"}}
+	if _, err := client.DemoV1beta1().FooDefaults("default").Create(ctx, defaults, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func deleteBackupObjects(t *testing.T, client versioned.Interface) {
+	ctx := context.Background()
+	if err := client.DemoV1beta1().FooDefaults("default").Delete(ctx, "tag", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DemoV1beta1().Foos("default").Delete(ctx, "one", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DemoV1alpha1().Configs("default").Delete(ctx, "shared", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestBackupRoundTrip exports the demo resources in either format, deletes
+// them and imports them again.
+func TestBackupRoundTrip(t *testing.T) {
+	config := startTestServer(t, "--feature-gates=ConfigResource=true")
+	client, err := versioned.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubeconfig := writeKubeconfig(t, config)
+	ctx := context.Background()
+
+	for _, format := range []string{formatYAML, formatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			createBackupObjects(t, client)
+			want, err := client.DemoV1beta1().Foos("default").Get(ctx, "one", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			file := filepath.Join(t.TempDir(), "export")
+			if _, err := executeCommand(newExportCommand(), "--kubeconfig", kubeconfig, "-o", format, "-f", file); err != nil {
+				t.Fatal(err)
+			}
+			deleteBackupObjects(t, client)
+
+			out, err := executeCommand(newImportCommand(), "--kubeconfig", kubeconfig, "-f", file)
+			if err != nil {
+				t.Fatalf("%v: %s", err, out)
+			}
+			if !strings.HasSuffix(out, "3 created, 0 updated, 0 skipped, 0 failed\n") {
+				t.Errorf("expected 3 objects to be created, got %s", out)
+			}
+			// Configs come first, since Foos refer to them
+			if i, j := strings.Index(out, "configs.demo.k8s.io"), strings.Index(out, "foos.demo.k8s.io"); i < 0 || j < i {
+				t.Errorf("expected the Config to be imported before the Foo, got %s", out)
+			}
+
+			got, err := client.DemoV1beta1().Foos("default").Get(ctx, "one", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got.Spec, want.Spec) || !reflect.DeepEqual(got.Labels, want.Labels) {
+				t.Errorf("expected the imported foo to match %v, got %v", want, got)
+			}
+			if got.UID == want.UID {
+				t.Errorf("expected the imported foo to get a new uid")
+			}
+			if config, err := client.DemoV1alpha1().Configs("default").Get(ctx, "shared", metav1.GetOptions{}); err != nil {
+				t.Error(err)
+			} else if config.Spec.Msg != "from the config" {
+				t.Errorf("expected the Config to be imported, got %v", config.Spec)
+			}
+			if defaults, err := client.DemoV1beta1().FooDefaults("default").Get(ctx, "tag", metav1.GetOptions{}); err != nil {
+				t.Error(err)
+			} else if defaults.Spec.ImageTag != "1.36" {
+				t.Errorf("expected the FooDefaults to be imported, got %v", defaults.Spec)
+			}
+			deleteBackupObjects(t, client)
+		})
+	}
+}
+
+// TestImportConflicts imports an export into the server it came from, after
+// the foo was changed or deleted there.
+func TestImportConflicts(t *testing.T) {
+	config := startTestServer(t, "--feature-gates=ConfigResource=true")
+	client, err := versioned.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	createBackupObjects(t, client)
+	kubeconfig := writeKubeconfig(t, config)
+	file := filepath.Join(t.TempDir(), "export.yaml")
+	if _, err := executeCommand(newExportCommand(), "--kubeconfig", kubeconfig, "-f", file); err != nil {
+		t.Fatal(err)
+	}
+	foos := client.DemoV1beta1().Foos("default")
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		args []string
+		// deleted deletes the foo before the import instead of changing it
+		deleted bool
+		wantErr bool
+		// wantOut is the line of the foo and the summary
+		wantOut []string
+		// wantMsg is the message of the foo after the import, empty if it
+		// does not exist
+		wantMsg string
+	}{
+		{
+			name:    "fail",
+			wantErr: true,
+			wantOut: []string{"foos.demo.k8s.io default/one failed: ", "0 created, 0 updated, 0 skipped, 3 failed\n"},
+			wantMsg: "changed",
+		},
+		{
+			name:    "skip",
+			args:    []string{"--conflict-policy=skip"},
+			wantOut: []string{"foos.demo.k8s.io default/one skipped\n", "0 created, 0 updated, 3 skipped, 0 failed\n"},
+			wantMsg: "changed",
+		},
+		{
+			name:    "overwrite",
+			args:    []string{"--conflict-policy=overwrite"},
+			wantOut: []string{"foos.demo.k8s.io default/one updated\n", "0 created, 3 updated, 0 skipped, 0 failed\n"},
+			wantMsg: "hello",
+		},
+		{
+			name:    "overwrite dry run",
+			args:    []string{"--conflict-policy=overwrite", "--dry-run"},
+			wantOut: []string{"foos.demo.k8s.io default/one updated (dry run)\n", "0 created, 3 updated, 0 skipped, 0 failed (dry run)\n"},
+			wantMsg: "changed",
+		},
+		{
+			name:    "create dry run",
+			args:    []string{"--conflict-policy=skip", "--dry-run"},
+			deleted: true,
+			wantOut: []string{"foos.demo.k8s.io default/one created (dry run)\n", "1 created, 0 updated, 2 skipped, 0 failed (dry run)\n"},
+		},
+		{
+			name:    "create",
+			args:    []string{"--conflict-policy=skip"},
+			deleted: true,
+			wantOut: []string{"foos.demo.k8s.io default/one created\n", "1 created, 0 updated, 2 skipped, 0 failed\n"},
+			wantMsg: "hello",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			foo, err := foos.Get(ctx, "one", metav1.GetOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				t.Fatal(err)
+			}
+			switch {
+			case tc.deleted && err == nil:
+				err = foos.Delete(ctx, "one", metav1.DeleteOptions{})
+			case tc.deleted:
+				err = nil
+			case err != nil:
+				foo = &v1beta1.Foo{ObjectMeta: metav1.ObjectMeta{Name: "one"}, Spec: v1beta1.FooSpec{Image: "busybox:1.36", Config: v1beta1.FooConfig{Msg: "changed"}}}
+				_, err = foos.Create(ctx, foo, metav1.CreateOptions{})
+			default:
+				foo.Spec.Config.Msg = "changed"
+				_, err = foos.Update(ctx, foo, metav1.UpdateOptions{})
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := executeCommand(newImportCommand(), append([]string{"--kubeconfig", kubeconfig, "-f", file}, tc.args...)...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected an error: %v, got %v: %s", tc.wantErr, err, out)
+			}
+			for _, want := range tc.wantOut {
+				if !strings.Contains(out, want) {
+					t.Errorf("expected the output to contain %q, got %s", want, out)
+				}
+			}
+			foo, err = foos.Get(ctx, "one", metav1.GetOptions{})
+			switch {
+			case len(tc.wantMsg) == 0 && !apierrors.IsNotFound(err):
+				t.Errorf("expected the foo not to exist, got %v, %v", foo, err)
+			case len(tc.wantMsg) > 0 && err != nil:
+				t.Error(err)
+			case len(tc.wantMsg) > 0 && foo.Spec.Config.Msg != tc.wantMsg:
+				t.Errorf("expected the foo to say %q, got %q", tc.wantMsg, foo.Spec.Config.Msg)
+			}
+		})
+	}
+}
+
+func TestReadObjects(t *testing.T) {
+	header := "kind: DemoExport\napiVersion: export.demo.k8s.io/v1\n"
+	foo := `apiVersion: demo.k8s.io/v1beta1
+kind: Foo
+metadata:
+  name: one
+  namespace: default
+  labels: {app: one}
+  resourceVersion: "42"
+  uid: 2a8d5f3e-0c8e-4d6c-9d2b-3f0e4c1b7a01
+  selfLink: /apis/demo.k8s.io/v1beta1/namespaces/default/foos/one
+  generation: 3
+  creationTimestamp: "2024-05-01T10:00:00Z"
+  managedFields:
+  - manager: kubectl
+    operation: Apply
+  ownerReferences:
+  - apiVersion: demo.k8s.io/v1alpha1
+    kind: Config
+    name: shared
+    uid: 6b1f0c2a-1f2e-4c3d-8e5f-7a9b0c1d2e3f
+spec:
+  image: busybox:1.36
+`
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+		want    []string
+	}{
+		{
+			name:  "YAML",
+			input: "---\n" + header + "---\n" + foo,
+			want:  []string{"Foo default/one"},
+		},
+		{
+			name:  "NDJSON",
+			input: `{"kind":"DemoExport","apiVersion":"export.demo.k8s.io/v1"}` + "\n" + `{"apiVersion":"demo.k8s.io/v1alpha1","kind":"Config","metadata":{"name":"shared","namespace":"default"}}` + "\n",
+			want:  []string{"Config default/shared"},
+		},
+		{
+			name:  "list",
+			input: header + "---\napiVersion: v1\nkind: List\nitems:\n- {apiVersion: demo.k8s.io/v1beta1, kind: Foo, metadata: {name: one}}\n- {apiVersion: demo.k8s.io/v1beta1, kind: FooDefaults, metadata: {name: tag}}\n",
+			want:  []string{"Foo /one", "FooDefaults /tag"},
+		},
+		{
+			name:  "header only",
+			input: header,
+		},
+		{
+			name:    "empty",
+			input:   "",
+			wantErr: "the input is empty",
+		},
+		{
+			name:    "no header",
+			input:   foo,
+			wantErr: "does not start with a DemoExport header",
+		},
+		{
+			name:    "unknown version",
+			input:   "kind: DemoExport\napiVersion: export.demo.k8s.io/v2\n---\n" + foo,
+			wantErr: `unsupported export version "export.demo.k8s.io/v2"`,
+		},
+		{
+			name:    "unexpected kind",
+			input:   header + "---\napiVersion: v1\nkind: ConfigMap\nmetadata: {name: one}\n",
+			wantErr: `unexpected /v1, Kind=ConfigMap "one"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			objs, err := readObjects(strings.NewReader(tc.input))
+			if len(tc.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, obj := range objs {
+				got = append(got, obj.GetKind()+" "+obj.GetNamespace()+"/"+obj.GetName())
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestStripServerFields(t *testing.T) {
+	objs, err := readObjects(strings.NewReader(`{"kind":"DemoExport","apiVersion":"export.demo.k8s.io/v1"}
+{"apiVersion":"demo.k8s.io/v1beta1","kind":"Foo","metadata":{"name":"one","namespace":"default","labels":{"app":"one"},"annotations":{"note":"kept"},"finalizers":["demo.k8s.io/cleanup"],"resourceVersion":"42","uid":"2a8d5f3e","selfLink":"/apis/demo.k8s.io/v1beta1/namespaces/default/foos/one","generation":3,"creationTimestamp":"2024-05-01T10:00:00Z","managedFields":[{"manager":"kubectl","operation":"Apply"}],"ownerReferences":[{"apiVersion":"demo.k8s.io/v1alpha1","kind":"Config","name":"shared","uid":"6b1f0c2a"}]},"spec":{"image":"busybox:1.36"}}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"name":        "one",
+		"namespace":   "default",
+		"labels":      map[string]interface{}{"app": "one"},
+		"annotations": map[string]interface{}{"note": "kept"},
+		"finalizers":  []interface{}{"demo.k8s.io/cleanup"},
+	}
+	if got := objs[0].Object["metadata"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected metadata %v, got %v", want, got)
+	}
+	if got := objs[0].Object["spec"]; !reflect.DeepEqual(got, map[string]interface{}{"image": "busybox:1.36"}) {
+		t.Errorf("expected the spec to be kept, got %v", got)
+	}
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/config"
+	configscheme "github.com/guodoliu/apiserver/pkg/apis/config/scheme"
+	configv1alpha1 "github.com/guodoliu/apiserver/pkg/apis/config/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/config/validation"
+	"github.com/spf13/pflag"
+	"io"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	genericoptions "k8s.io/apiserver/pkg/server/options"
+	"net"
+	"os"
+)
+
+// loadConfigFile decodes, defaults and validates the DemoServerConfiguration stored at path.
+func loadConfigFile(path string) (*config.DemoServerConfiguration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file %q: %v", path, err)
+	}
+	obj, gvk, err := configscheme.Codecs.UniversalDecoder().Decode(data, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode config file %q: %v", path, err)
+	}
+	cfg, ok := obj.(*config.DemoServerConfiguration)
+	if !ok {
+		return nil, fmt.Errorf("config file %q holds %v, expected DemoServerConfiguration", path, gvk)
+	}
+	if errs := validation.ValidateDemoServerConfiguration(cfg); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid config file %q: %v", path, errs.ToAggregate())
+	}
+	return cfg, nil
+}
+
+// printConfiguration writes cfg to w as a v1alpha1 YAML document.
+func printConfiguration(w io.Writer, cfg *config.DemoServerConfiguration) error {
+	info, ok := runtime.SerializerInfoForMediaType(configscheme.Codecs.SupportedMediaTypes(), runtime.ContentTypeYAML)
+	if !ok {
+		return fmt.Errorf("unable to locate encoder -- %q is not a supported media type", runtime.ContentTypeYAML)
+	}
+	encoder := configscheme.Codecs.EncoderForVersion(info.Serializer, configv1alpha1.SchemeGroupVersion)
+	return encoder.Encode(cfg, w)
+}
+
+// ApplyConfiguration copies cfg into the options. Settings whose flag was
+// given on the command line are left alone, so flags win over the file.
+func (o *Options) ApplyConfiguration(cfg *config.DemoServerConfiguration, fs *pflag.FlagSet) {
+	unset := func(name string) bool {
+		return !fs.Changed(name)
+	}
+
+	if unset("kubeconfig") {
+		o.KubeConfig = cfg.KubeConfig
+	}
+
+	if unset("bind-address") && len(cfg.Serving.BindAddress) > 0 {
+		o.SecureServing.BindAddress = net.ParseIP(cfg.Serving.BindAddress)
+	}
+	if unset("secure-port") {
+		o.SecureServing.BindPort = int(cfg.Serving.BindPort)
+	}
+	if unset("cert-dir") && len(cfg.Serving.CertDirectory) > 0 {
+		o.SecureServing.ServerCert.CertDirectory = cfg.Serving.CertDirectory
+	}
+	if unset("tls-cert-file") {
+		o.SecureServing.ServerCert.CertKey.CertFile = cfg.Serving.TLSCertFile
+	}
+	if unset("tls-private-key-file") {
+		o.SecureServing.ServerCert.CertKey.KeyFile = cfg.Serving.TLSPrivateKeyFile
+	}
+
+	if unset("enable-etcd-storage") {
+		o.EnableEtcdStorage = cfg.Storage.Enabled
+	}
+	if unset("etcd-servers") {
+		o.Etcd.StorageConfig.Transport.ServerList = cfg.Storage.EtcdServers
+	}
+	if unset("etcd-prefix") {
+		o.Etcd.StorageConfig.Prefix = cfg.Storage.Prefix
+	}
+	if unset("storage-media-type") {
+		o.Etcd.DefaultStorageMediaType = cfg.Storage.MediaType
+	}
+
+	if unset("embedded-etcd-data-dir") {
+		o.EmbeddedEtcd.DataDir = cfg.Storage.EmbeddedEtcd.DataDir
+	}
+	if unset("embedded-etcd-client-url") {
+		o.EmbeddedEtcd.ClientURL = cfg.Storage.EmbeddedEtcd.ClientURL
+	}
+	if unset("embedded-etcd-snapshot-dir") {
+		o.EmbeddedEtcd.SnapshotDir = cfg.Storage.EmbeddedEtcd.SnapshotDir
+	}
+	if unset("embedded-etcd-defrag-interval") {
+		o.EmbeddedEtcd.DefragInterval = cfg.Storage.EmbeddedEtcd.DefragInterval.Duration
+	}
+	if unset("encryption-provider-config") {
+		o.Etcd.EncryptionProviderConfigFilepath = cfg.Storage.Encryption.ProviderConfigFile
+	}
+	if unset("encryption-provider-config-automatic-reload") {
+		o.Etcd.EncryptionProviderConfigAutomaticReload = cfg.Storage.Encryption.AutomaticReload
+	}
+	if unset("watch-cache") {
+		o.Etcd.EnableWatchCache = cfg.Storage.WatchCache
+	}
+	if unset("watch-cache-sizes") {
+		o.Etcd.WatchCacheSizes = nil
+		for _, resource := range sets.List(sets.KeySet(cfg.Storage.WatchCacheSizes)) {
+			o.Etcd.WatchCacheSizes = append(o.Etcd.WatchCacheSizes, fmt.Sprintf("%s#%d", resource, cfg.Storage.WatchCacheSizes[resource]))
+		}
+	}
+
+	if unset("enable-auth") {
+		o.EnableAuth = cfg.Auth.Enabled
+	}
+	if unset("authentication-kubeconfig") {
+		o.Authentication.RemoteKubeConfigFile = cfg.Auth.AuthenticationKubeconfig
+	}
+	if unset("authorization-kubeconfig") {
+		o.Authorization.RemoteKubeConfigFile = cfg.Auth.AuthorizationKubeconfig
+	}
+
+	if unset("enable-admission") {
+		o.EnableAdmission = cfg.Admission.Enabled
+	}
+	if unset("enable-admission-plugins") {
+		o.Admission.EnablePlugins = cfg.Admission.EnablePlugins
+	}
+	if unset("disable-admission-plugins") {
+		o.Admission.DisablePlugins = cfg.Admission.DisablePlugins
+	}
+
+	if unset("profiling") {
+		o.Features.EnableProfiling = cfg.Features.EnableProfiling
+	}
+	if unset("contention-profiling") {
+		o.Features.EnableContentionProfiling = cfg.Features.EnableContentionProfiling
+	}
+	if unset("feature-gates") {
+		o.FeatureGates = cfg.Features.FeatureGates
+	}
+
+	if unset("enable-embedded-controllers") {
+		o.EnableControllers = cfg.Controllers.Enabled
+	}
+	if unset("concurrent-foo-syncs") {
+		o.ConcurrentFooSyncs = int(cfg.Controllers.ConcurrentFooSyncs)
+	}
+	leaderElection := cfg.Controllers.LeaderElection
+	if unset("leader-elect") {
+		o.LeaderElection.LeaderElect = leaderElection.LeaderElect
+	}
+	if unset("leader-elect-lease-duration") {
+		o.LeaderElection.LeaseDuration = leaderElection.LeaseDuration
+	}
+	if unset("leader-elect-renew-deadline") {
+		o.LeaderElection.RenewDeadline = leaderElection.RenewDeadline
+	}
+	if unset("leader-elect-retry-period") {
+		o.LeaderElection.RetryPeriod = leaderElection.RetryPeriod
+	}
+	if unset("leader-elect-resource-lock") {
+		o.LeaderElection.ResourceLock = leaderElection.ResourceLock
+	}
+	if unset("leader-elect-resource-name") {
+		o.LeaderElection.ResourceName = leaderElection.ResourceName
+	}
+	if unset("leader-elect-resource-namespace") {
+		o.LeaderElection.ResourceNamespace = leaderElection.ResourceNamespace
+	}
+
+	if unset("register-apiservice") {
+		o.RegisterAPIService = cfg.APIService.Register
+	}
+	if unset("apiservice-ca-file") {
+		o.APIServiceCAFile = cfg.APIService.CAFile
+	}
+	if unset("service-namespace") {
+		o.ServiceNamespace = cfg.APIService.ServiceNamespace
+	}
+	if unset("service-name") {
+		o.ServiceName = cfg.APIService.ServiceName
+	}
+	if unset("service-port") {
+		o.ServicePort = int(cfg.APIService.ServicePort)
+	}
+	if unset("server-replicas") {
+		o.ServerReplicas = int(cfg.APIService.Replicas)
+	}
+	if unset("unregister-apiservice-on-shutdown") {
+		o.UnregisterAPIServiceOnShutdown = cfg.APIService.UnregisterOnShutdown
+	}
+
+	if unset("foo-revision-history-limit") {
+		o.FooRevisionHistoryLimit = int(cfg.Foos.RevisionHistoryLimit)
+	}
+
+	if unset("runtime-config") && cfg.RuntimeConfig != nil {
+		o.APIEnablement.RuntimeConfig = cfg.RuntimeConfig
+	}
+}
+
+// Configuration returns the effective configuration of the options.
+func (o *Options) Configuration() *config.DemoServerConfiguration {
+	cfg := &config.DemoServerConfiguration{
+		KubeConfig: o.KubeConfig,
+		Serving: config.ServingConfiguration{
+			BindPort:          int32(o.SecureServing.BindPort),
+			CertDirectory:     o.SecureServing.ServerCert.CertDirectory,
+			TLSCertFile:       o.SecureServing.ServerCert.CertKey.CertFile,
+			TLSPrivateKeyFile: o.SecureServing.ServerCert.CertKey.KeyFile,
+		},
+		Storage: config.StorageConfiguration{
+			Enabled:     o.EnableEtcdStorage,
+			EtcdServers: o.Etcd.StorageConfig.Transport.ServerList,
+			Prefix:      o.Etcd.StorageConfig.Prefix,
+			MediaType:   o.Etcd.DefaultStorageMediaType,
+			EmbeddedEtcd: config.EmbeddedEtcdConfiguration{
+				DataDir:        o.EmbeddedEtcd.DataDir,
+				ClientURL:      o.EmbeddedEtcd.ClientURL,
+				SnapshotDir:    o.EmbeddedEtcd.SnapshotDir,
+				DefragInterval: metav1.Duration{Duration: o.EmbeddedEtcd.DefragInterval},
+			},
+			Encryption: config.EncryptionConfiguration{
+				ProviderConfigFile: o.Etcd.EncryptionProviderConfigFilepath,
+				AutomaticReload:    o.Etcd.EncryptionProviderConfigAutomaticReload,
+			},
+			WatchCache:      o.Etcd.EnableWatchCache,
+			WatchCacheSizes: watchCacheSizes(o.Etcd.WatchCacheSizes),
+		},
+		Auth: config.AuthConfiguration{
+			Enabled:                  o.EnableAuth,
+			AuthenticationKubeconfig: o.Authentication.RemoteKubeConfigFile,
+			AuthorizationKubeconfig:  o.Authorization.RemoteKubeConfigFile,
+		},
+		Admission: config.AdmissionConfiguration{
+			Enabled:        o.EnableAdmission,
+			EnablePlugins:  o.Admission.EnablePlugins,
+			DisablePlugins: o.Admission.DisablePlugins,
+		},
+		Features: config.FeaturesConfiguration{
+			EnableProfiling:           o.Features.EnableProfiling,
+			EnableContentionProfiling: o.Features.EnableContentionProfiling,
+			FeatureGates:              o.FeatureGates,
+		},
+		Controllers: config.ControllersConfiguration{
+			Enabled:            o.EnableControllers,
+			ConcurrentFooSyncs: int32(o.ConcurrentFooSyncs),
+			LeaderElection:     o.LeaderElection,
+		},
+		APIService: config.APIServiceConfiguration{
+			Register:             o.RegisterAPIService,
+			CAFile:               o.APIServiceCAFile,
+			ServiceNamespace:     o.ServiceNamespace,
+			ServiceName:          o.ServiceName,
+			ServicePort:          int32(o.ServicePort),
+			Replicas:             int32(o.ServerReplicas),
+			UnregisterOnShutdown: o.UnregisterAPIServiceOnShutdown,
+		},
+		Foos: config.FoosConfiguration{
+			RevisionHistoryLimit: int32(o.FooRevisionHistoryLimit),
+		},
+		RuntimeConfig: o.APIEnablement.RuntimeConfig,
+	}
+	if o.SecureServing.BindAddress != nil {
+		cfg.Serving.BindAddress = o.SecureServing.BindAddress.String()
+	}
+	return cfg
+}
+
+// watchCacheSizes turns --watch-cache-sizes into its config file form. Invalid
+// entries are dropped; Validate reports them.
+func watchCacheSizes(flags []string) map[string]int32 {
+	parsed, err := genericoptions.ParseWatchCacheSizes(flags)
+	if err != nil || len(parsed) == 0 {
+		return nil
+	}
+	sizes := make(map[string]int32, len(parsed))
+	for resource, size := range parsed {
+		sizes[resource.String()] = int32(size)
+	}
+	return sizes
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"github.com/guodoliu/apiserver/pkg/apis/config"
+	configscheme "github.com/guodoliu/apiserver/pkg/apis/config/scheme"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfigFile writes data to a config file and returns its path.
+func writeConfigFile(t *testing.T, data string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// printConfig runs the server command with --print-config and args, and
+// returns what it printed.
+func printConfig(args ...string) (string, error) {
+	cmd := NewDemoServerCommand(make(chan struct{}))
+	out := &bytes.Buffer{}
+	cmd.SetArgs(append([]string{"--print-config"}, args...))
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func decodeConfig(t *testing.T, data string) *config.DemoServerConfiguration {
+	obj, _, err := configscheme.Codecs.UniversalDecoder().Decode([]byte(data), nil, nil)
+	if err != nil {
+		t.Fatalf("unable to decode the printed config: %v\n%s", err, data)
+	}
+	return obj.(*config.DemoServerConfiguration)
+}
+
+func TestConfigFile(t *testing.T) {
+	tests := []struct {
+		name string
+		// file is the config file, none if empty
+		file    string
+		args    []string
+		wantErr []string
+		check   func(t *testing.T, cfg *config.DemoServerConfiguration)
+	}{
+		{
+			name: "flag defaults without a file",
+			check: func(t *testing.T, cfg *config.DemoServerConfiguration) {
+				if cfg.Serving.BindPort != 6443 || cfg.Storage.MediaType != "application/json" || cfg.Foos.RevisionHistoryLimit != 10 {
+					t.Errorf("expected the flag defaults, got %+v", cfg)
+				}
+			},
+		},
+		{
+			name: "loaded",
+			file: `apiVersion: config.demo.k8s.io/v1alpha1
+kind: DemoServerConfiguration
+kubeConfig: /etc/demo/kubeconfig
+serving:
+  bindAddress: 127.0.0.1
+  bindPort: 8443
+storage:
+  enabled: true
+  etcdServers: [http://etcd:2379]
+  prefix: /custom
+  mediaType: application/vnd.kubernetes.protobuf
+  watchCacheSizes:
+    foos.demo.k8s.io: 0
+controllers:
+  enabled: true
+  concurrentFooSyncs: 5
+foos:
+  revisionHistoryLimit: 3
+runtimeConfig:
+  demo.k8s.io/v1alpha1: "false"
+`,
+			check: func(t *testing.T, cfg *config.DemoServerConfiguration) {
+				if cfg.KubeConfig != "/etc/demo/kubeconfig" || cfg.Serving.BindAddress != "127.0.0.1" || cfg.Serving.BindPort != 8443 {
+					t.Errorf("expected the serving settings of the file, got %q and %+v", cfg.KubeConfig, cfg.Serving)
+				}
+				if !cfg.Storage.Enabled || strings.Join(cfg.Storage.EtcdServers, ",") != "http://etcd:2379" || cfg.Storage.Prefix != "/custom" ||
+					cfg.Storage.MediaType != "application/vnd.kubernetes.protobuf" || len(cfg.Storage.WatchCacheSizes) != 1 || cfg.Storage.WatchCacheSizes["foos.demo.k8s.io"] != 0 {
+					t.Errorf("expected the storage settings of the file, got %+v", cfg.Storage)
+				}
+				if !cfg.Controllers.Enabled || cfg.Controllers.ConcurrentFooSyncs != 5 || cfg.Foos.RevisionHistoryLimit != 3 {
+					t.Errorf("expected the controller settings of the file, got %+v and %+v", cfg.Controllers, cfg.Foos)
+				}
+				if cfg.RuntimeConfig["demo.k8s.io/v1alpha1"] != "false" {
+					t.Errorf("expected the runtime config of the file, got %v", cfg.RuntimeConfig)
+				}
+			},
+		},
+		{
+			name: "flags override the file",
+			file: `apiVersion: config.demo.k8s.io/v1alpha1
+kind: DemoServerConfiguration
+serving:
+  bindPort: 8443
+storage:
+  prefix: /custom
+controllers:
+  concurrentFooSyncs: 5
+foos:
+  revisionHistoryLimit: 3
+`,
+			args: []string{"--secure-port=9443", "--concurrent-foo-syncs=7", "--foo-revision-history-limit=0"},
+			check: func(t *testing.T, cfg *config.DemoServerConfiguration) {
+				if cfg.Serving.BindPort != 9443 || cfg.Controllers.ConcurrentFooSyncs != 7 || cfg.Foos.RevisionHistoryLimit != 0 {
+					t.Errorf("expected the flags to win, got port %d, syncs %d and limit %d",
+						cfg.Serving.BindPort, cfg.Controllers.ConcurrentFooSyncs, cfg.Foos.RevisionHistoryLimit)
+				}
+				if cfg.Storage.Prefix != "/custom" {
+					t.Errorf("expected the prefix of the file, got %q", cfg.Storage.Prefix)
+				}
+			},
+		},
+		{
+			name: "defaulted",
+			file: "apiVersion: config.demo.k8s.io/v1alpha1\nkind: DemoServerConfiguration\n",
+			// the defaults of the file replace the flag defaults
+			args: []string{"--profiling=false"},
+			check: func(t *testing.T, cfg *config.DemoServerConfiguration) {
+				if cfg.Serving.BindPort != 6443 || cfg.Storage.Prefix != "/registry/demo" || cfg.Storage.MediaType != "application/json" ||
+					cfg.Storage.EmbeddedEtcd.ClientURL != "http://127.0.0.1:2379" || !cfg.Storage.WatchCache {
+					t.Errorf("expected the default serving and storage settings, got %+v and %+v", cfg.Serving, cfg.Storage)
+				}
+				if cfg.Controllers.ConcurrentFooSyncs != 2 || cfg.Controllers.LeaderElection.ResourceName != "demo-apiserver-controllers" ||
+					cfg.Controllers.LeaderElection.ResourceNamespace != "kube-system" || cfg.Controllers.LeaderElection.LeaseDuration.Duration != 15*time.Second {
+					t.Errorf("expected the default controller settings, got %+v", cfg.Controllers)
+				}
+				if cfg.APIService.ServiceNamespace != "demo" || cfg.APIService.ServiceName != "apiserver" || cfg.APIService.ServicePort != 443 || cfg.APIService.Replicas != 1 {
+					t.Errorf("expected the default APIService settings, got %+v", cfg.APIService)
+				}
+				if cfg.Foos.RevisionHistoryLimit != 10 || cfg.Features.EnableProfiling {
+					t.Errorf("expected the default revision history limit and profiling off, got %+v and %+v", cfg.Foos, cfg.Features)
+				}
+			},
+		},
+		{
+			name: "invalid",
+			file: `apiVersion: config.demo.k8s.io/v1alpha1
+kind: DemoServerConfiguration
+serving:
+  bindAddress: localhost
+  bindPort: 70000
+storage:
+  mediaType: text/plain
+controllers:
+  enabled: true
+  concurrentFooSyncs: 0
+foos:
+  revisionHistoryLimit: -1
+`,
+			wantErr: []string{"invalid config file", "serving.bindAddress", "serving.bindPort", "storage.mediaType", "controllers.concurrentFooSyncs", "foos.revisionHistoryLimit"},
+		},
+		{
+			name:    "invalid even with the flags",
+			file:    "apiVersion: config.demo.k8s.io/v1alpha1\nkind: DemoServerConfiguration\nserving:\n  bindPort: 70000\n",
+			args:    []string{"--secure-port=9443"},
+			wantErr: []string{"serving.bindPort"},
+		},
+		{
+			name: "several replicas without a shared CA",
+			file: `apiVersion: config.demo.k8s.io/v1alpha1
+kind: DemoServerConfiguration
+apiService:
+  register: true
+  replicas: 2
+  unregisterOnShutdown: true
+`,
+			wantErr: []string{"apiService.caFile", "apiService.unregisterOnShutdown"},
+		},
+		{
+			name: "several replicas with a shared CA",
+			args: []string{"--register-apiservice", "--server-replicas=3", "--apiservice-ca-file=/etc/demo/ca.crt"},
+			check: func(t *testing.T, cfg *config.DemoServerConfiguration) {
+				if cfg.APIService.Replicas != 3 || cfg.APIService.CAFile != "/etc/demo/ca.crt" {
+					t.Errorf("expected the replicas and the CA of the flags, got %+v", cfg.APIService)
+				}
+			},
+		},
+		{
+			name:    "unknown version",
+			file:    "apiVersion: config.demo.k8s.io/v2\nkind: DemoServerConfiguration\n",
+			wantErr: []string{"unable to decode config file"},
+		},
+		{
+			name:    "other kind",
+			file:    "apiVersion: v1\nkind: ConfigMap\n",
+			wantErr: []string{"unable to decode config file"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args := tc.args
+			if len(tc.file) > 0 {
+				args = append([]string{"--config=" + writeConfigFile(t, tc.file)}, args...)
+			}
+			out, err := printConfig(args...)
+			if len(tc.wantErr) > 0 {
+				if err == nil {
+					t.Fatalf("expected an error, got\n%s", out)
+				}
+				for _, want := range tc.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("expected the error to contain %q, got %v", want, err)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.check(t, decodeConfig(t, out))
+		})
+	}
+}
+
+func TestConfigFileMissing(t *testing.T) {
+	if _, err := printConfig("--config=" + filepath.Join(t.TempDir(), "missing.yaml")); err == nil || !strings.Contains(err.Error(), "unable to read config file") {
+		t.Errorf("expected the missing file to be reported, got %v", err)
+	}
+}
+
+// TestPrintConfigRoundTrip feeds the printed configuration back in as the
+// config file, which must print the same again.
+func TestPrintConfigRoundTrip(t *testing.T) {
+	for name, args := range map[string][]string{
+		"defaults": nil,
+		"flags": {"--secure-port=9443", "--enable-etcd-storage", "--etcd-servers=http://etcd:2379", "--watch-cache-sizes=foos.demo.k8s.io#0",
+			"--enable-embedded-controllers", "--concurrent-foo-syncs=4", "--runtime-config=demo.k8s.io/v1alpha1=false",
+			"--feature-gates=ConfigResource=true", "--register-apiservice", "--service-name=demo-apiserver"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			printed, err := printConfig(args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reprinted, err := printConfig("--config=" + writeConfigFile(t, printed))
+			if err != nil {
+				t.Fatalf("unable to load the printed config: %v\n%s", err, printed)
+			}
+			if reprinted != printed {
+				t.Errorf("expected the printed config to round trip, got\n%s\nthen\n%s", printed, reprinted)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	apidiscoveryv2 "k8s.io/api/apidiscovery/v2"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestDiscoveryShortNamesAndCategories(t *testing.T) {
+	config := startTestServer(t, "--feature-gates=ConfigResource=true")
+	client, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type names struct {
+		shortNames []string
+		categories []string
+	}
+	tests := map[string]map[string]names{
+		"demo.k8s.io/v1beta1": {
+			"foos":         {[]string{"fo"}, []string{"demo", "all"}},
+			"foodefaults":  {[]string{"fdef"}, []string{"demo"}},
+			"foosummaries": {[]string{"fsum"}, []string{"demo"}},
+			// create-only, so kubectl get demo would fail on it
+			"fooreviews": {},
+		},
+		"demo.k8s.io/v1alpha1": {
+			"foos":    {[]string{"fo"}, []string{"demo", "all"}},
+			"configs": {[]string{"dcfg"}, []string{"demo"}},
+		},
+	}
+	for groupVersion, want := range tests {
+		t.Run(groupVersion, func(t *testing.T) {
+			resources, err := client.ServerResourcesForGroupVersion(groupVersion)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]names{}
+			for _, r := range resources.APIResources {
+				if _, ok := want[r.Name]; ok {
+					got[r.Name] = names{r.ShortNames, r.Categories}
+				}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+// TestAggregatedDiscovery checks that the aggregated discovery document at
+// /apis describes the demo group like the documents of each version do.
+func TestAggregatedDiscovery(t *testing.T) {
+	config := startTestServer(t, "--feature-gates=ConfigResource=true")
+	client, err := rest.HTTPClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodGet, config.Host+"/apis", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "application/json;g=apidiscovery.k8s.io;v=v2;as=APIGroupDiscoveryList")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %s", resp.Status)
+	}
+	aggregated := &apidiscoveryv2.APIGroupDiscoveryList{}
+	if err := json.NewDecoder(resp.Body).Decode(aggregated); err != nil {
+		t.Fatal(err)
+	}
+	if aggregated.Kind != "APIGroupDiscoveryList" {
+		t.Fatalf("expected an aggregated discovery document, got kind %q", aggregated.Kind)
+	}
+	var group *apidiscoveryv2.APIGroupDiscovery
+	for i := range aggregated.Items {
+		if aggregated.Items[i].Name == demo.GroupName {
+			group = &aggregated.Items[i]
+		}
+	}
+	if group == nil {
+		t.Fatalf("%s is missing from the aggregated discovery document", demo.GroupName)
+	}
+
+	var versions []string
+	for _, version := range group.Versions {
+		versions = append(versions, version.Version)
+	}
+	if want := []string{"v1beta1", "v1alpha1"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("expected versions %v in order of preference, got %v", want, versions)
+	}
+
+	legacy, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range group.Versions {
+		t.Run(version.Version, func(t *testing.T) {
+			if version.Freshness != apidiscoveryv2.DiscoveryFreshnessCurrent {
+				t.Errorf("expected freshness %s, got %s", apidiscoveryv2.DiscoveryFreshnessCurrent, version.Freshness)
+			}
+			got := map[string]string{}
+			for _, r := range version.Resources {
+				kind := ""
+				if r.ResponseKind != nil {
+					kind = r.ResponseKind.Kind
+				}
+				got[r.Resource] = describe(kind, string(r.Scope) == string(apidiscoveryv2.ScopeNamespace), r.SingularResource, r.Verbs, r.ShortNames, r.Categories)
+				for _, sub := range r.Subresources {
+					subKind := ""
+					if sub.ResponseKind != nil {
+						subKind = sub.ResponseKind.Kind
+					}
+					got[r.Resource+"/"+sub.Subresource] = describe(subKind, string(r.Scope) == string(apidiscoveryv2.ScopeNamespace), "", sub.Verbs, nil, nil)
+				}
+			}
+
+			resources, err := legacy.ServerResourcesForGroupVersion(demo.GroupName + "/" + version.Version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]string{}
+			for _, r := range resources.APIResources {
+				singular := r.SingularName
+				if strings.Contains(r.Name, "/") {
+					singular = ""
+				}
+				want[r.Name] = describe(r.Kind, r.Namespaced, singular, r.Verbs, r.ShortNames, r.Categories)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("aggregated discovery differs from /apis/%s/%s:\naggregated: %v\nlegacy:     %v", demo.GroupName, version.Version, got, want)
+			}
+		})
+	}
+}
+
+// describe summarizes a discovered resource so that both discovery formats
+// can be compared.
+func describe(kind string, namespaced bool, singular string, verbs, shortNames, categories []string) string {
+	verbs = append([]string(nil), verbs...)
+	sort.Strings(verbs)
+	return strings.Join([]string{
+		"kind=" + kind,
+		"namespaced=" + map[bool]string{true: "true", false: "false"}[namespaced],
+		"singular=" + singular,
+		"verbs=" + strings.Join(verbs, ","),
+		"shortNames=" + strings.Join(shortNames, ","),
+		"categories=" + strings.Join(categories, ","),
+	}, " ")
+}
+
+// TestFeatureGatedResources checks that foos/status and configs are only
+// served while their feature gates are on.
+func TestFeatureGatedResources(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags []string
+		want  bool
+	}{
+		{name: "gates off"},
+		{name: "gates on", flags: []string{"--feature-gates=FooStatusSubresource=true,ConfigResource=true"}, want: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := discovery.NewDiscoveryClientForConfig(startTestServer(t, tc.flags...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			served := map[string]bool{}
+			for _, groupVersion := range []string{"demo.k8s.io/v1alpha1", "demo.k8s.io/v1beta1"} {
+				resources, err := client.ServerResourcesForGroupVersion(groupVersion)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, r := range resources.APIResources {
+					served[groupVersion+" "+r.Name] = true
+				}
+			}
+			for _, resource := range []string{"demo.k8s.io/v1alpha1 foos/status", "demo.k8s.io/v1beta1 foos/status", "demo.k8s.io/v1alpha1 configs"} {
+				if served[resource] != tc.want {
+					t.Errorf("expected %s to be served: %v, got %v", resource, tc.want, served[resource])
+				}
+			}
+			if !served["demo.k8s.io/v1beta1 foos"] {
+				t.Errorf("expected foos to be served regardless of the gates")
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/generated/clientset/versioned"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// encryptionKey returns a base64 encoded key of 32 bytes, the size every
+// provider accepts.
+func encryptionKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+// writeEncryptionConfig writes a provider config for foos and configs and
+// returns the flag that points the server at it. providers are the entries of
+// the providers list, in order.
+func writeEncryptionConfig(t *testing.T, providers ...string) string {
+	t.Helper()
+	config := `apiVersion: apiserver.config.k8s.io/v1
+kind: EncryptionConfiguration
+resources:
+  - resources:
+      - foos.demo.k8s.io
+      - configs.demo.k8s.io
+    providers:
+` + strings.Join(providers, "\n") + "\n"
+	path := filepath.Join(t.TempDir(), "encryption-config.yaml")
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return "--encryption-provider-config=" + path
+}
+
+// provider returns a providers entry with the given keys, named by their
+// byte, first key first.
+func provider(name string, keys ...byte) string {
+	entry := fmt.Sprintf("      - %s:\n          keys:", name)
+	for _, key := range keys {
+		entry += fmt.Sprintf("\n            - name: key%c\n              secret: %s", key, encryptionKey(key))
+	}
+	return entry
+}
+
+const identityProvider = "      - identity: {}"
+
+// rawValue returns what etcd at clientURL holds for the demo object of the
+// resource in namespace default.
+func rawValue(t *testing.T, clientURL, resource, name string) string {
+	t.Helper()
+	client, err := clientv3.New(clientv3.Config{Endpoints: []string{clientURL}, DialTimeout: 10 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, err := client.Get(ctx, defaultEtcdPathPrefix+"/", clientv3.WithPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kv := range resp.Kvs {
+		if strings.HasSuffix(string(kv.Key), "/"+resource+"/default/"+name) {
+			return string(kv.Value)
+		}
+	}
+	t.Fatalf("no %s default/%s in etcd", resource, name)
+	return ""
+}
+
+func TestEncryptionAtRest(t *testing.T) {
+	for _, name := range []string{"aescbc", "aesgcm", "secretbox"} {
+		t.Run(name, func(t *testing.T) {
+			clientURL := fmt.Sprintf("http://127.0.0.1:%d", freePort(t))
+			client, err := versioned.NewForConfig(startTestServer(t,
+				"--embedded-etcd-client-url="+clientURL,
+				"--feature-gates=ConfigResource=true",
+				writeEncryptionConfig(t, provider(name, '1'), identityProvider),
+			))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			foo := &v1alpha1.Foo{
+				ObjectMeta: metav1.ObjectMeta{Name: "one"},
+				Spec:       v1alpha1.FooSpec{Image: "busybox:1.36", Config: v1alpha1.FooConfig{Msg: "secret foo message"}},
+			}
+			if _, err := client.DemoV1alpha1().Foos("default").Create(ctx, foo, metav1.CreateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			config := &v1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: "one"}, Spec: v1alpha1.ConfigSpec{Msg: "secret config message"}}
+			if _, err := client.DemoV1alpha1().Configs("default").Create(ctx, config, metav1.CreateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+
+			prefix := "k8s:enc:" + name + ":v1:key1:"
+			for resource, msg := range map[string]string{"foos": "secret foo message", "configs": "secret config message"} {
+				value := rawValue(t, clientURL, resource, "one")
+				if !strings.HasPrefix(value, prefix) {
+					t.Errorf("expected %s to be stored with the prefix %q, got %q", resource, prefix, value[:min(len(value), len(prefix))])
+				}
+				if strings.Contains(value, msg) || strings.Contains(value, "busybox") {
+					t.Errorf("expected %s to be stored encrypted, got %q", resource, value)
+				}
+			}
+
+			// and read back decrypted
+			if got, err := client.DemoV1alpha1().Foos("default").Get(ctx, "one", metav1.GetOptions{}); err != nil {
+				t.Error(err)
+			} else if got.Spec.Config.Msg != "secret foo message" {
+				t.Errorf("expected the message to be decrypted, got %q", got.Spec.Config.Msg)
+			}
+			if got, err := client.DemoV1alpha1().Configs("default").Get(ctx, "one", metav1.GetOptions{}); err != nil {
+				t.Error(err)
+			} else if got.Spec.Msg != "secret config message" {
+				t.Errorf("expected the message to be decrypted, got %q", got.Spec.Msg)
+			}
+		})
+	}
+}
+
+// TestEncryptionKeyRotation follows the rotation steps of
+// artifacts/encryption.yaml across restarts of a server on the same etcd.
+func TestEncryptionKeyRotation(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), "etcd")
+	clientURL := fmt.Sprintf("http://127.0.0.1:%d", freePort(t))
+	start := func(t *testing.T, providers ...string) (*rest.Config, *versioned.Clientset) {
+		config := startTestServer(t,
+			"--embedded-etcd-data-dir="+dataDir,
+			"--embedded-etcd-client-url="+clientURL,
+			writeEncryptionConfig(t, providers...),
+		)
+		client, err := versioned.NewForConfig(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return config, client
+	}
+	expectKey := func(t *testing.T, key string) {
+		t.Helper()
+		if value := rawValue(t, clientURL, "foos", "one"); !strings.HasPrefix(value, "k8s:enc:aescbc:v1:"+key+":") {
+			t.Errorf("expected the foo to be encrypted with %s, got %q", key, value[:min(len(value), 30)])
+		}
+	}
+	get := func(t *testing.T, client *versioned.Clientset) {
+		t.Helper()
+		foo, err := client.DemoV1alpha1().Foos("default").Get(context.Background(), "one", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if foo.Spec.Config.Msg != "secret foo message" {
+			t.Errorf("expected the message to be decrypted, got %q", foo.Spec.Config.Msg)
+		}
+	}
+
+	t.Run("key1", func(t *testing.T) {
+		_, client := start(t, provider("aescbc", '1'))
+		foo := &v1alpha1.Foo{
+			ObjectMeta: metav1.ObjectMeta{Name: "one"},
+			Spec:       v1alpha1.FooSpec{Image: "busybox:1.36", Config: v1alpha1.FooConfig{Msg: "secret foo message"}},
+		}
+		if _, err := client.DemoV1alpha1().Foos("default").Create(context.Background(), foo, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		expectKey(t, "key1")
+	})
+	if t.Failed() {
+		return
+	}
+
+	t.Run("key2 first and migrated", func(t *testing.T) {
+		config, client := start(t, provider("aescbc", '2', '1'))
+		get(t, client)
+		expectKey(t, "key1")
+
+		dynamicClient, err := dynamic.NewForConfig(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		o := &migrateOptions{PageSize: 100}
+		limiter := flowcontrol.NewFakeAlwaysRateLimiter()
+		if err := o.migrate(context.Background(), dynamicClient.Resource(v1alpha1.SchemeGroupVersion.WithResource("foos")),
+			limiter, &resourceMigration{}, func() error { return nil }); err != nil {
+			t.Fatal(err)
+		}
+		expectKey(t, "key2")
+	})
+	if t.Failed() {
+		return
+	}
+
+	t.Run("key1 dropped", func(t *testing.T) {
+		_, client := start(t, provider("aescbc", '2'))
+		get(t, client)
+	})
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	configv1alpha1 "github.com/guodoliu/apiserver/pkg/apis/config/v1alpha1"
+	"github.com/spf13/pflag"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+	"io"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	embeddedEtcdStartTimeout       = time.Minute
+	embeddedEtcdMaintenanceTimeout = 2 * time.Minute
+)
+
+// EmbeddedEtcdOptions runs a single member etcd inside the server process.
+type EmbeddedEtcdOptions struct {
+	DataDir        string
+	ClientURL      string
+	SnapshotDir    string
+	DefragInterval time.Duration
+}
+
+func NewEmbeddedEtcdOptions() *EmbeddedEtcdOptions {
+	return &EmbeddedEtcdOptions{
+		ClientURL: configv1alpha1.DefaultEmbeddedEtcdURL,
+	}
+}
+
+func (o *EmbeddedEtcdOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.DataDir, "embedded-etcd-data-dir", o.DataDir, "If set, run etcd inside the server with its data in this directory and store demo resources in it. "+
+		"Turns on etcd storage and may not be combined with --etcd-servers")
+	fs.StringVar(&o.ClientURL, "embedded-etcd-client-url", o.ClientURL, "The URL the embedded etcd serves clients on")
+	fs.StringVar(&o.SnapshotDir, "embedded-etcd-snapshot-dir", o.SnapshotDir, "If set, save an etcd snapshot to this directory when the server shuts down")
+	fs.DurationVar(&o.DefragInterval, "embedded-etcd-defrag-interval", o.DefragInterval, "How often to defragment the embedded etcd. Zero turns it off")
+}
+
+func (o *EmbeddedEtcdOptions) Enabled() bool {
+	return len(o.DataDir) > 0
+}
+
+func (o *EmbeddedEtcdOptions) Validate() []error {
+	if !o.Enabled() {
+		return nil
+	}
+	var errs []error
+	if u, err := url.Parse(o.ClientURL); err != nil || u.Scheme != "http" || len(u.Host) == 0 {
+		errs = append(errs, fmt.Errorf("--embedded-etcd-client-url must be an http URL, got %q", o.ClientURL))
+	}
+	if o.DefragInterval < 0 {
+		errs = append(errs, fmt.Errorf("--embedded-etcd-defrag-interval must not be negative"))
+	}
+	return errs
+}
+
+// Start runs etcd and defragments it every DefragInterval until stopCh is
+// closed. The returned func snapshots etcd, if asked to, and stops it; call it
+// once the server no longer uses etcd.
+func (o *EmbeddedEtcdOptions) Start(stopCh <-chan struct{}) (func(), error) {
+	stopEtcd, err := startEmbeddedEtcd(o)
+	if err != nil {
+		return nil, err
+	}
+	klog.InfoS("Started embedded etcd", "dataDir", o.DataDir, "clientURL", o.ClientURL)
+
+	if o.DefragInterval > 0 {
+		go wait.Until(func() {
+			if err := o.defragment(); err != nil {
+				klog.ErrorS(err, "Failed to defragment embedded etcd")
+			}
+		}, o.DefragInterval, stopCh)
+	}
+
+	return func() {
+		if len(o.SnapshotDir) > 0 {
+			if err := o.snapshot(); err != nil {
+				klog.ErrorS(err, "Failed to snapshot embedded etcd")
+			}
+		}
+		stopEtcd()
+	}, nil
+}
+
+func (o *EmbeddedEtcdOptions) client() (*clientv3.Client, error) {
+	return clientv3.New(clientv3.Config{
+		Endpoints:   []string{o.ClientURL},
+		DialTimeout: 5 * time.Second,
+	})
+}
+
+func (o *EmbeddedEtcdOptions) defragment() error {
+	client, err := o.client()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), embeddedEtcdMaintenanceTimeout)
+	defer cancel()
+	_, err = client.Defragment(ctx, o.ClientURL)
+	return err
+}
+
+// snapshot writes the etcd database to a new file in SnapshotDir. The file
+// only appears under its final name once it is complete.
+func (o *EmbeddedEtcdOptions) snapshot() error {
+	client, err := o.client()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), embeddedEtcdMaintenanceTimeout)
+	defer cancel()
+	rc, err := client.Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := os.MkdirAll(o.SnapshotDir, 0700); err != nil {
+		return err
+	}
+	path := filepath.Join(o.SnapshotDir, fmt.Sprintf("demo-%s.db", time.Now().UTC().Format("20060102T150405Z")))
+	f, err := os.CreateTemp(o.SnapshotDir, ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, rc); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return err
+	}
+	klog.InfoS("Saved embedded etcd snapshot", "path", path)
+	return nil
+}
+
+// startEmbeddedEtcd starts a single member etcd and waits until it serves
+// clients. The returned func stops it.
+func startEmbeddedEtcd(o *EmbeddedEtcdOptions) (func(), error) {
+	clientURL, err := url.Parse(o.ClientURL)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := embed.NewConfig()
+	cfg.Name = "demo-apiserver"
+	cfg.Dir = o.DataDir
+	cfg.ListenClientUrls = []url.URL{*clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{*clientURL}
+	// a single member has no peers to talk to, so nothing listens on the
+	// advertised peer URL and the etcd of artifacts/app.yaml keeps port 2380
+	cfg.ListenPeerUrls = nil
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+	cfg.LogLevel = "warn"
+
+	e, err := embed.StartEtcd(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start embedded etcd: %v", err)
+	}
+	select {
+	case <-e.Server.ReadyNotify():
+		return e.Close, nil
+	case err := <-e.Err():
+		e.Close()
+		return nil, fmt.Errorf("embedded etcd failed: %v", err)
+	case <-time.After(embeddedEtcdStartTimeout):
+		e.Close()
+		return nil, fmt.Errorf("embedded etcd did not become ready within %v", embeddedEtcdStartTimeout)
+	}
+}
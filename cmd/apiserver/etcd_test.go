@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go.etcd.io/bbolt"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestEmbeddedEtcd returns options for an embedded etcd in a temporary
+// directory, serving on a free local port.
+func newTestEmbeddedEtcd(t *testing.T) *EmbeddedEtcdOptions {
+	t.Helper()
+	o := NewEmbeddedEtcdOptions()
+	o.DataDir = filepath.Join(t.TempDir(), "etcd")
+	o.ClientURL = fmt.Sprintf("http://127.0.0.1:%d", freePort(t))
+	return o
+}
+
+func TestEmbeddedEtcdSnapshotOnShutdown(t *testing.T) {
+	o := newTestEmbeddedEtcd(t)
+	o.SnapshotDir = filepath.Join(t.TempDir(), "snapshots")
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	stop, err := o.Start(stopCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := o.client()
+	if err != nil {
+		stop()
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := client.Put(ctx, "/registry/demo/foos/default/one", "one"); err != nil {
+		client.Close()
+		stop()
+		t.Fatal(err)
+	}
+	client.Close()
+	stop()
+
+	entries, err := os.ReadDir(o.SnapshotDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || !strings.HasPrefix(entries[0].Name(), "demo-") || !strings.HasSuffix(entries[0].Name(), ".db") {
+		t.Fatalf("expected a single demo-*.db snapshot and no leftovers, got %v", entries)
+	}
+
+	// a snapshot is an etcd backend database holding every key
+	db, err := bbolt.Open(filepath.Join(o.SnapshotDir, entries[0].Name()), 0400, &bbolt.Options{ReadOnly: true, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	found := false
+	if err := db.View(func(tx *bbolt.Tx) error {
+		keys := tx.Bucket([]byte("key"))
+		if keys == nil {
+			return fmt.Errorf("no key bucket in the snapshot")
+		}
+		return keys.ForEach(func(_, v []byte) error {
+			found = found || strings.Contains(string(v), "/registry/demo/foos/default/one")
+			return nil
+		})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("the snapshot does not hold the key written before shutdown")
+	}
+}
+
+func TestEmbeddedEtcdDefragment(t *testing.T) {
+	o := newTestEmbeddedEtcd(t)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	stop, err := o.Start(stopCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	client, err := o.client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// fill the database, then free the space by deleting and compacting
+	value := strings.Repeat("x", 64*1024)
+	for i := 0; i < 100; i++ {
+		if _, err := client.Put(ctx, fmt.Sprintf("/registry/demo/foos/default/%d", i), value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resp, err := client.Delete(ctx, "/registry/demo/foos/", clientv3.WithPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Compact(ctx, resp.Header.Revision, clientv3.WithCompactPhysical()); err != nil {
+		t.Fatal(err)
+	}
+	before, err := client.Status(ctx, o.ClientURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := o.defragment(); err != nil {
+		t.Fatal(err)
+	}
+	after, err := client.Status(ctx, o.ClientURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.DbSize >= before.DbSize {
+		t.Errorf("expected defragmenting to shrink the database, got %d bytes before and %d after", before.DbSize, after.DbSize)
+	}
+}
+
+// TestEmbeddedEtcdNoPeerListener runs two embedded etcds at once, which only
+// works as long as neither listens on the default peer port.
+func TestEmbeddedEtcdNoPeerListener(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	for i := 0; i < 2; i++ {
+		stop, err := newTestEmbeddedEtcd(t).Start(stopCh)
+		if err != nil {
+			t.Fatalf("unable to start embedded etcd %d: %v", i, err)
+		}
+		defer stop()
+	}
+}
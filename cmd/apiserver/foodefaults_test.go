@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"github.com/guodoliu/apiserver/pkg/generated/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type warningRecorder struct {
+	mu       sync.Mutex
+	warnings []string
+}
+
+func (r *warningRecorder) HandleWarningHeader(_ int, _ string, text string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.warnings = append(r.warnings, text)
+}
+
+// TestFooDefaultsWithoutAdmission checks that writing FooDefaults to a server
+// that runs without admission warns that they are not applied.
+func TestFooDefaultsWithoutAdmission(t *testing.T) {
+	config := startTestServer(t)
+	recorder := &warningRecorder{}
+	config.WarningHandler = recorder
+	client, err := versioned.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaults := &v1beta1.FooDefaults{ObjectMeta: metav1.ObjectMeta{Name: "tag"}, Spec: v1beta1.FooDefaultsSpec{ImageTag: "1.36"}}
+	if _, err := client.DemoV1beta1().FooDefaults("default").Create(context.Background(), defaults, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.warnings) != 1 || !strings.Contains(recorder.warnings[0], "FooDefaults are not applied") {
+		t.Errorf("expected a warning that FooDefaults are not applied, got %q", recorder.warnings)
+	}
+}
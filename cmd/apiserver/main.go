@@ -19,16 +19,27 @@ package main
 import (
 	"flag"
 	"fmt"
+	"github.com/guodoliu/apiserver/pkg/admission/demoinitializer"
 	"github.com/guodoliu/apiserver/pkg/admission/disallow"
+	"github.com/guodoliu/apiserver/pkg/admission/foodefaults"
+	configv1alpha1 "github.com/guodoliu/apiserver/pkg/apis/config/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/config/validation"
 	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
 	"github.com/guodoliu/apiserver/pkg/apiserver"
-	"github.com/guodoliu/apiserver/pkg/generated/openapi"
+	clientset "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned"
+	informers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions"
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
 	openapinamer "k8s.io/apiserver/pkg/endpoints/openapi"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
 	"k8s.io/apiserver/pkg/server/storage"
 	"k8s.io/apiserver/pkg/storage/storagebackend"
 	"k8s.io/apiserver/pkg/util/feature"
@@ -36,12 +47,17 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/component-base/cli"
 	cliflag "k8s.io/component-base/cli/flag"
+	componentbaseconfig "k8s.io/component-base/config"
+	componentbaseoptions "k8s.io/component-base/config/options"
 	"k8s.io/component-base/term"
 	"k8s.io/klog/v2"
 	"net"
 	"os"
+	"strings"
+	"time"
 
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	genericoptions "k8s.io/apiserver/pkg/server/options"
@@ -49,13 +65,23 @@ import (
 
 const defaultEtcdPathPrefix = "/registry/demo"
 
+// cachedResources are the resources --watch-cache-sizes may name
+var cachedResources = sets.New(demo.Resource("foos"), demo.Resource("foorevisions"), demo.Resource("configs"), demo.Resource("foodefaults"))
+
 type Options struct {
+	// ConfigFile is a DemoServerConfiguration file; flags given on the command line override it
+	ConfigFile  string
+	PrintConfig bool
+
 	SecureServing *genericoptions.SecureServingOptionsWithLoopback
 	KubeConfig    string
 	Features      *genericoptions.FeatureOptions
+	// FeatureGates are applied to the default feature gate in Complete
+	FeatureGates map[string]bool
 
 	EnableEtcdStorage bool
 	Etcd              *genericoptions.EtcdOptions
+	EmbeddedEtcd      *EmbeddedEtcdOptions
 
 	EnableAuth     bool
 	Authentication *genericoptions.DelegatingAuthenticationOptions
@@ -63,29 +89,81 @@ type Options struct {
 
 	EnableAdmission bool
 	Admission       *genericoptions.AdmissionOptions
+
+	APIEnablement *genericoptions.APIEnablementOptions
+
+	// EnableControllers runs the demo controllers in the server instead of the manager
+	EnableControllers  bool
+	ConcurrentFooSyncs int
+	LeaderElection     componentbaseconfig.LeaderElectionConfiguration
+
+	// RegisterAPIService keeps the demo APIServices of the backing cluster up to date
+	RegisterAPIService             bool
+	APIServiceCAFile               string
+	ServiceNamespace               string
+	ServiceName                    string
+	ServicePort                    int
+	ServerReplicas                 int
+	UnregisterAPIServiceOnShutdown bool
+
+	// FooRevisionHistoryLimit is the number of revisions kept per foo
+	FooRevisionHistoryLimit int
 }
 
 func (o *Options) Flags() (fs cliflag.NamedFlagSets) {
 	msfs := fs.FlagSet("demo.dev-server")
+	msfs.StringVar(&o.ConfigFile, "config", o.ConfigFile, "The path to a DemoServerConfiguration file. Flags set on the command line override values from this file.")
+	msfs.BoolVar(&o.PrintConfig, "print-config", o.PrintConfig, "If true, print the effective configuration and exit")
 	msfs.StringVar(&o.KubeConfig, "kubeconfig", o.KubeConfig, "The path to the kubeconfig used to connect to the Kubernetes API server (defaults to in-cluster config)")
 
 	o.SecureServing.AddFlags(fs.FlagSet("apiserver secure serving"))
 	o.Features.AddFlags(fs.FlagSet("features"))
+	fs.FlagSet("features").Var(cliflag.NewMapStringBool(&o.FeatureGates), "feature-gates", "A set of key=value pairs that describe feature gates for alpha/experimental features. "+
+		"Options are:\n"+strings.Join(feature.DefaultFeatureGate.KnownFeatures(), "\n"))
 
 	msfs.BoolVar(&o.EnableEtcdStorage, "enable-etcd-storage", false, "If true, enable etcd storage")
 	o.Etcd.AddFlags(fs.FlagSet("Etcd"))
+	o.EmbeddedEtcd.AddFlags(fs.FlagSet("Etcd"))
 
 	msfs.BoolVar(&o.EnableAuth, "enable-auth", o.EnableAuth, "If true, enable authentication")
 	o.Authentication.AddFlags(fs.FlagSet("apiserver authentication"))
 	o.Authorization.AddFlags(fs.FlagSet("apiserver authorization"))
 
 	msfs.BoolVar(&o.EnableAdmission, "enable-admission", o.EnableAdmission, "If true, enable admission plugins")
+	msfs.IntVar(&o.FooRevisionHistoryLimit, "foo-revision-history-limit", o.FooRevisionHistoryLimit, "The number of revisions kept per Foo for the foos/history and foos/rollback subresources. Zero turns the history off")
+
+	o.APIEnablement.AddFlags(fs.FlagSet("API enablement"))
+
+	cfs := fs.FlagSet("controllers")
+	cfs.BoolVar(&o.EnableControllers, "enable-embedded-controllers", o.EnableControllers, "If true, run the demo controllers in this server instead of a separate manager")
+	cfs.IntVar(&o.ConcurrentFooSyncs, "concurrent-foo-syncs", o.ConcurrentFooSyncs, "The number of Foos that are allowed to sync concurrently")
+	componentbaseoptions.BindLeaderElectionFlags(&o.LeaderElection, cfs)
+
+	afs := fs.FlagSet("APIService registration")
+	afs.BoolVar(&o.RegisterAPIService, "register-apiservice", o.RegisterAPIService, "If true, create or update an APIService for every served demo version in the backing cluster")
+	afs.StringVar(&o.APIServiceCAFile, "apiservice-ca-file", o.APIServiceCAFile, "The CA bundle put into the APIServices; it is reloaded when it changes. Defaults to the serving certificate")
+	afs.StringVar(&o.ServiceNamespace, "service-namespace", o.ServiceNamespace, "The namespace of the Service in front of this server")
+	afs.StringVar(&o.ServiceName, "service-name", o.ServiceName, "The name of the Service in front of this server")
+	afs.IntVar(&o.ServicePort, "service-port", o.ServicePort, "The port of the Service in front of this server")
+	afs.IntVar(&o.ServerReplicas, "server-replicas", o.ServerReplicas, "The number of replicas of this server behind the Service. More than one require --apiservice-ca-file")
+	afs.BoolVar(&o.UnregisterAPIServiceOnShutdown, "unregister-apiservice-on-shutdown", o.UnregisterAPIServiceOnShutdown, "If true, delete the APIServices when the server shuts down. Only allowed with a single replica")
 	return fs
 }
 
 func (o *Options) Complete() error {
+	if err := feature.DefaultMutableFeatureGate.SetFromMap(o.FeatureGates); err != nil {
+		return err
+	}
+	if o.EmbeddedEtcd.Enabled() {
+		if len(o.Etcd.StorageConfig.Transport.ServerList) > 0 {
+			return fmt.Errorf("--etcd-servers may not be combined with --embedded-etcd-data-dir")
+		}
+		o.EnableEtcdStorage = true
+		o.Etcd.StorageConfig.Transport.ServerList = []string{o.EmbeddedEtcd.ClientURL}
+	}
 	disallow.Register(o.Admission.Plugins)
-	o.Admission.RecommendedPluginOrder = append(o.Admission.RecommendedPluginOrder, "DisallowFoo")
+	foodefaults.Register(o.Admission.Plugins)
+	o.Admission.RecommendedPluginOrder = append(o.Admission.RecommendedPluginOrder, "DisallowFoo", foodefaults.PluginName)
 	return nil
 }
 
@@ -93,11 +171,36 @@ func (o Options) Validate(args []string) error {
 	var errs []error
 	if o.EnableEtcdStorage {
 		errs = o.Etcd.Validate()
+	} else if len(o.Etcd.EncryptionProviderConfigFilepath) > 0 {
+		errs = append(errs, fmt.Errorf("--encryption-provider-config requires etcd storage"))
+	}
+	if sizes, err := genericoptions.ParseWatchCacheSizes(o.Etcd.WatchCacheSizes); err != nil {
+		errs = append(errs, err)
+	} else {
+		for resource := range sizes {
+			if !cachedResources.Has(resource) {
+				errs = append(errs, fmt.Errorf("--watch-cache-sizes: %s is not a demo resource", resource))
+			}
+		}
+	}
+	errs = append(errs, o.EmbeddedEtcd.Validate()...)
+	if o.FooRevisionHistoryLimit < 0 {
+		errs = append(errs, fmt.Errorf("--foo-revision-history-limit must not be negative"))
+	}
+	if o.Features.EnablePriorityAndFairness {
+		errs = append(errs, fmt.Errorf("--enable-priority-and-fairness is not supported by the demo server"))
 	}
 	if o.EnableAuth {
 		errs = append(errs, o.Authentication.Validate()...)
 		errs = append(errs, o.Authorization.Validate()...)
 	}
+	errs = append(errs, o.APIEnablement.Validate(apiserver.Scheme)...)
+	if err := validation.ValidateAPIService(&o.Configuration().APIService, field.NewPath("apiService")).ToAggregate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validation.ValidateControllers(&o.Configuration().Controllers, field.NewPath("controllers")).ToAggregate(); err != nil {
+		errs = append(errs, err)
+	}
 	return utilerrors.NewAggregate(errs)
 }
 
@@ -111,6 +214,43 @@ func (o Options) ServerConfig() (*apiserver.Config, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	demoClient, err := clientset.NewForConfig(apiserver.DemoClientConfig(apiservercfg.LoopbackClientConfig))
+	if err != nil {
+		return nil, err
+	}
+	demoInformers := informers.NewSharedInformerFactory(demoClient, apiservercfg.LoopbackClientConfig.Timeout)
+
+	if o.EnableAdmission {
+		if err := o.AdmissionConfig(apiservercfg, demoInformers); err != nil {
+			return nil, err
+		}
+	}
+
+	var controllers *apiserver.ControllersConfig
+	if o.EnableControllers {
+		restConfig, err := o.restConfig()
+		if err != nil {
+			return nil, err
+		}
+		kubeClient, err := kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			return nil, err
+		}
+		controllers = &apiserver.ControllersConfig{
+			KubeClient:         kubeClient,
+			ConcurrentFooSyncs: o.ConcurrentFooSyncs,
+			LeaderElection:     o.LeaderElection,
+		}
+	}
+
+	var apiService *apiserver.APIServiceConfig
+	if o.RegisterAPIService {
+		if apiService, err = o.APIServiceConfig(); err != nil {
+			return nil, err
+		}
+	}
+
 	if o.EnableEtcdStorage {
 		storageConfigCopy := o.Etcd.StorageConfig
 		if storageConfigCopy.StorageObjectCountTracker == nil {
@@ -118,11 +258,20 @@ func (o Options) ServerConfig() (*apiserver.Config, error) {
 		}
 		klog.Infof("etcd cfg: %v", o.Etcd)
 
+		// keep writing v1alpha1 so that data stays readable by servers that
+		// predate v1beta1; Configs have no v1beta1 form at all
+		resourceEncoding := storage.NewDefaultResourceEncodingConfig(apiserver.Scheme)
+		for _, resource := range []string{"foos", "configs"} {
+			resourceEncoding.SetResourceEncoding(demo.Resource(resource), v1alpha1.SchemeGroupVersion, demo.SchemeGroupVersion)
+		}
+
+		// ApplyWithStorageFactoryTo also registers the "etcd" healthz/livez/readyz
+		// and "etcd-readiness" readyz checks against storageConfigCopy.
 		if err = o.Etcd.ApplyWithStorageFactoryTo(storage.NewDefaultStorageFactory(
-			o.Etcd.StorageConfig,
+			storageConfigCopy,
 			o.Etcd.DefaultStorageMediaType,
 			apiserver.Codec,
-			storage.NewDefaultResourceEncodingConfig(apiserver.Scheme),
+			resourceEncoding,
 			apiservercfg.MergedResourceConfig,
 			nil), &apiservercfg.Config); err != nil {
 			return nil, err
@@ -132,26 +281,43 @@ func (o Options) ServerConfig() (*apiserver.Config, error) {
 		GenericConfig: apiservercfg,
 		ExtraConfig: apiserver.ExtraConfig{
 			EnableEtcdStorage: o.EnableEtcdStorage,
+			DemoInformers:     demoInformers,
+			Controllers:       controllers,
+			APIService:        apiService,
+
+			FooDefaultsAdmission:    o.fooDefaultsAdmission(),
+			FooRevisionHistoryLimit: o.FooRevisionHistoryLimit,
 		},
 	}, nil
 }
 
 func (o Options) ApiserverConfig() (*genericapiserver.RecommendedConfig, error) {
-	if err := o.SecureServing.MaybeDefaultWithSelfSignedCerts("localhost", nil, []net.IP{net.ParseIP("127.0.0.1")}); err != nil {
+	var alternateDNS []string
+	if o.RegisterAPIService {
+		// the aggregator verifies the certificate against the Service name
+		alternateDNS = append(alternateDNS, fmt.Sprintf("%s.%s.svc", o.ServiceName, o.ServiceNamespace))
+	}
+	if err := o.SecureServing.MaybeDefaultWithSelfSignedCerts("localhost", alternateDNS, []net.IP{net.ParseIP("127.0.0.1")}); err != nil {
 		return nil, fmt.Errorf("error creating self-signed certificate: %v", err)
 	}
 	serverConfig := genericapiserver.NewRecommendedConfig(apiserver.Codec)
 	if err := o.SecureServing.ApplyTo(&serverConfig.SecureServing, &serverConfig.LoopbackClientConfig); err != nil {
 		return nil, err
 	}
+	if err := o.APIEnablement.ApplyTo(&serverConfig.Config, apiserver.DefaultAPIResourceConfigSource(), apiserver.Scheme); err != nil {
+		return nil, err
+	}
+	serverConfig.EnableProfiling = o.Features.EnableProfiling
+	serverConfig.EnableContentionProfiling = o.Features.EnableContentionProfiling
+	serverConfig.DebugSocketPath = o.Features.DebugSocketPath
 
 	// enable OpenAPI schemas
 	namer := openapinamer.NewDefinitionNamer(apiserver.Scheme)
-	serverConfig.OpenAPIConfig = genericapiserver.DefaultOpenAPIConfig(openapi.GetOpenAPIDefinitions, namer)
+	serverConfig.OpenAPIConfig = genericapiserver.DefaultOpenAPIConfig(apiserver.GetOpenAPIDefinitions, namer)
 	serverConfig.OpenAPIConfig.Info.Title = "demo.dev-server"
 	serverConfig.OpenAPIConfig.Info.Version = "0.1"
 
-	serverConfig.OpenAPIV3Config = genericapiserver.DefaultOpenAPIV3Config(openapi.GetOpenAPIDefinitions, namer)
+	serverConfig.OpenAPIV3Config = genericapiserver.DefaultOpenAPIV3Config(apiserver.GetOpenAPIDefinitions, namer)
 	serverConfig.OpenAPIV3Config.Info.Title = "demo.dev-server"
 	serverConfig.OpenAPIV3Config.Info.Version = "0.1"
 
@@ -162,24 +328,86 @@ func (o Options) ApiserverConfig() (*genericapiserver.RecommendedConfig, error)
 		if err := o.Authorization.ApplyTo(&serverConfig.Authorization); err != nil {
 			return nil, err
 		}
+	} else {
+		// handlers that authorize on their own, like an apply creating the
+		// object, fail without an authorizer
+		serverConfig.Authorization.Authorizer = authorizerfactory.NewAlwaysAllowAuthorizer()
 	}
 
-	if o.EnableAdmission {
-		(&genericoptions.CoreAPIOptions{}).ApplyTo(serverConfig)
+	return serverConfig, nil
+}
 
-		kubeClient, err := kubernetes.NewForConfig(serverConfig.ClientConfig)
-		if err != nil {
-			return nil, err
-		}
-		dynamicClient, err := dynamic.NewForConfig(serverConfig.ClientConfig)
-		if err != nil {
-			return nil, err
-		}
-		initializers := []admission.PluginInitializer{}
-		o.Admission.ApplyTo(&serverConfig.Config, serverConfig.SharedInformerFactory, kubeClient, dynamicClient, feature.DefaultFeatureGate, initializers...)
+// AdmissionConfig wires the admission chain into serverConfig. Plugins that
+// implement demoinitializer.WantsDemoInformerFactory get demoInformers.
+func (o Options) AdmissionConfig(serverConfig *genericapiserver.RecommendedConfig, demoInformers informers.SharedInformerFactory) error {
+	if err := (&genericoptions.CoreAPIOptions{}).ApplyTo(serverConfig); err != nil {
+		return err
 	}
 
-	return serverConfig, nil
+	kubeClient, err := kubernetes.NewForConfig(serverConfig.ClientConfig)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(serverConfig.ClientConfig)
+	if err != nil {
+		return err
+	}
+	initializers := []admission.PluginInitializer{
+		demoinitializer.New(demoInformers, serverConfig.MergedResourceConfig),
+	}
+	return o.Admission.ApplyTo(&serverConfig.Config, serverConfig.SharedInformerFactory, kubeClient, dynamicClient, feature.DefaultFeatureGate, initializers...)
+}
+
+// fooDefaultsAdmission tells whether the FooDefaults admission plugin runs,
+// the way the admission options pick the enabled plugins.
+func (o Options) fooDefaultsAdmission() bool {
+	if !o.EnableAdmission {
+		return false
+	}
+	if sets.New(o.Admission.EnablePlugins...).Has(foodefaults.PluginName) {
+		return true
+	}
+	return !o.Admission.DefaultOffPlugins.Has(foodefaults.PluginName) && !sets.New(o.Admission.DisablePlugins...).Has(foodefaults.PluginName)
+}
+
+// APIServiceConfig returns the APIService registration settings. Unless
+// --apiservice-ca-file is given, the caBundle is the serving certificate.
+func (o Options) APIServiceConfig() (*apiserver.APIServiceConfig, error) {
+	restConfig, err := o.restConfig()
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	caFile := o.APIServiceCAFile
+	if len(caFile) == 0 {
+		caFile = o.SecureServing.ServerCert.CertKey.CertFile
+	}
+	var caBundle dynamiccertificates.CAContentProvider
+	switch {
+	case len(caFile) > 0:
+		caBundle, err = dynamiccertificates.NewDynamicCAContentFromFile("apiservice-ca", caFile)
+	case o.SecureServing.ServerCert.GeneratedCert != nil:
+		cert, _ := o.SecureServing.ServerCert.GeneratedCert.CurrentCertKeyContent()
+		caBundle, err = dynamiccertificates.NewStaticCAContent("apiservice-ca", cert)
+	default:
+		err = fmt.Errorf("no CA bundle for the APIServices, set --apiservice-ca-file")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiserver.APIServiceConfig{
+		DynamicClient:        dynamicClient,
+		CABundle:             caBundle,
+		ServiceNamespace:     o.ServiceNamespace,
+		ServiceName:          o.ServiceName,
+		ServicePort:          int32(o.ServicePort),
+		UnregisterOnShutdown: o.UnregisterAPIServiceOnShutdown,
+	}, nil
 }
 
 func (o Options) restConfig() (*rest.Config, error) {
@@ -205,19 +433,52 @@ func (o Options) restConfig() (*rest.Config, error) {
 func NewDemoServerCommand(stopCh <-chan struct{}) *cobra.Command {
 	opts := &Options{
 		SecureServing:  genericoptions.NewSecureServingOptions().WithLoopback(),
+		Features:       genericoptions.NewFeatureOptions(),
 		Etcd:           genericoptions.NewEtcdOptions(storagebackend.NewDefaultConfig(defaultEtcdPathPrefix, nil)),
+		EmbeddedEtcd:   NewEmbeddedEtcdOptions(),
 		Authentication: genericoptions.NewDelegatingAuthenticationOptions(),
 		Authorization:  genericoptions.NewDelegatingAuthorizationOptions(),
 		Admission:      genericoptions.NewAdmissionOptions(),
+		APIEnablement:  genericoptions.NewAPIEnablementOptions(),
 	}
+	// objects written before a storage version change keep their old encoding
+	// until "apiserver migrate-storage" rewrites them
 	opts.Etcd.StorageConfig.EncodeVersioner = runtime.NewMultiGroupVersioner(demo.SchemeGroupVersion, schema.GroupKind{Group: demo.GroupName})
 	opts.Etcd.DefaultStorageMediaType = "application/json"
 	opts.SecureServing.BindPort = 6443
+	// the demo server has no access to the flowcontrol API of the backing cluster
+	opts.Features.EnablePriorityAndFairness = false
+	opts.ConcurrentFooSyncs = configv1alpha1.DefaultConcurrentFooSyncs
+	opts.LeaderElection = componentbaseconfig.LeaderElectionConfiguration{
+		LeaderElect:       true,
+		LeaseDuration:     metav1.Duration{Duration: 15 * time.Second},
+		RenewDeadline:     metav1.Duration{Duration: 10 * time.Second},
+		RetryPeriod:       metav1.Duration{Duration: 2 * time.Second},
+		ResourceLock:      resourcelock.LeasesResourceLock,
+		ResourceName:      configv1alpha1.DefaultLeaderElectionResourceName,
+		ResourceNamespace: configv1alpha1.DefaultLeaderElectionNamespace,
+	}
+	opts.ServiceNamespace = configv1alpha1.DefaultServiceNamespace
+	opts.ServiceName = configv1alpha1.DefaultServiceName
+	opts.ServicePort = configv1alpha1.DefaultServicePort
+	opts.ServerReplicas = 1
+	opts.FooRevisionHistoryLimit = configv1alpha1.DefaultFooRevisionHistoryLimit
 
 	cmd := &cobra.Command{
+		Use:   "apiserver",
 		Short: "Launch a demo server",
 		Long:  "Launch a demo server",
 		RunE: func(c *cobra.Command, args []string) error {
+			if len(opts.ConfigFile) > 0 {
+				cfg, err := loadConfigFile(opts.ConfigFile)
+				if err != nil {
+					return err
+				}
+				opts.ApplyConfiguration(cfg, c.Flags())
+			}
+			if opts.PrintConfig {
+				return printConfiguration(c.OutOrStdout(), opts.Configuration())
+			}
 			if err := opts.Complete(); err != nil {
 				return err
 			}
@@ -240,22 +501,43 @@ func NewDemoServerCommand(stopCh <-chan struct{}) *cobra.Command {
 	klog.InitFlags(local)
 	nfs.FlagSet("logging").AddGoFlagSet(local)
 
+	cmd.AddCommand(newExportCommand(), newImportCommand(), newMigrateCommand())
+
+	// the sectioned flags only belong to the server itself, subcommands keep
+	// the default help
+	defaultUsage, defaultHelp := cmd.UsageFunc(), cmd.HelpFunc()
 	usageFmt := "Usage:\n  %s\n"
 	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
-	cmd.SetUsageFunc(func(cmd *cobra.Command) error {
-		fmt.Fprintf(cmd.OutOrStderr(), usageFmt, cmd.UseLine())
-		cliflag.PrintSections(cmd.OutOrStderr(), nfs, cols)
+	cmd.SetUsageFunc(func(c *cobra.Command) error {
+		if c != cmd {
+			return defaultUsage(c)
+		}
+		fmt.Fprintf(c.OutOrStderr(), usageFmt, c.UseLine())
+		cliflag.PrintSections(c.OutOrStderr(), nfs, cols)
 		return nil
 	})
-	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
-		fmt.Fprintf(cmd.OutOrStdout(), "%s\n\n"+usageFmt, cmd.Long, cmd.UseLine())
-		cliflag.PrintSections(cmd.OutOrStdout(), nfs, cols)
+	cmd.SetHelpFunc(func(c *cobra.Command, args []string) {
+		if c != cmd {
+			defaultHelp(c, args)
+			return
+		}
+		fmt.Fprintf(c.OutOrStdout(), "%s\n\n"+usageFmt, c.Long, c.UseLine())
+		cliflag.PrintSections(c.OutOrStdout(), nfs, cols)
 	})
 
 	return cmd
 }
 
 func runCommand(options *Options, stopCh <-chan struct{}) error {
+	if options.EmbeddedEtcd.Enabled() {
+		stopEtcd, err := options.EmbeddedEtcd.Start(stopCh)
+		if err != nil {
+			return err
+		}
+		// runs after the server has drained, so the snapshot has every write
+		defer stopEtcd()
+	}
+
 	serverCfg, err := options.ServerConfig()
 	if err != nil {
 		return err
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/flowcontrol"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+const storageMigratorFieldManager = "demo-storage-migrator"
+
+// migrateOptions rewrite every demo object so that etcd only holds the current
+// storage version. Progress is kept in StateFile so that an interrupted run
+// resumes where it stopped and so that it records when it is safe to stop
+// serving old versions.
+type migrateOptions struct {
+	KubeConfig string
+	StateFile  string
+	PageSize   int64
+	QPS        float32
+	Burst      int
+	Restart    bool
+}
+
+// migrationState is what StateFile holds, keyed by group resource.
+type migrationState struct {
+	Resources map[string]*resourceMigration `json:"resources"`
+}
+
+type resourceMigration struct {
+	// StorageVersionHash is the storage version objects are migrated to, as
+	// published by discovery. A new hash starts the migration over.
+	StorageVersionHash string `json:"storageVersionHash"`
+	// Continue resumes the list after the last page that was fully migrated
+	Continue string `json:"continue,omitempty"`
+	// Migrated counts the objects of the current pass over the resource
+	Migrated int `json:"migrated"`
+	// Completed is set once every object was rewritten
+	Completed *metav1.Time `json:"completed,omitempty"`
+}
+
+func newMigrateCommand() *cobra.Command {
+	o := &migrateOptions{StateFile: "demo-storage-migration.json", PageSize: 100, QPS: 20, Burst: 40}
+	cmd := &cobra.Command{
+		Use:   "migrate-storage",
+		Short: "Rewrite all demo resources in the current storage version",
+		Long: "Rewrite all demo resources with no-op updates so that etcd holds them in the current storage version. " +
+			"Objects that are already stored in that version are not written again. Progress is saved after every page; " +
+			"once the state file marks every resource completed for its current storage version, old versions can be dropped.",
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.run(c.Context(), c.OutOrStdout(), c.ErrOrStderr())
+		},
+	}
+	cmd.Flags().StringVar(&o.KubeConfig, "kubeconfig", o.KubeConfig, "The kubeconfig of the cluster that serves the demo API (defaults to the usual kubeconfig loading rules)")
+	cmd.Flags().StringVar(&o.StateFile, "state-file", o.StateFile, "The file migration progress is read from and saved to")
+	cmd.Flags().Int64Var(&o.PageSize, "page-size", o.PageSize, "The number of objects to read per request")
+	cmd.Flags().Float32Var(&o.QPS, "qps", o.QPS, "The maximum number of updates per second")
+	cmd.Flags().IntVar(&o.Burst, "burst", o.Burst, "The maximum burst of updates")
+	cmd.Flags().BoolVar(&o.Restart, "restart", o.Restart, "If true, ignore the saved progress and migrate every resource again")
+	return cmd
+}
+
+func (o *migrateOptions) run(ctx context.Context, stdout, stderr io.Writer) error {
+	if o.QPS <= 0 || o.Burst <= 0 {
+		return fmt.Errorf("--qps and --burst must be positive")
+	}
+	config, err := loadClientConfig(o.KubeConfig)
+	if err != nil {
+		return err
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	state := &migrationState{}
+	if !o.Restart {
+		if state, err = loadMigrationState(o.StateFile); err != nil {
+			return err
+		}
+	}
+	if state.Resources == nil {
+		state.Resources = map[string]*resourceMigration{}
+	}
+	limiter := flowcontrol.NewTokenBucketRateLimiter(o.QPS, o.Burst)
+	defer limiter.Stop()
+
+	for _, resource := range backupResources {
+		gvr, err := migrationVersion(discoveryClient, resource.groupResource(), stderr)
+		if err != nil {
+			return err
+		}
+		hash := ""
+		if len(gvr.Version) > 0 {
+			if hash, err = storageVersionHash(discoveryClient, gvr); err != nil {
+				return err
+			}
+		}
+		if len(hash) == 0 {
+			fmt.Fprintf(stdout, "skipping %s: not served or not stored in etcd\n", resource.groupResource())
+			continue
+		}
+
+		key := gvr.GroupResource().String()
+		progress := state.Resources[key]
+		if progress == nil || progress.StorageVersionHash != hash {
+			progress = &resourceMigration{StorageVersionHash: hash}
+			state.Resources[key] = progress
+		}
+		if progress.Completed != nil {
+			fmt.Fprintf(stdout, "%s already migrated to storage version %s at %s\n", key, hash, progress.Completed.UTC().Format(time.RFC3339))
+			continue
+		}
+		if err := o.migrate(ctx, client.Resource(gvr), limiter, progress, func() error {
+			return state.save(o.StateFile)
+		}); err != nil {
+			return fmt.Errorf("unable to migrate %s: %v", key, err)
+		}
+
+		// a storage version that changed meanwhile may have been missed by
+		// objects written early on
+		if current, err := storageVersionHash(discoveryClient, gvr); err != nil {
+			return err
+		} else if current != hash {
+			progress.Completed = nil
+			if err := state.save(o.StateFile); err != nil {
+				return err
+			}
+			return fmt.Errorf("the storage version of %s changed during the migration, run it again", key)
+		}
+		fmt.Fprintf(stdout, "migrated %d %s to storage version %s\n", progress.Migrated, key, hash)
+	}
+	return nil
+}
+
+// migrate pages through all objects of a resource, starting after the saved
+// continue token, and writes each back unchanged. The storage layer only
+// persists objects whose encoding changes, so objects already in the current
+// storage version cost a read and nothing else.
+func (o *migrateOptions) migrate(ctx context.Context, client dynamic.NamespaceableResourceInterface, limiter flowcontrol.RateLimiter, progress *resourceMigration, save func() error) error {
+	for {
+		list, err := client.List(ctx, metav1.ListOptions{Limit: o.PageSize, Continue: progress.Continue})
+		if apierrors.IsResourceExpired(err) {
+			// the snapshot the token refers to was compacted; listing again
+			// from the start only rewrites what is still in the old version,
+			// and counts everything again
+			progress.Continue = ""
+			progress.Migrated = 0
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for i := range list.Items {
+			if err := limiter.Wait(ctx); err != nil {
+				return err
+			}
+			if err := migrateObject(ctx, client, &list.Items[i]); err != nil {
+				return err
+			}
+			progress.Migrated++
+		}
+		progress.Continue = list.GetContinue()
+		if len(progress.Continue) == 0 {
+			now := metav1.Now()
+			progress.Completed = &now
+		}
+		if err := save(); err != nil {
+			return err
+		}
+		if progress.Completed != nil {
+			return nil
+		}
+	}
+}
+
+func migrateObject(ctx context.Context, client dynamic.NamespaceableResourceInterface, obj *unstructured.Unstructured) error {
+	_, err := client.Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{FieldManager: storageMigratorFieldManager})
+	switch {
+	case err == nil:
+		return nil
+	case apierrors.IsConflict(err), apierrors.IsNotFound(err):
+		// written or deleted since it was listed, either way it is no longer
+		// stored in the old version
+		return nil
+	default:
+		return fmt.Errorf("%s/%s: %v", obj.GetNamespace(), obj.GetName(), err)
+	}
+}
+
+// migrationVersion returns the version gr is read and written in, the
+// preferred version of its group or, with a warning, the first other version
+// that serves it. The version is empty if none does. Any version works since
+// every one is stored the same way.
+func migrationVersion(client discovery.DiscoveryInterface, gr schema.GroupResource, stderr io.Writer) (schema.GroupVersionResource, error) {
+	groups, err := client.ServerGroups()
+	if err != nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("unable to discover the served groups: %v", err)
+	}
+	for _, group := range groups.Groups {
+		if group.Name != gr.Group {
+			continue
+		}
+		versions := []string{group.PreferredVersion.Version}
+		for _, version := range group.Versions {
+			if version.Version != group.PreferredVersion.Version {
+				versions = append(versions, version.Version)
+			}
+		}
+		for _, version := range versions {
+			gvr := gr.WithVersion(version)
+			resources, err := client.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return schema.GroupVersionResource{}, fmt.Errorf("unable to discover %s: %v", gvr.GroupVersion(), err)
+			}
+			if !slices.ContainsFunc(resources.APIResources, func(r metav1.APIResource) bool { return r.Name == gr.Resource }) {
+				continue
+			}
+			if version != group.PreferredVersion.Version {
+				fmt.Fprintf(stderr, "warning: %s is not served in the preferred version %s, migrating it through %s\n", gr, group.PreferredVersion.Version, version)
+			}
+			return gvr, nil
+		}
+	}
+	return schema.GroupVersionResource{}, nil
+}
+
+// storageVersionHash returns the storage version hash discovery publishes for
+// gvr, or "" if gvr is not served or its storage has no version.
+func storageVersionHash(client discovery.DiscoveryInterface, gvr schema.GroupVersionResource) (string, error) {
+	resources, err := client.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to discover %s: %v", gvr.GroupVersion(), err)
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == gvr.Resource {
+			return r.StorageVersionHash, nil
+		}
+	}
+	return "", nil
+}
+
+func loadMigrationState(path string) (*migrationState, error) {
+	state := &migrationState{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("unable to read migration state from %s: %v", path, err)
+	}
+	return state, nil
+}
+
+// save replaces the state file, so that an interrupted write never loses the
+// previous progress.
+func (s *migrationState) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".migration-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/generated/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/flowcontrol"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// pagedFoos serves a list in pages, keyed by the continue token that
+// requests them. Tokens it does not know have expired.
+type pagedFoos struct {
+	dynamic.NamespaceableResourceInterface
+	pages map[string]*unstructured.UnstructuredList
+	// updateErr is returned for the updates of the named objects
+	updateErr map[string]error
+
+	continues []string
+	updated   []string
+}
+
+func (f *pagedFoos) Namespace(string) dynamic.ResourceInterface { return f }
+
+func (f *pagedFoos) List(_ context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	f.continues = append(f.continues, opts.Continue)
+	page, ok := f.pages[opts.Continue]
+	if !ok {
+		return nil, apierrors.NewResourceExpired("the continue token expired")
+	}
+	return page, nil
+}
+
+func (f *pagedFoos) Update(_ context.Context, obj *unstructured.Unstructured, opts metav1.UpdateOptions, _ ...string) (*unstructured.Unstructured, error) {
+	if opts.FieldManager != storageMigratorFieldManager {
+		return nil, fmt.Errorf("unexpected field manager %q", opts.FieldManager)
+	}
+	f.updated = append(f.updated, obj.GetName())
+	return obj, f.updateErr[obj.GetName()]
+}
+
+func fooPage(next string, names ...string) *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetContinue(next)
+	for _, name := range names {
+		obj := unstructured.Unstructured{}
+		obj.SetName(name)
+		obj.SetNamespace(metav1.NamespaceDefault)
+		list.Items = append(list.Items, obj)
+	}
+	return list
+}
+
+func TestMigrate(t *testing.T) {
+	pages := map[string]*unstructured.UnstructuredList{
+		"":   fooPage("p2", "foo-1", "foo-2"),
+		"p2": fooPage("p3", "foo-3"),
+		"p3": fooPage("", "foo-4"),
+	}
+	tests := []struct {
+		name     string
+		progress resourceMigration
+		// updateErr is returned for the update of foo-2
+		updateErr     error
+		wantContinues []string
+		wantUpdated   []string
+		wantMigrated  int
+		wantSaves     int
+		wantErr       string
+	}{
+		{
+			name:          "from the start",
+			wantContinues: []string{"", "p2", "p3"},
+			wantUpdated:   []string{"foo-1", "foo-2", "foo-3", "foo-4"},
+			wantMigrated:  4,
+			wantSaves:     3,
+		},
+		{
+			name:          "resumed",
+			progress:      resourceMigration{Continue: "p2", Migrated: 2},
+			wantContinues: []string{"p2", "p3"},
+			wantUpdated:   []string{"foo-3", "foo-4"},
+			wantMigrated:  4,
+			wantSaves:     2,
+		},
+		{
+			name:          "expired token restarts the list and the count",
+			progress:      resourceMigration{Continue: "compacted", Migrated: 3},
+			wantContinues: []string{"compacted", "", "p2", "p3"},
+			wantUpdated:   []string{"foo-1", "foo-2", "foo-3", "foo-4"},
+			wantMigrated:  4,
+			wantSaves:     3,
+		},
+		{
+			name:          "written meanwhile",
+			updateErr:     apierrors.NewConflict(schema.GroupResource{Resource: "foos"}, "foo-2", fmt.Errorf("changed")),
+			wantContinues: []string{"", "p2", "p3"},
+			wantUpdated:   []string{"foo-1", "foo-2", "foo-3", "foo-4"},
+			wantMigrated:  4,
+			wantSaves:     3,
+		},
+		{
+			name:          "deleted meanwhile",
+			updateErr:     apierrors.NewNotFound(schema.GroupResource{Resource: "foos"}, "foo-2"),
+			wantContinues: []string{"", "p2", "p3"},
+			wantUpdated:   []string{"foo-1", "foo-2", "foo-3", "foo-4"},
+			wantMigrated:  4,
+			wantSaves:     3,
+		},
+		{
+			name:          "failed",
+			updateErr:     apierrors.NewForbidden(schema.GroupResource{Resource: "foos"}, "foo-2", fmt.Errorf("denied")),
+			wantContinues: []string{""},
+			wantUpdated:   []string{"foo-1", "foo-2"},
+			wantMigrated:  1,
+			wantErr:       "default/foo-2",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &pagedFoos{pages: pages, updateErr: map[string]error{"foo-2": tc.updateErr}}
+			progress := tc.progress
+			saves := 0
+			o := &migrateOptions{PageSize: 2}
+			err := o.migrate(context.Background(), client, flowcontrol.NewFakeAlwaysRateLimiter(), &progress, func() error {
+				saves++
+				return nil
+			})
+			if len(tc.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("expected an error containing %q, got %v", tc.wantErr, err)
+				}
+				if progress.Completed != nil {
+					t.Errorf("expected the migration to be incomplete")
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if progress.Completed == nil || len(progress.Continue) > 0 {
+					t.Errorf("expected the migration to be completed, got %+v", progress)
+				}
+			}
+			if !slices.Equal(client.continues, tc.wantContinues) {
+				t.Errorf("expected the lists to continue from %q, got %q", tc.wantContinues, client.continues)
+			}
+			if !slices.Equal(client.updated, tc.wantUpdated) {
+				t.Errorf("expected %v to be written, got %v", tc.wantUpdated, client.updated)
+			}
+			if progress.Migrated != tc.wantMigrated || saves != tc.wantSaves {
+				t.Errorf("expected %d migrated and %d saves, got %d and %d", tc.wantMigrated, tc.wantSaves, progress.Migrated, saves)
+			}
+		})
+	}
+}
+
+// TestMigrateSavesEveryPage checks that the saved progress always resumes
+// after the last page that was migrated.
+func TestMigrateSavesEveryPage(t *testing.T) {
+	client := &pagedFoos{pages: map[string]*unstructured.UnstructuredList{
+		"":   fooPage("p2", "foo-1"),
+		"p2": fooPage("", "foo-2"),
+	}}
+	progress := &resourceMigration{}
+	var saved []resourceMigration
+	o := &migrateOptions{PageSize: 1}
+	err := o.migrate(context.Background(), client, flowcontrol.NewFakeAlwaysRateLimiter(), progress, func() error {
+		saved = append(saved, *progress)
+		if len(saved) == 1 {
+			return fmt.Errorf("disk full")
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "disk full" {
+		t.Fatalf("expected the failed save to stop the migration, got %v", err)
+	}
+	if len(saved) != 1 || saved[0].Continue != "p2" || saved[0].Migrated != 1 || saved[0].Completed != nil {
+		t.Errorf("expected the progress after the first page to be saved, got %+v", saved)
+	}
+}
+
+func TestMigrationState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	state, err := loadMigrationState(path)
+	if err != nil {
+		t.Fatalf("expected a missing state file to start afresh: %v", err)
+	}
+	if len(state.Resources) > 0 {
+		t.Errorf("expected no progress, got %+v", state.Resources)
+	}
+
+	completed := metav1.Unix(1700000000, 0)
+	state.Resources = map[string]*resourceMigration{
+		"foos.demo.k8s.io":    {StorageVersionHash: "hash-1", Continue: "p2", Migrated: 100},
+		"configs.demo.k8s.io": {StorageVersionHash: "hash-2", Migrated: 3, Completed: &completed},
+	}
+	if err := state.save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadMigrationState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	foos, configs := loaded.Resources["foos.demo.k8s.io"], loaded.Resources["configs.demo.k8s.io"]
+	if foos == nil || *foos != *state.Resources["foos.demo.k8s.io"] {
+		t.Errorf("expected the progress of foos to be saved, got %+v", foos)
+	}
+	if configs == nil || configs.Completed == nil || !configs.Completed.Equal(&completed) || configs.Migrated != 3 {
+		t.Errorf("expected configs to be saved as completed, got %+v", configs)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the state file to be left, got %v", entries)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadMigrationState(path); err == nil || !strings.Contains(err.Error(), "unable to read migration state") {
+		t.Errorf("expected a corrupt state file to be reported, got %v", err)
+	}
+}
+
+func TestMigrationVersion(t *testing.T) {
+	resources := func(version string, names ...string) *metav1.APIResourceList {
+		list := &metav1.APIResourceList{GroupVersion: "demo.k8s.io/" + version}
+		for _, name := range names {
+			list.APIResources = append(list.APIResources, metav1.APIResource{Name: name, StorageVersionHash: "hash"})
+		}
+		return list
+	}
+	tests := []struct {
+		name string
+		// served lists the versions, the preferred one first
+		served      []*metav1.APIResourceList
+		resource    string
+		wantVersion string
+		wantWarning bool
+	}{
+		{
+			name:        "preferred version",
+			served:      []*metav1.APIResourceList{resources("v1beta1", "foos", "foodefaults"), resources("v1alpha1", "foos", "configs")},
+			resource:    "foos",
+			wantVersion: "v1beta1",
+		},
+		{
+			name:        "only v1alpha1 served",
+			served:      []*metav1.APIResourceList{resources("v1alpha1", "foos", "configs")},
+			resource:    "foos",
+			wantVersion: "v1alpha1",
+		},
+		{
+			name:        "not in the preferred version",
+			served:      []*metav1.APIResourceList{resources("v1beta1", "foos", "foodefaults"), resources("v1alpha1", "foos", "configs")},
+			resource:    "configs",
+			wantVersion: "v1alpha1",
+			wantWarning: true,
+		},
+		{
+			name:     "not served",
+			served:   []*metav1.APIResourceList{resources("v1beta1", "foos")},
+			resource: "configs",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: tc.served}}
+			stderr := &bytes.Buffer{}
+			gvr, err := migrationVersion(client, schema.GroupResource{Group: "demo.k8s.io", Resource: tc.resource}, stderr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gvr.Version != tc.wantVersion {
+				t.Errorf("expected version %q, got %q", tc.wantVersion, gvr.Version)
+			}
+			if warned := strings.Contains(stderr.String(), "warning:"); warned != tc.wantWarning {
+				t.Errorf("expected a warning: %v, got %q", tc.wantWarning, stderr.String())
+			}
+		})
+	}
+}
+
+// TestMigrateStorage migrates a server twice, the second time from the state
+// file the first run left, and once more after the storage version of foos
+// changed.
+func TestMigrateStorage(t *testing.T) {
+	config := startTestServer(t, "--feature-gates=ConfigResource=true")
+	client, err := versioned.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	createBackupObjects(t, client)
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	migrate := func(args ...string) string {
+		t.Helper()
+		out, err := executeCommand(newMigrateCommand(), append([]string{"--kubeconfig=" + writeKubeconfig(t, config), "--state-file=" + stateFile}, args...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	expectLines := func(out string, want ...string) {
+		t.Helper()
+		for _, line := range want {
+			if !strings.Contains(out, line) {
+				t.Errorf("expected %q in the output, got\n%s", line, out)
+			}
+		}
+	}
+
+	expectLines(migrate(), "migrated 1 configs.demo.k8s.io", "migrated 1 foos.demo.k8s.io", "migrated 1 foodefaults.demo.k8s.io")
+	expectLines(migrate(), "configs.demo.k8s.io already migrated", "foos.demo.k8s.io already migrated", "foodefaults.demo.k8s.io already migrated")
+
+	state, err := loadMigrationState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Resources["foos.demo.k8s.io"].StorageVersionHash = "previous"
+	if err := state.save(stateFile); err != nil {
+		t.Fatal(err)
+	}
+	expectLines(migrate(), "configs.demo.k8s.io already migrated", "migrated 1 foos.demo.k8s.io", "foodefaults.demo.k8s.io already migrated")
+	expectLines(migrate("--restart"), "migrated 1 configs.demo.k8s.io", "migrated 1 foos.demo.k8s.io", "migrated 1 foodefaults.demo.k8s.io")
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	demov1beta1 "github.com/guodoliu/apiserver/pkg/generated/applyconfiguration/demo/v1beta1"
+	"github.com/guodoliu/apiserver/pkg/generated/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestServerSideApply applies parts of a Foo with two field managers, which
+// only merges and detects conflicts field by field if the published schema
+// describes Foos.
+func TestServerSideApply(t *testing.T) {
+	client, err := versioned.NewForConfig(startTestServer(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	foos := client.DemoV1beta1().Foos("default")
+	ctx := context.Background()
+
+	if _, err := foos.Apply(ctx, demov1beta1.Foo("one", "default").
+		WithSpec(demov1beta1.FooSpec().WithImage("busybox:1.36")),
+		metav1.ApplyOptions{FieldManager: "images"}); err != nil {
+		t.Fatal(err)
+	}
+	foo, err := foos.Apply(ctx, demov1beta1.Foo("one", "default").
+		WithSpec(demov1beta1.FooSpec().WithConfig(demov1beta1.FooConfig().WithMsg("hi"))),
+		metav1.ApplyOptions{FieldManager: "messages"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if foo.Spec.Image != "busybox:1.36" || foo.Spec.Config.Msg != "hi" {
+		t.Errorf("expected the fields of both managers to be merged, got %+v", foo.Spec)
+	}
+	owners := map[string]string{}
+	for _, entry := range foo.ManagedFields {
+		owners[entry.Manager] = string(entry.FieldsV1.Raw)
+	}
+	if !strings.Contains(owners["images"], `"f:image"`) || strings.Contains(owners["images"], `"f:msg"`) ||
+		!strings.Contains(owners["messages"], `"f:msg"`) || strings.Contains(owners["messages"], `"f:image"`) {
+		t.Errorf("expected each manager to own the field it applied, got %v", owners)
+	}
+
+	_, err = foos.Apply(ctx, demov1beta1.Foo("one", "default").
+		WithSpec(demov1beta1.FooSpec().WithImage("alpine:3.19").WithConfig(demov1beta1.FooConfig().WithMsg("hi"))),
+		metav1.ApplyOptions{FieldManager: "messages"})
+	if !errors.IsConflict(err) || !strings.Contains(err.Error(), ".spec.image") {
+		t.Errorf("expected a conflict on .spec.image, got %v", err)
+	}
+
+	// forcing takes the field over
+	foo, err = foos.Apply(ctx, demov1beta1.Foo("one", "default").
+		WithSpec(demov1beta1.FooSpec().WithImage("alpine:3.19").WithConfig(demov1beta1.FooConfig().WithMsg("hi"))),
+		metav1.ApplyOptions{FieldManager: "messages", Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if foo.Spec.Image != "alpine:3.19" {
+		t.Errorf("expected the forced image, got %q", foo.Spec.Image)
+	}
+	if owned := managedFields(foo, "messages", ""); !strings.Contains(owned, `"f:image"`) {
+		t.Errorf("expected messages to own the image it forced, got %s", owned)
+	}
+	if _, ok := managedFieldsEntry(foo, "images", ""); ok {
+		t.Errorf("expected images to own nothing once its only field was taken over, got %v", foo.ManagedFields)
+	}
+}
+
+// TestServerSideApplyStatus applies the status of a Foo. With the status
+// subresource the spec and the status are reset on the endpoint that does not
+// write them, so appliers of one never own fields of the other. Without it
+// foos have no reset fields and the main endpoint applies the status too.
+func TestServerSideApplyStatus(t *testing.T) {
+	ctx := context.Background()
+	spec := demov1beta1.FooSpec().WithImage("busybox:1.36")
+	status := demov1beta1.FooStatus().WithPhase(v1beta1.FooPhaseReady).WithReplicas(1)
+
+	t.Run("status subresource", func(t *testing.T) {
+		client, err := versioned.NewForConfig(startTestServer(t, "--feature-gates=FooStatusSubresource=true"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		foos := client.DemoV1beta1().Foos("default")
+		if _, err := foos.Apply(ctx, demov1beta1.Foo("one", "default").WithSpec(spec),
+			metav1.ApplyOptions{FieldManager: "user"}); err != nil {
+			t.Fatal(err)
+		}
+
+		// the spec applied to foos/status is reset
+		foo, err := foos.ApplyStatus(ctx, demov1beta1.Foo("one", "default").
+			WithSpec(demov1beta1.FooSpec().WithImage("alpine:3.19")).WithStatus(status),
+			metav1.ApplyOptions{FieldManager: "controller"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if foo.Status.Phase != v1beta1.FooPhaseReady || foo.Status.Replicas != 1 || foo.Spec.Image != "busybox:1.36" {
+			t.Errorf("expected only the status to be applied, got %+v", foo)
+		}
+		owned := managedFields(foo, "controller", "status")
+		if !strings.Contains(owned, `"f:phase"`) || strings.Contains(owned, `"f:spec"`) {
+			t.Errorf("expected controller to own the status only, got %s", owned)
+		}
+
+		// the status applied to foos is reset, and does not conflict with
+		// the owner of the status
+		foo, err = foos.Apply(ctx, demov1beta1.Foo("one", "default").WithSpec(spec).
+			WithStatus(demov1beta1.FooStatus().WithPhase(v1beta1.FooPhaseProcessing)),
+			metav1.ApplyOptions{FieldManager: "user"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if foo.Status.Phase != v1beta1.FooPhaseReady {
+			t.Errorf("expected the status to be kept, got %+v", foo.Status)
+		}
+		if owned := managedFields(foo, "user", ""); strings.Contains(owned, `"f:status"`) {
+			t.Errorf("expected user not to own the status, got %s", owned)
+		}
+
+		// appliers of the status conflict among themselves
+		_, err = foos.ApplyStatus(ctx, demov1beta1.Foo("one", "default").
+			WithStatus(demov1beta1.FooStatus().WithPhase(v1beta1.FooPhaseProcessing)),
+			metav1.ApplyOptions{FieldManager: "other-controller"})
+		if !errors.IsConflict(err) || !strings.Contains(err.Error(), ".status.phase") {
+			t.Errorf("expected a conflict on .status.phase, got %v", err)
+		}
+	})
+
+	t.Run("no status subresource", func(t *testing.T) {
+		client, err := versioned.NewForConfig(startTestServer(t, "--feature-gates=FooStatusSubresource=false"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		foos := client.DemoV1beta1().Foos("default")
+		foo, err := foos.Apply(ctx, demov1beta1.Foo("one", "default").WithSpec(spec).WithStatus(status),
+			metav1.ApplyOptions{FieldManager: "user"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if foo.Status.Phase != v1beta1.FooPhaseReady || foo.Status.Replicas != 1 {
+			t.Errorf("expected the status to be applied, got %+v", foo.Status)
+		}
+		if owned := managedFields(foo, "user", ""); !strings.Contains(owned, `"f:phase"`) || !strings.Contains(owned, `"f:image"`) {
+			t.Errorf("expected user to own the spec and the status, got %s", owned)
+		}
+		if _, err := foos.ApplyStatus(ctx, demov1beta1.Foo("one", "default").WithStatus(status),
+			metav1.ApplyOptions{FieldManager: "controller"}); !errors.IsNotFound(err) {
+			t.Errorf("expected foos/status not to be served, got %v", err)
+		}
+	})
+}
+
+// managedFieldsEntry returns the managed fields of manager on subresource,
+// empty for the main resource.
+func managedFieldsEntry(foo *v1beta1.Foo, manager, subresource string) (metav1.ManagedFieldsEntry, bool) {
+	for _, entry := range foo.ManagedFields {
+		if entry.Manager == manager && entry.Subresource == subresource {
+			return entry, true
+		}
+	}
+	return metav1.ManagedFieldsEntry{}, false
+}
+
+// managedFields returns the fields manager owns on subresource as JSON.
+func managedFields(foo *v1beta1.Foo, manager, subresource string) string {
+	entry, ok := managedFieldsEntry(foo, manager, subresource)
+	if !ok || entry.FieldsV1 == nil {
+		return ""
+	}
+	return string(entry.FieldsV1.Raw)
+}
+
+// TestStrictFooSpecDecoding checks that the legacy field names of v1alpha1
+// are still accepted, but not together with the current ones.
+func TestStrictFooSpecDecoding(t *testing.T) {
+	client, err := versioned.NewForConfig(startTestServer(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	create := func(name, spec string) (*v1alpha1.Foo, error) {
+		body := fmt.Sprintf(`{"apiVersion":"demo.k8s.io/v1alpha1","kind":"Foo","metadata":{"name":%q},"spec":%s}`, name, spec)
+		foo := &v1alpha1.Foo{}
+		return foo, client.DemoV1alpha1().RESTClient().Post().
+			Namespace("default").Resource("foos").Param("fieldValidation", "Strict").
+			Body([]byte(body)).Do(context.Background()).Into(foo)
+	}
+
+	foo, err := create("legacy", `{"Image":"busybox:1.36","Config":{"Msg":"hi"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if foo.Spec.Image != "busybox:1.36" || foo.Spec.Config.Msg != "hi" {
+		t.Errorf("expected the legacy fields to be read, got %+v", foo.Spec)
+	}
+	if _, err := create("both", `{"image":"busybox:1.36","Image":"alpine:3.19"}`); !errors.IsBadRequest(err) {
+		t.Errorf("expected a foo setting image twice to be rejected, got %v", err)
+	}
+}
+
+// TestOpenAPIExplain looks fields of Foos up in the published OpenAPI
+// documents the way kubectl explain does.
+func TestOpenAPIExplain(t *testing.T) {
+	config := startTestServer(t)
+	client, err := rest.HTTPClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(t *testing.T, path string) map[string]interface{} {
+		t.Helper()
+		resp, err := client.Get(config.Host + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status 200 for %s, got %s", path, resp.Status)
+		}
+		document := map[string]interface{}{}
+		if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+			t.Fatal(err)
+		}
+		return document
+	}
+
+	documents := map[string]func(t *testing.T) (definitions map[string]interface{}){
+		"v2": func(t *testing.T) map[string]interface{} {
+			return get(t, "/openapi/v2")["definitions"].(map[string]interface{})
+		},
+		"v3": func(t *testing.T) map[string]interface{} {
+			paths := get(t, "/openapi/v3")["paths"].(map[string]interface{})
+			path, ok := paths["apis/"+demo.GroupName+"/v1beta1"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("no OpenAPI v3 document for %s/v1beta1 in %v", demo.GroupName, paths)
+			}
+			document := get(t, path["serverRelativeURL"].(string))
+			return document["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+		},
+	}
+	for version, definitions := range documents {
+		t.Run(version, func(t *testing.T) {
+			e := explainer{definitions: definitions(t)}
+			tests := []struct {
+				path string
+				// description is a substring of the expected description
+				description string
+			}{
+				{path: "spec.image", description: "container image"},
+				{path: "spec.config", description: "configuration used by foo container"},
+				{path: "spec.config.msg", description: "hello world"},
+				{path: "spec.config.msg1", description: "Deprecated"},
+				{path: "status", description: "observed state"},
+			}
+			for _, tc := range tests {
+				field, err := e.explain("Foo", tc.path)
+				if err != nil {
+					t.Errorf("%s: %v", tc.path, err)
+					continue
+				}
+				if description, _ := field["description"].(string); !strings.Contains(description, tc.description) {
+					t.Errorf("expected the description of %s to contain %q, got %q", tc.path, tc.description, description)
+				}
+			}
+			if _, err := e.explain("Foo", "spec.Image"); err == nil {
+				t.Error("expected the legacy spec.Image not to be documented")
+			}
+		})
+	}
+}
+
+// explainer resolves field paths in the definitions of an OpenAPI document.
+type explainer struct {
+	definitions map[string]interface{}
+}
+
+// explain returns the schema of the field at path in the v1beta1 kind.
+func (e explainer) explain(kind, path string) (map[string]interface{}, error) {
+	var schema map[string]interface{}
+	for _, definition := range e.definitions {
+		definition := definition.(map[string]interface{})
+		gvks, _ := definition["x-kubernetes-group-version-kind"].([]interface{})
+		for _, gvk := range gvks {
+			gvk := gvk.(map[string]interface{})
+			if gvk["group"] == demo.GroupName && gvk["version"] == "v1beta1" && gvk["kind"] == kind {
+				schema = definition
+			}
+		}
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("no definition of %s/v1beta1 %s", demo.GroupName, kind)
+	}
+
+	var field map[string]interface{}
+	for _, name := range strings.Split(path, ".") {
+		properties, _ := e.resolve(schema)["properties"].(map[string]interface{})
+		field, _ = properties[name].(map[string]interface{})
+		if field == nil {
+			return nil, fmt.Errorf("no field %s", name)
+		}
+		schema = field
+	}
+	return field, nil
+}
+
+// resolve follows a reference, given directly or as the only schema of allOf.
+func (e explainer) resolve(schema map[string]interface{}) map[string]interface{} {
+	if allOf, ok := schema["allOf"].([]interface{}); ok && len(allOf) == 1 {
+		schema = allOf[0].(map[string]interface{})
+	}
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return schema
+	}
+	definition, _ := e.definitions[ref[strings.LastIndex(ref, "/")+1:]].(map[string]interface{})
+	return definition
+}
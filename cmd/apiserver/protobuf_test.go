@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"github.com/guodoliu/apiserver/pkg/apiserver"
+	"github.com/guodoliu/apiserver/pkg/generated/clientset/versioned"
+	"io"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// responseRecorder records the content type of the responses a client gets
+// and counts the bytes of their bodies.
+type responseRecorder struct {
+	rt           http.RoundTripper
+	mu           sync.Mutex
+	contentTypes []string
+	bytes        int64
+}
+
+func (r *responseRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.rt.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	r.contentTypes = append(r.contentTypes, resp.Header.Get("Content-Type"))
+	r.mu.Unlock()
+	resp.Body = &countingBody{ReadCloser: resp.Body, recorder: r}
+	return resp, nil
+}
+
+func (r *responseRecorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.contentTypes, r.bytes = nil, 0
+}
+
+type countingBody struct {
+	io.ReadCloser
+	recorder *responseRecorder
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.recorder.mu.Lock()
+	b.recorder.bytes += int64(n)
+	b.recorder.mu.Unlock()
+	return n, err
+}
+
+// recordingClient returns a demo clientset for config and the recorder of
+// its responses.
+func recordingClient(t testing.TB, config *rest.Config) (*versioned.Clientset, *responseRecorder) {
+	config = rest.CopyConfig(config)
+	recorder := &responseRecorder{}
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		recorder.rt = rt
+		return recorder
+	})
+	client, err := versioned.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, recorder
+}
+
+func TestProtobuf(t *testing.T) {
+	clientURL := fmt.Sprintf("http://127.0.0.1:%d", freePort(t))
+	config := startTestServer(t,
+		"--embedded-etcd-client-url="+clientURL,
+		"--storage-media-type="+runtime.ContentTypeProtobuf,
+	)
+	ctx := context.Background()
+
+	demoConfig := apiserver.DemoClientConfig(config)
+	if demoConfig.ContentType != runtime.ContentTypeProtobuf {
+		t.Fatalf("expected the demo clients to send protobuf, got %q", demoConfig.ContentType)
+	}
+	client, recorder := recordingClient(t, demoConfig)
+
+	foo := &v1beta1.Foo{
+		ObjectMeta: metav1.ObjectMeta{Name: "one"},
+		Spec:       v1beta1.FooSpec{Image: "busybox:1.36", Config: v1beta1.FooConfig{Msg: "hello"}},
+	}
+	created, err := client.DemoV1beta1().Foos("default").Create(ctx, foo, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value := rawValue(t, clientURL, "foos", "one"); !strings.HasPrefix(value, "k8s\x00") {
+		t.Errorf("expected the foo to be stored as protobuf, got %q", value[:min(len(value), 16)])
+	}
+
+	w, err := client.DemoV1beta1().Foos("default").Watch(ctx, metav1.ListOptions{ResourceVersion: created.ResourceVersion})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+	created.Spec.Config.Msg = "hello again"
+	if _, err := client.DemoV1beta1().Foos("default").Update(ctx, created, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-w.ResultChan():
+		if got, ok := event.Object.(*v1beta1.Foo); event.Type != watch.Modified || !ok || got.Spec.Config.Msg != "hello again" {
+			t.Errorf("expected the foo to be modified, got %s of %v", event.Type, event.Object)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("the update did not reach the watcher")
+	}
+	if list, err := client.DemoV1beta1().Foos("default").List(ctx, metav1.ListOptions{}); err != nil {
+		t.Error(err)
+	} else if len(list.Items) != 1 || list.Items[0].Spec.Image != "busybox:1.36" {
+		t.Errorf("expected the foo to be listed, got %v", list.Items)
+	}
+
+	recorder.mu.Lock()
+	for _, contentType := range recorder.contentTypes {
+		if !strings.HasPrefix(contentType, runtime.ContentTypeProtobuf) {
+			t.Errorf("expected protobuf responses, got %q", contentType)
+		}
+	}
+	recorder.mu.Unlock()
+
+	// clients that only speak JSON read the same foo
+	jsonClient, err := versioned.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := jsonClient.DemoV1alpha1().Foos("default").Get(ctx, "one", metav1.GetOptions{}); err != nil {
+		t.Error(err)
+	} else if got.Spec.Config.Msg != "hello again" {
+		t.Errorf("expected the JSON client to read the update, got %q", got.Spec.Config.Msg)
+	}
+}
+
+// BenchmarkFooRequests gets a foo and lists 100 of them with either media
+// type on the wire and in etcd, and reports the bytes of each response.
+func BenchmarkFooRequests(b *testing.B) {
+	for _, mediaType := range []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf} {
+		b.Run(mediaType, func(b *testing.B) {
+			config := startTestServer(b, "--storage-media-type="+mediaType)
+			config.ContentType = mediaType
+			config.AcceptContentTypes = mediaType
+			client, recorder := recordingClient(b, config)
+			foos := client.DemoV1beta1().Foos("default")
+			ctx := context.Background()
+			for i := 0; i < 100; i++ {
+				foo := &v1beta1.Foo{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("foo-%d", i), Labels: map[string]string{"app": "foo"}},
+					Spec:       v1beta1.FooSpec{Image: "busybox:1.36", Config: v1beta1.FooConfig{Msg: "hello world"}},
+				}
+				if _, err := foos.Create(ctx, foo, metav1.CreateOptions{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.Run("get", func(b *testing.B) {
+				recorder.reset()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := foos.Get(ctx, fmt.Sprintf("foo-%d", i%100), metav1.GetOptions{}); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(recorder.bytes)/float64(b.N), "response-bytes/op")
+			})
+			b.Run("list", func(b *testing.B) {
+				recorder.reset()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := foos.List(ctx, metav1.ListOptions{}); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(recorder.bytes)/float64(b.N), "response-bytes/op")
+			})
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/features"
+	"github.com/guodoliu/apiserver/pkg/generated/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/component-base/featuregate"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"net"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// freePort returns a local port nothing listens on.
+func freePort(t testing.TB) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// startTestServer runs the demo server with an embedded etcd, with
+// authentication, authorization and the backing cluster turned off, and
+// returns a client config for it once it is ready. Further flags are passed
+// on as given. The server stops when the test ends.
+func startTestServer(t testing.TB, flags ...string) *rest.Config {
+	t.Helper()
+	dir := t.TempDir()
+	port := freePort(t)
+	args := append([]string{
+		"--bind-address=127.0.0.1",
+		"--secure-port=" + strconv.Itoa(port),
+		"--cert-dir=" + filepath.Join(dir, "certs"),
+		"--embedded-etcd-data-dir=" + filepath.Join(dir, "etcd"),
+		fmt.Sprintf("--embedded-etcd-client-url=http://127.0.0.1:%d", freePort(t)),
+	}, flags...)
+
+	// --feature-gates sets the process wide gates, which later tests must not
+	// inherit
+	for _, gate := range []featuregate.Feature{features.FooStatusSubresource, features.ConfigResource} {
+		featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, gate, utilfeature.DefaultFeatureGate.Enabled(gate))
+	}
+
+	stopCh := make(chan struct{})
+	cmd := NewDemoServerCommand(stopCh)
+	cmd.SetArgs(args)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- cmd.Execute()
+	}()
+	t.Cleanup(func() {
+		close(stopCh)
+		select {
+		case err := <-errCh:
+			if err != nil {
+				t.Errorf("server failed: %v", err)
+			}
+		case <-time.After(time.Minute):
+			t.Errorf("server did not stop")
+		}
+	})
+
+	config := &rest.Config{
+		Host:            fmt.Sprintf("https://127.0.0.1:%d", port),
+		TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+		QPS:             -1,
+	}
+	client, err := rest.HTTPClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := wait.PollUntilContextCancel(ctx, 100*time.Millisecond, true, func(ctx context.Context) (bool, error) {
+		select {
+		case err := <-errCh:
+			errCh <- err
+			return false, fmt.Errorf("server exited: %v", err)
+		default:
+		}
+		resp, err := client.Get(config.Host + "/readyz")
+		if err != nil {
+			return false, nil
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK, nil
+	}); err != nil {
+		t.Fatalf("server did not become ready: %v", err)
+	}
+	return config
+}
+
+// TestDeprecatedFoos checks that requests against v1alpha1 foos and the
+// discovery of v1alpha1 warn that v1alpha1 Foos are deprecated.
+func TestDeprecatedFoos(t *testing.T) {
+	config := startTestServer(t, "--feature-gates=ConfigResource=true")
+	recorder := &warningRecorder{}
+	config.WarningHandler = recorder
+	client, err := versioned.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	want := []string{"demo.k8s.io/v1alpha1 Foo is deprecated in v1.30+, unavailable in v1.33+; use demo.k8s.io/v1beta1 Foo"}
+
+	tests := []struct {
+		name    string
+		request func() error
+		want    []string
+	}{
+		{
+			name: "v1alpha1 foos",
+			request: func() error {
+				_, err := client.DemoV1alpha1().Foos("default").List(ctx, metav1.ListOptions{})
+				return err
+			},
+			want: want,
+		},
+		{
+			name: "v1alpha1 discovery",
+			request: func() error {
+				_, err := discoveryClient.ServerResourcesForGroupVersion("demo.k8s.io/v1alpha1")
+				return err
+			},
+			want: want,
+		},
+		{
+			name: "v1alpha1 configs",
+			request: func() error {
+				_, err := client.DemoV1alpha1().Configs("default").List(ctx, metav1.ListOptions{})
+				return err
+			},
+		},
+		{
+			name: "v1beta1 foos",
+			request: func() error {
+				_, err := client.DemoV1beta1().Foos("default").List(ctx, metav1.ListOptions{})
+				return err
+			},
+		},
+		{
+			name: "v1beta1 discovery",
+			request: func() error {
+				_, err := discoveryClient.ServerResourcesForGroupVersion("demo.k8s.io/v1beta1")
+				return err
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder.mu.Lock()
+			recorder.warnings = nil
+			recorder.mu.Unlock()
+			if err := tc.request(); err != nil {
+				t.Fatal(err)
+			}
+			recorder.mu.Lock()
+			defer recorder.mu.Unlock()
+			if !slices.Equal(recorder.warnings, tc.want) {
+				t.Errorf("expected warnings %q, got %q", tc.want, recorder.warnings)
+			}
+		})
+	}
+}
+
+// TestRuntimeConfig checks that --runtime-config switches versions off.
+func TestRuntimeConfig(t *testing.T) {
+	config := startTestServer(t, "--runtime-config=demo.k8s.io/v1alpha1=false")
+	client, err := versioned.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if _, err := client.DemoV1alpha1().Foos("default").List(ctx, metav1.ListOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected v1alpha1 foos not to be served, got %v", err)
+	}
+	if _, err := client.DemoV1beta1().Foos("default").List(ctx, metav1.ListOptions{}); err != nil {
+		t.Errorf("expected v1beta1 foos to be served, got %v", err)
+	}
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, group := range groups.Groups {
+		if group.Name != "demo.k8s.io" {
+			continue
+		}
+		var versions []string
+		for _, version := range group.Versions {
+			versions = append(versions, version.Version)
+		}
+		if !slices.Equal(versions, []string{"v1beta1"}) || group.PreferredVersion.Version != "v1beta1" {
+			t.Errorf("expected only v1beta1 to be discovered, got %v preferring %s", versions, group.PreferredVersion.Version)
+		}
+		return
+	}
+	t.Errorf("expected demo.k8s.io to be discovered, got %v", groups.Groups)
+}
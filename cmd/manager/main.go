@@ -1 +0,0 @@
-../../main.go
\ No newline at end of file
@@ -0,0 +1,174 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// The manager runs the demo controllers outside the demo server, against a
+// cluster that serves the demo API. It runs the same controllers as
+// --enable-embedded-controllers and takes the same lease.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	configv1alpha1 "github.com/guodoliu/apiserver/pkg/apis/config/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"github.com/guodoliu/apiserver/pkg/apiserver"
+	foocontroller "github.com/guodoliu/apiserver/pkg/controller/foo"
+	clientset "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned"
+	informers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions"
+	demov1alpha1informers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions/demo/v1alpha1"
+	"github.com/spf13/pflag"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	componentbaseconfig "k8s.io/component-base/config"
+	componentbaseoptions "k8s.io/component-base/config/options"
+	"k8s.io/klog/v2"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+)
+
+type options struct {
+	KubeConfig             string
+	HealthProbeBindAddress string
+	ConcurrentFooSyncs     int
+	LeaderElection         componentbaseconfig.LeaderElectionConfiguration
+}
+
+func main() {
+	o := &options{
+		HealthProbeBindAddress: ":8081",
+		ConcurrentFooSyncs:     configv1alpha1.DefaultConcurrentFooSyncs,
+		LeaderElection: componentbaseconfig.LeaderElectionConfiguration{
+			LeaderElect:       true,
+			LeaseDuration:     metav1.Duration{Duration: 15 * time.Second},
+			RenewDeadline:     metav1.Duration{Duration: 10 * time.Second},
+			RetryPeriod:       metav1.Duration{Duration: 2 * time.Second},
+			ResourceLock:      resourcelock.LeasesResourceLock,
+			ResourceName:      configv1alpha1.DefaultLeaderElectionResourceName,
+			ResourceNamespace: configv1alpha1.DefaultLeaderElectionNamespace,
+		},
+	}
+	fs := pflag.CommandLine
+	fs.StringVar(&o.KubeConfig, "kubeconfig", o.KubeConfig, "The path to the kubeconfig of the cluster serving the demo API, the in-cluster config if empty")
+	fs.StringVar(&o.HealthProbeBindAddress, "health-probe-bind-address", o.HealthProbeBindAddress, "The address /healthz is served on")
+	fs.IntVar(&o.ConcurrentFooSyncs, "concurrent-foo-syncs", o.ConcurrentFooSyncs, "The number of Foos that are synced concurrently")
+	componentbaseoptions.BindLeaderElectionFlags(&o.LeaderElection, fs)
+	klogFlags := flag.NewFlagSet("klog", flag.ExitOnError)
+	klog.InitFlags(klogFlags)
+	fs.AddGoFlagSet(klogFlags)
+	pflag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	if err := o.run(ctx); err != nil {
+		klog.ErrorS(err, "Unable to run the manager")
+		os.Exit(1)
+	}
+}
+
+func (o *options) run(ctx context.Context) error {
+	if o.ConcurrentFooSyncs <= 0 {
+		return fmt.Errorf("--concurrent-foo-syncs must be greater than 0")
+	}
+	config, err := clientcmd.BuildConfigFromFlags("", o.KubeConfig)
+	if err != nil {
+		return fmt.Errorf("unable to load the kubeconfig: %v", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	demoClient, err := clientset.NewForConfig(apiserver.DemoClientConfig(config))
+	if err != nil {
+		return err
+	}
+
+	// like the embedded controllers, work on v1beta1 Foos and fall back to
+	// v1alpha1 if the cluster serves only those
+	factory := informers.NewSharedInformerFactory(demoClient, 0)
+	var foos foocontroller.Foos
+	switch {
+	case served(kubeClient.Discovery(), v1beta1.SchemeGroupVersion, "foos"):
+		foos = foocontroller.NewV1beta1Foos(demoClient, factory.Demo().V1beta1().Foos())
+	case served(kubeClient.Discovery(), v1alpha1.SchemeGroupVersion, "foos"):
+		foos = foocontroller.NewV1alpha1Foos(demoClient, factory.Demo().V1alpha1().Foos())
+	default:
+		return fmt.Errorf("the cluster does not serve foos.%s", v1beta1.GroupName)
+	}
+	var configInformer demov1alpha1informers.ConfigInformer
+	if served(kubeClient.Discovery(), v1alpha1.SchemeGroupVersion, "configs") {
+		configInformer = factory.Demo().V1alpha1().Configs()
+		configInformer.Informer()
+	}
+	factory.Start(ctx.Done())
+	defer factory.Shutdown()
+
+	checks := []healthz.HealthChecker{healthz.PingHealthz}
+	var watchdog *leaderelection.HealthzAdaptor
+	if o.LeaderElection.LeaderElect {
+		watchdog = leaderelection.NewLeaderHealthzAdaptor(20 * time.Second)
+		checks = append(checks, watchdog)
+	}
+	mux := http.NewServeMux()
+	healthz.InstallHandler(mux, checks...)
+	server := &http.Server{Addr: o.HealthProbeBindAddress, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			klog.ErrorS(err, "Unable to serve the health probes")
+		}
+	}()
+	defer server.Close()
+
+	cfg := &apiserver.ControllersConfig{
+		KubeClient:         kubeClient,
+		ConcurrentFooSyncs: o.ConcurrentFooSyncs,
+		LeaderElection:     o.LeaderElection,
+	}
+	err = apiserver.RunControllers(ctx, cfg, watchdog, func(ctx context.Context) {
+		foocontroller.NewController(foos, kubeClient, configInformer).Run(ctx, cfg.ConcurrentFooSyncs)
+	})
+	if err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+// served reports whether the cluster serves resource in gv.
+func served(client discovery.DiscoveryInterface, gv schema.GroupVersion, resource string) bool {
+	resources, err := client.ServerResourcesForGroupVersion(gv.String())
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Unable to discover the demo API", "groupVersion", gv)
+		}
+		return false
+	}
+	return slices.ContainsFunc(resources.APIResources, func(r metav1.APIResource) bool {
+		return r.Name == resource
+	})
+}
@@ -28,6 +28,7 @@ source "${CODEGEN_PKG}/kube_codegen.sh"
 THIS_PKG="github.com/guodoliu/apiserver"
 
 kube::codegen::gen_helpers \
+    --extra-peer-dir k8s.io/component-base/config/v1alpha1 \
     --boilerplate "${SCRIPT_ROOT}/hack/boilerplate.go.txt" \
     "${SCRIPT_ROOT}/pkg/apis"
 
@@ -35,13 +36,52 @@ kube::codegen::gen_openapi \
     --output-dir "${SCRIPT_ROOT}/pkg/generated/openapi" \
     --output-pkg "${THIS_PKG}/pkg/generated/openapi" \
     --report-filename "${report_filename:-"/dev/null"}" \
+    --extra-pkgs k8s.io/api/autoscaling/v1 \
     ${update_report:+"${update_report}"} \
     --boilerplate "${SCRIPT_ROOT}/hack/boilerplate.go.txt" \
     "${SCRIPT_ROOT}/pkg/apis"
 
+# applyconfiguration-gen embeds the OpenAPI schema of the types generated above
+MODELS_SCHEMA=$(mktemp)
+trap 'rm -f "${MODELS_SCHEMA}"' EXIT
+(cd "${SCRIPT_ROOT}"; go run ./hack/models-schema) > "${MODELS_SCHEMA}"
+
 kube::codegen::gen_client \
     --with-watch \
+    --with-applyconfig \
+    --plural-exceptions "FooDefaults:FooDefaults" \
+    --applyconfig-openapi-schema "${MODELS_SCHEMA}" \
     --output-dir "${SCRIPT_ROOT}/pkg/generated" \
     --output-pkg "${THIS_PKG}/pkg/generated" \
     --boilerplate "${SCRIPT_ROOT}/hack/boilerplate.go.txt" \
-    "${SCRIPT_ROOT}/pkg/apis"
\ No newline at end of file
+    "${SCRIPT_ROOT}/pkg/apis"
+# go-to-protobuf needs protoc on the PATH, see
+# https://github.com/protocolbuffers/protobuf/releases. Set SKIP_PROTOBUF to
+# regenerate everything else without it.
+if [[ -z "${SKIP_PROTOBUF:-}" ]]; then
+    if ! command -v protoc >/dev/null; then
+        echo "protoc not found; install it or set SKIP_PROTOBUF=1" >&2
+        exit 1
+    fi
+    PROTO_BIN=$(mktemp -d)
+    PROTO_SRC=$(mktemp -d)
+    trap 'rm -rf "${MODELS_SCHEMA}" "${PROTO_BIN}" "${PROTO_SRC}"' EXIT
+    GOBIN="${PROTO_BIN}" go install \
+        k8s.io/code-generator/cmd/go-to-protobuf \
+        k8s.io/code-generator/cmd/go-to-protobuf/protoc-gen-gogo
+
+    # go-to-protobuf writes below a GOPATH style source tree, which is also
+    # where protoc finds the apimachinery and gogoproto definitions the demo
+    # types import, linked from the module cache
+    mkdir -p "${PROTO_SRC}/$(dirname "${THIS_PKG}")" "${PROTO_SRC}/k8s.io" "${PROTO_SRC}/github.com/gogo"
+    ln -s "$(cd "${SCRIPT_ROOT}"; pwd)" "${PROTO_SRC}/${THIS_PKG}"
+    ln -s "$(cd "${SCRIPT_ROOT}"; go list -m -f '{{.Dir}}' k8s.io/apimachinery)" "${PROTO_SRC}/k8s.io/apimachinery"
+    ln -s "$(cd "${SCRIPT_ROOT}"; go list -m -f '{{.Dir}}' github.com/gogo/protobuf)" "${PROTO_SRC}/github.com/gogo/protobuf"
+
+    PATH="${PROTO_BIN}:${PATH}" "${PROTO_BIN}/go-to-protobuf" \
+        --go-header-file "${SCRIPT_ROOT}/hack/boilerplate.go.txt" \
+        --output-dir "${PROTO_SRC}" \
+        --proto-import "${PROTO_SRC}/github.com/gogo/protobuf/protobuf" \
+        --apimachinery-packages "-k8s.io/apimachinery/pkg/util/intstr,-k8s.io/apimachinery/pkg/api/resource,-k8s.io/apimachinery/pkg/runtime/schema,-k8s.io/apimachinery/pkg/runtime,-k8s.io/apimachinery/pkg/apis/meta/v1" \
+        --packages "${THIS_PKG}/pkg/apis/demo/v1alpha1,${THIS_PKG}/pkg/apis/demo/v1beta1"
+fi
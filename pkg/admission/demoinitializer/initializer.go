@@ -0,0 +1,53 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package demoinitializer
+
+import (
+	informers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions"
+	"k8s.io/apiserver/pkg/admission"
+	serverstorage "k8s.io/apiserver/pkg/server/storage"
+)
+
+type pluginInitializer struct {
+	informers      informers.SharedInformerFactory
+	resourceConfig *serverstorage.ResourceConfig
+}
+
+var _ admission.PluginInitializer = pluginInitializer{}
+
+// New creates an instance of demo admission plugins initializer.
+// The informers are started by the server once admission has been set up,
+// so plugins should only register the listers they need here.
+func New(informers informers.SharedInformerFactory, resourceConfig *serverstorage.ResourceConfig) pluginInitializer {
+	return pluginInitializer{
+		informers:      informers,
+		resourceConfig: resourceConfig,
+	}
+}
+
+// Initialize checks the initialization interfaces implemented by a plugin
+// and provide the appropriate initialization data. The resource config comes
+// first, so that plugins can leave out the informers of resources that are
+// not served and would never sync.
+func (i pluginInitializer) Initialize(plugin admission.Interface) {
+	if wants, ok := plugin.(WantsAPIResourceConfig); ok {
+		wants.SetAPIResourceConfig(i.resourceConfig)
+	}
+	if wants, ok := plugin.(WantsDemoInformerFactory); ok {
+		wants.SetDemoInformerFactory(i.informers)
+	}
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package demoinitializer
+
+import (
+	informers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions"
+	"k8s.io/apiserver/pkg/admission"
+	serverstorage "k8s.io/apiserver/pkg/server/storage"
+)
+
+// WantsDemoInformerFactory defines a function which sets InformerFactory for admission plugins that need it
+type WantsDemoInformerFactory interface {
+	SetDemoInformerFactory(informers.SharedInformerFactory)
+	admission.InitializationValidator
+}
+
+// WantsAPIResourceConfig defines a function which tells admission plugins which demo resources are served
+type WantsAPIResourceConfig interface {
+	SetAPIResourceConfig(*serverstorage.ResourceConfig)
+	admission.InitializationValidator
+}
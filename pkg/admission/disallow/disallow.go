@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/features"
 	"io"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/admission/initializer"
+	"k8s.io/component-base/featuregate"
 )
 
 func Register(plugins *admission.Plugins) {
@@ -23,13 +26,38 @@ func New() (admission.Interface, error) {
 }
 
 var _ admission.Interface = &DisallowFoo{}
+var _ initializer.WantsFeatures = &DisallowFoo{}
 
 type DisallowFoo struct {
 	admission.Handler
+
+	inspectedFeatureGates bool
+	configResourceEnabled bool
+}
+
+// InspectFeatureGates allows setting bools without taking a dep on a global variable
+func (d *DisallowFoo) InspectFeatureGates(featureGates featuregate.FeatureGate) {
+	d.configResourceEnabled = featureGates.Enabled(features.ConfigResource)
+	d.inspectedFeatureGates = true
+}
+
+// ValidateInitialization ensures that the feature gates have been inspected
+func (d *DisallowFoo) ValidateInitialization() error {
+	if !d.inspectedFeatureGates {
+		return fmt.Errorf("%s did not see feature gates", "DisallowFoo")
+	}
+	return nil
 }
 
 func (d *DisallowFoo) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) (err error) {
-	if a.GetKind().GroupKind() != demo.SchemeGroupVersion.WithKind("Foo").GroupKind() {
+	switch a.GetKind().GroupKind() {
+	case demo.Kind("Foo"):
+	case demo.Kind("Config"):
+		// Configs are only served with the ConfigResource feature gate
+		if !d.configResourceEnabled {
+			return nil
+		}
+	default:
 		return nil
 	}
 
@@ -0,0 +1,301 @@
+package foodefaults
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/admission/demoinitializer"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	informers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions"
+	demolisters "github.com/guodoliu/apiserver/pkg/generated/listers/demo/v1beta1"
+	"io"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/admission"
+	serverstorage "k8s.io/apiserver/pkg/server/storage"
+	"k8s.io/apiserver/pkg/warning"
+	"sort"
+	"strings"
+)
+
+// PluginName is the name of the plugin in --enable-admission-plugins and
+// --disable-admission-plugins. The plugin does nothing while foodefaults are
+// turned off with --runtime-config.
+const PluginName = "FooDefaults"
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(config io.Reader) (admission.Interface, error) {
+		return New()
+	})
+}
+
+func New() (admission.Interface, error) {
+	return &FooDefaults{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+	}, nil
+}
+
+var (
+	_ admission.MutationInterface              = &FooDefaults{}
+	_ admission.ValidationInterface            = &FooDefaults{}
+	_ demoinitializer.WantsAPIResourceConfig   = &FooDefaults{}
+	_ demoinitializer.WantsDemoInformerFactory = &FooDefaults{}
+)
+
+// FooDefaults merges the FooDefaults of a namespace that select a new Foo
+// into it. Each field the Foo leaves empty is taken from the first FooDefaults
+// that sets it, in order of descending priority and then of name, and the
+// FooDefaultsAnnotation records where it came from. Writing a FooDefaults
+// warns about the others that could supply the same fields to the same Foos
+// at the same priority, where only the names decide.
+type FooDefaults struct {
+	*admission.Handler
+
+	inspectedResourceConfig bool
+	served                  bool
+	lister                  demolisters.FooDefaultsLister
+}
+
+// SetAPIResourceConfig tells whether foodefaults are served. Without them
+// there is nothing to merge, and their informer would never sync.
+func (p *FooDefaults) SetAPIResourceConfig(config *serverstorage.ResourceConfig) {
+	p.served = config.ResourceEnabled(v1beta1.SchemeGroupVersion.WithResource("foodefaults"))
+	p.inspectedResourceConfig = true
+}
+
+func (p *FooDefaults) SetDemoInformerFactory(f informers.SharedInformerFactory) {
+	if !p.served {
+		return
+	}
+	informer := f.Demo().V1beta1().FooDefaults()
+	p.lister = informer.Lister()
+	p.SetReadyFunc(informer.Informer().HasSynced)
+}
+
+// ValidateInitialization ensures that the plugin saw the served resources and
+// got the demo informers
+func (p *FooDefaults) ValidateInitialization() error {
+	if !p.inspectedResourceConfig {
+		return fmt.Errorf("%s did not see the served resources", PluginName)
+	}
+	if p.served && p.lister == nil {
+		return fmt.Errorf("%s did not get a FooDefaults lister", PluginName)
+	}
+	return nil
+}
+
+// Handles skips every request while foodefaults are not served.
+func (p *FooDefaults) Handles(operation admission.Operation) bool {
+	return p.served && p.Handler.Handles(operation)
+}
+
+func (p *FooDefaults) Admit(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	if a.GetKind().GroupKind() != demo.Kind("Foo") || a.GetOperation() != admission.Create || len(a.GetSubresource()) > 0 {
+		return nil
+	}
+	foo, ok := a.GetObject().(*demo.Foo)
+	if !ok {
+		return errors.NewBadRequest(fmt.Sprintf("not a Foo: %T", a.GetObject()))
+	}
+	if !p.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	all, err := p.lister.FooDefaults(a.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return errors.NewInternalError(err)
+	}
+	selecting, err := selectingInOrder(all, foo)
+	if err != nil {
+		return errors.NewInternalError(err)
+	}
+	if applied := merge(foo, selecting); len(applied) > 0 {
+		metav1.SetMetaDataAnnotation(&foo.ObjectMeta, v1beta1.FooDefaultsAnnotation, strings.Join(applied, ","))
+	}
+	return nil
+}
+
+// Validate warns about the FooDefaults that take precedence over a written
+// one, or lose to it, by name only.
+func (p *FooDefaults) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	if a.GetKind().GroupKind() != demo.Kind("FooDefaults") || len(a.GetSubresource()) > 0 {
+		return nil
+	}
+	defaults := &v1beta1.FooDefaults{}
+	if err := o.GetObjectConvertor().Convert(a.GetObject(), defaults, nil); err != nil {
+		return errors.NewInternalError(err)
+	}
+	if !p.WaitForReady() {
+		return admission.NewForbidden(a, fmt.Errorf("not yet ready to handle request"))
+	}
+
+	all, err := p.lister.FooDefaults(a.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return errors.NewInternalError(err)
+	}
+	for _, w := range conflicts(defaults, all) {
+		warning.AddWarning(ctx, "", w)
+	}
+	return nil
+}
+
+// conflicts describes the FooDefaults other than defaults that have its
+// priority, set some of its fields and may select the same Foos.
+func conflicts(defaults *v1beta1.FooDefaults, all []*v1beta1.FooDefaults) []string {
+	fields := fieldsSet(&defaults.Spec)
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	var warnings []string
+	for _, other := range all {
+		if other.Name == defaults.Name || other.Spec.Priority != defaults.Spec.Priority {
+			continue
+		}
+		shared := fields.Intersection(fieldsSet(&other.Spec))
+		if shared.Len() == 0 || !mayOverlap(defaults.Spec.Selector, other.Spec.Selector) {
+			continue
+		}
+		first := defaults.Name
+		if other.Name < first {
+			first = other.Name
+		}
+		warnings = append(warnings, fmt.Sprintf("FooDefaults %s also sets %s at priority %d and may select the same Foos; %s supplies them, being first by name",
+			other.Name, strings.Join(sets.List(shared), ", "), defaults.Spec.Priority, first))
+	}
+	return warnings
+}
+
+// fieldsSet returns the fields of a Foo that spec may supply.
+func fieldsSet(spec *v1beta1.FooDefaultsSpec) sets.Set[string] {
+	fields := sets.New[string]()
+	if len(spec.ImageRegistry) > 0 {
+		fields.Insert("imageRegistry")
+	}
+	if len(spec.ImageTag) > 0 {
+		fields.Insert("imageTag")
+	}
+	if len(spec.Msg) > 0 {
+		fields.Insert("msg")
+	}
+	for key := range spec.Labels {
+		fields.Insert("labels." + key)
+	}
+	return fields
+}
+
+// mayOverlap tells whether some labels could match both selectors. It only
+// tells the selectors apart by the keys they require with or without
+// values, so it may report an overlap where there is none, but never
+// misses one. A nil selector selects everything.
+func mayOverlap(a, b *metav1.LabelSelector) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	sa, err := metav1.LabelSelectorAsSelector(a)
+	if err != nil {
+		return true
+	}
+	sb, err := metav1.LabelSelectorAsSelector(b)
+	if err != nil {
+		return true
+	}
+	ra, _ := sa.Requirements()
+	rb, _ := sb.Requirements()
+	for _, x := range ra {
+		for _, y := range rb {
+			if x.Key() == y.Key() && (excludes(x, y) || excludes(y, x)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// excludes tells whether no value of a label, or its absence, meets both x
+// and y.
+func excludes(x, y labels.Requirement) bool {
+	switch x.Operator() {
+	case selection.In, selection.Equals, selection.DoubleEquals:
+		switch y.Operator() {
+		case selection.DoesNotExist:
+			return true
+		case selection.In, selection.Equals, selection.DoubleEquals:
+			return !x.Values().HasAny(y.Values().UnsortedList()...)
+		case selection.NotIn, selection.NotEquals:
+			return y.Values().IsSuperset(x.Values())
+		}
+	case selection.Exists:
+		return y.Operator() == selection.DoesNotExist
+	}
+	return false
+}
+
+// selectingInOrder returns the FooDefaults that select foo, by its labels
+// before any are defaulted, highest priority first and then by name.
+func selectingInOrder(all []*v1beta1.FooDefaults, foo *demo.Foo) ([]*v1beta1.FooDefaults, error) {
+	var selecting []*v1beta1.FooDefaults
+	for _, defaults := range all {
+		if defaults.Spec.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(defaults.Spec.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector of FooDefaults %s/%s: %v", defaults.Namespace, defaults.Name, err)
+			}
+			if !selector.Matches(labels.Set(foo.Labels)) {
+				continue
+			}
+		}
+		selecting = append(selecting, defaults)
+	}
+	sort.Slice(selecting, func(i, j int) bool {
+		if selecting[i].Spec.Priority != selecting[j].Spec.Priority {
+			return selecting[i].Spec.Priority > selecting[j].Spec.Priority
+		}
+		return selecting[i].Name < selecting[j].Name
+	})
+	return selecting, nil
+}
+
+// merge sets the fields foo leaves empty from the first of defaults that sets
+// them. It returns the fields it set with the FooDefaults each came from, as
+// in "imageRegistry=registry".
+func merge(foo *demo.Foo, defaults []*v1beta1.FooDefaults) []string {
+	var applied []string
+	for _, d := range defaults {
+		if len(d.Spec.ImageRegistry) > 0 && len(foo.Spec.Image) > 0 && !hasRegistry(foo.Spec.Image) {
+			foo.Spec.Image = d.Spec.ImageRegistry + "/" + foo.Spec.Image
+			applied = append(applied, "imageRegistry="+d.Name)
+		}
+		if len(d.Spec.ImageTag) > 0 && len(foo.Spec.Image) > 0 && !hasTagOrDigest(foo.Spec.Image) {
+			foo.Spec.Image = foo.Spec.Image + ":" + d.Spec.ImageTag
+			applied = append(applied, "imageTag="+d.Name)
+		}
+		if len(d.Spec.Msg) > 0 && len(foo.Spec.Config.Msg) == 0 {
+			foo.Spec.Config.Msg = d.Spec.Msg
+			applied = append(applied, "msg="+d.Name)
+		}
+		keys := make([]string, 0, len(d.Spec.Labels))
+		for key := range d.Spec.Labels {
+			if _, ok := foo.Labels[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			metav1.SetMetaDataLabel(&foo.ObjectMeta, key, d.Spec.Labels[key])
+			applied = append(applied, "labels."+key+"="+d.Name)
+		}
+	}
+	return applied
+}
+
+// hasRegistry tells whether the first component of image is a registry host
+// rather than part of the name, the way container runtimes tell.
+func hasRegistry(image string) bool {
+	host, _, found := strings.Cut(image, "/")
+	return found && (strings.ContainsAny(host, ".:") || host == "localhost")
+}
+
+func hasTagOrDigest(image string) bool {
+	return strings.Contains(image, "@") || strings.LastIndex(image, ":") > strings.LastIndex(image, "/")
+}
@@ -0,0 +1,273 @@
+package foodefaults
+
+import (
+	"context"
+	"github.com/guodoliu/apiserver/pkg/admission/demoinitializer"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/fake"
+	informers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+	serverstorage "k8s.io/apiserver/pkg/server/storage"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newFooDefaults(name string, priority int32, selector *metav1.LabelSelector, spec v1beta1.FooDefaultsSpec) *v1beta1.FooDefaults {
+	spec.Priority = priority
+	spec.Selector = selector
+	return &v1beta1.FooDefaults{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}, Spec: spec}
+}
+
+func matchLabels(keysAndValues ...string) *metav1.LabelSelector {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{}}
+	for i := 0; i < len(keysAndValues); i += 2 {
+		selector.MatchLabels[keysAndValues[i]] = keysAndValues[i+1]
+	}
+	return selector
+}
+
+func names(defaults []*v1beta1.FooDefaults) []string {
+	var names []string
+	for _, d := range defaults {
+		names = append(names, d.Name)
+	}
+	return names
+}
+
+func TestSelectingInOrder(t *testing.T) {
+	all := []*v1beta1.FooDefaults{
+		newFooDefaults("b-low", 1, nil, v1beta1.FooDefaultsSpec{}),
+		newFooDefaults("a-low", 1, nil, v1beta1.FooDefaultsSpec{}),
+		newFooDefaults("high", 10, nil, v1beta1.FooDefaultsSpec{}),
+		newFooDefaults("team-a", 5, matchLabels("team", "a"), v1beta1.FooDefaultsSpec{}),
+		newFooDefaults("not-team-a", 5, &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "team", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"a"}},
+		}}, v1beta1.FooDefaultsSpec{}),
+		newFooDefaults("empty", 0, &metav1.LabelSelector{}, v1beta1.FooDefaultsSpec{}),
+	}
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   []string
+	}{
+		{name: "no labels", want: []string{"high", "not-team-a", "a-low", "b-low", "empty"}},
+		{name: "team a", labels: map[string]string{"team": "a"}, want: []string{"high", "team-a", "a-low", "b-low", "empty"}},
+		{name: "team b", labels: map[string]string{"team": "b"}, want: []string{"high", "not-team-a", "a-low", "b-low", "empty"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := selectingInOrder(all, &demo.Foo{ObjectMeta: metav1.ObjectMeta{Labels: tc.labels}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(names(got), tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, names(got))
+			}
+		})
+	}
+
+	invalid := newFooDefaults("invalid", 0, &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "team", Operator: "Near"},
+	}}, v1beta1.FooDefaultsSpec{})
+	if _, err := selectingInOrder([]*v1beta1.FooDefaults{invalid}, &demo.Foo{}); err == nil {
+		t.Error("expected an invalid selector to fail")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	registry := newFooDefaults("registry", 0, nil, v1beta1.FooDefaultsSpec{ImageRegistry: "registry.example.com"})
+	tag := newFooDefaults("tag", 0, nil, v1beta1.FooDefaultsSpec{ImageTag: "1.36"})
+	otherTag := newFooDefaults("other-tag", 0, nil, v1beta1.FooDefaultsSpec{ImageTag: "1.35"})
+	msg := newFooDefaults("msg", 0, nil, v1beta1.FooDefaultsSpec{Msg: "hi"})
+	team := newFooDefaults("team", 0, nil, v1beta1.FooDefaultsSpec{Labels: map[string]string{"team": "a", "tier": "web"}})
+	otherTeam := newFooDefaults("other-team", 0, nil, v1beta1.FooDefaultsSpec{Labels: map[string]string{"team": "b", "zone": "z1"}})
+
+	tests := []struct {
+		name     string
+		foo      demo.Foo
+		defaults []*v1beta1.FooDefaults
+		want     demo.Foo
+		applied  []string
+	}{
+		{
+			name: "nothing to merge",
+			foo:  demo.Foo{Spec: demo.FooSpec{Image: "busybox"}},
+			want: demo.Foo{Spec: demo.FooSpec{Image: "busybox"}},
+		},
+		{
+			name:     "registry and tag",
+			foo:      demo.Foo{Spec: demo.FooSpec{Image: "busybox"}},
+			defaults: []*v1beta1.FooDefaults{registry, tag},
+			want:     demo.Foo{Spec: demo.FooSpec{Image: "registry.example.com/busybox:1.36"}},
+			applied:  []string{"imageRegistry=registry", "imageTag=tag"},
+		},
+		{
+			name:     "image with registry and tag",
+			foo:      demo.Foo{Spec: demo.FooSpec{Image: "localhost:5000/busybox:1.34"}},
+			defaults: []*v1beta1.FooDefaults{registry, tag},
+			want:     demo.Foo{Spec: demo.FooSpec{Image: "localhost:5000/busybox:1.34"}},
+		},
+		{
+			name:     "image with digest",
+			foo:      demo.Foo{Spec: demo.FooSpec{Image: "team/busybox@sha256:9ae97d36d26566ff84e8893c64a6dc4fe8ca6d1144bf5b87b2b85a32def253c7"}},
+			defaults: []*v1beta1.FooDefaults{tag},
+			want:     demo.Foo{Spec: demo.FooSpec{Image: "team/busybox@sha256:9ae97d36d26566ff84e8893c64a6dc4fe8ca6d1144bf5b87b2b85a32def253c7"}},
+		},
+		{
+			name:     "no image",
+			foo:      demo.Foo{},
+			defaults: []*v1beta1.FooDefaults{registry, tag},
+			want:     demo.Foo{},
+		},
+		{
+			name:     "first tag wins",
+			foo:      demo.Foo{Spec: demo.FooSpec{Image: "busybox"}},
+			defaults: []*v1beta1.FooDefaults{otherTag, tag},
+			want:     demo.Foo{Spec: demo.FooSpec{Image: "busybox:1.35"}},
+			applied:  []string{"imageTag=other-tag"},
+		},
+		{
+			name:     "msg",
+			foo:      demo.Foo{Spec: demo.FooSpec{Image: "busybox:1.36"}},
+			defaults: []*v1beta1.FooDefaults{msg},
+			want:     demo.Foo{Spec: demo.FooSpec{Image: "busybox:1.36", Config: demo.FooConfig{Msg: "hi"}}},
+			applied:  []string{"msg=msg"},
+		},
+		{
+			name:     "msg given",
+			foo:      demo.Foo{Spec: demo.FooSpec{Config: demo.FooConfig{Msg: "hello"}}},
+			defaults: []*v1beta1.FooDefaults{msg},
+			want:     demo.Foo{Spec: demo.FooSpec{Config: demo.FooConfig{Msg: "hello"}}},
+		},
+		{
+			name:     "labels by key",
+			foo:      demo.Foo{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"tier": "db"}}},
+			defaults: []*v1beta1.FooDefaults{team, otherTeam},
+			want:     demo.Foo{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a", "tier": "db", "zone": "z1"}}},
+			applied:  []string{"labels.team=team", "labels.zone=other-team"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			foo := tc.foo.DeepCopy()
+			applied := merge(foo, tc.defaults)
+			if !reflect.DeepEqual(*foo, tc.want) {
+				t.Errorf("expected %+v, got %+v", tc.want, *foo)
+			}
+			if !reflect.DeepEqual(applied, tc.applied) {
+				t.Errorf("expected applied %v, got %v", tc.applied, applied)
+			}
+		})
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	expressions := func(requirements ...metav1.LabelSelectorRequirement) *metav1.LabelSelector {
+		return &metav1.LabelSelector{MatchExpressions: requirements}
+	}
+	tag := v1beta1.FooDefaultsSpec{ImageTag: "1.36"}
+	written := newFooDefaults("b", 5, matchLabels("team", "a"), v1beta1.FooDefaultsSpec{ImageTag: "1.36", Labels: map[string]string{"tier": "web"}})
+
+	tests := []struct {
+		name  string
+		other *v1beta1.FooDefaults
+		// want is a substring of the expected warning, if any
+		want string
+	}{
+		{name: "itself", other: newFooDefaults("b", 5, nil, tag)},
+		{name: "other priority", other: newFooDefaults("a", 4, nil, tag)},
+		{name: "other fields", other: newFooDefaults("a", 5, nil, v1beta1.FooDefaultsSpec{Msg: "hi"})},
+		{name: "other labels", other: newFooDefaults("a", 5, nil, v1beta1.FooDefaultsSpec{Labels: map[string]string{"zone": "z1"}})},
+		{name: "selects everything", other: newFooDefaults("a", 5, nil, tag), want: "FooDefaults a also sets imageTag at priority 5 and may select the same Foos; a supplies them"},
+		{name: "comes later by name", other: newFooDefaults("c", 5, nil, tag), want: "b supplies them"},
+		{name: "same label", other: newFooDefaults("a", 5, nil, v1beta1.FooDefaultsSpec{Labels: map[string]string{"tier": "db"}}), want: "sets labels.tier"},
+		{name: "other key", other: newFooDefaults("a", 5, matchLabels("zone", "z1"), tag), want: "imageTag"},
+		{name: "other value", other: newFooDefaults("a", 5, matchLabels("team", "b"), tag)},
+		{name: "in", other: newFooDefaults("a", 5, expressions(metav1.LabelSelectorRequirement{Key: "team", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}}), tag), want: "imageTag"},
+		{name: "not in", other: newFooDefaults("a", 5, expressions(metav1.LabelSelectorRequirement{Key: "team", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"a"}}), tag)},
+		{name: "not in others", other: newFooDefaults("a", 5, expressions(metav1.LabelSelectorRequirement{Key: "team", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"b"}}), tag), want: "imageTag"},
+		{name: "does not exist", other: newFooDefaults("a", 5, expressions(metav1.LabelSelectorRequirement{Key: "team", Operator: metav1.LabelSelectorOpDoesNotExist}), tag)},
+		{name: "exists", other: newFooDefaults("a", 5, expressions(metav1.LabelSelectorRequirement{Key: "team", Operator: metav1.LabelSelectorOpExists}), tag), want: "imageTag"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := conflicts(written, []*v1beta1.FooDefaults{tc.other})
+			switch {
+			case len(tc.want) == 0 && len(got) > 0:
+				t.Errorf("expected no warnings, got %q", got)
+			case len(tc.want) > 0 && (len(got) != 1 || !strings.Contains(got[0], tc.want)):
+				t.Errorf("expected a warning containing %q, got %q", tc.want, got)
+			}
+		})
+	}
+
+	// DoesNotExist only excludes selectors that need the key
+	if !mayOverlap(expressions(metav1.LabelSelectorRequirement{Key: "team", Operator: metav1.LabelSelectorOpDoesNotExist}),
+		expressions(metav1.LabelSelectorRequirement{Key: "team", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"a"}})) {
+		t.Error("expected a missing label to meet both DoesNotExist and NotIn")
+	}
+}
+
+func TestInitialization(t *testing.T) {
+	fooDefaults := v1beta1.SchemeGroupVersion.WithResource("foodefaults")
+	tests := []struct {
+		name   string
+		served bool
+	}{
+		{name: "served", served: true},
+		{name: "not served"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resourceConfig := serverstorage.NewResourceConfig()
+			resourceConfig.EnableVersions(v1beta1.SchemeGroupVersion)
+			if !tc.served {
+				resourceConfig.DisableResources(fooDefaults)
+			}
+			// created rather than passed to the fake clientset, which would
+			// guess the resource foodefaultses
+			client := fake.NewSimpleClientset()
+			if _, err := client.DemoV1beta1().FooDefaults("default").Create(context.Background(),
+				newFooDefaults("tag", 0, nil, v1beta1.FooDefaultsSpec{ImageTag: "1.36"}), metav1.CreateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			factory := informers.NewSharedInformerFactory(client, 0)
+			plugin, _ := New()
+			demoinitializer.New(factory, resourceConfig).Initialize(plugin)
+			if err := admission.ValidateInitialization(plugin); err != nil {
+				t.Fatal(err)
+			}
+			if handles := plugin.Handles(admission.Create); handles != tc.served {
+				t.Errorf("expected the plugin to handle creates %v, got %v", tc.served, handles)
+			}
+
+			// only served foodefaults have an informer, which syncs
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			factory.Start(stopCh)
+			synced := factory.WaitForCacheSync(stopCh)
+			if _, ok := synced[reflect.TypeOf(&v1beta1.FooDefaults{})]; ok != tc.served {
+				t.Fatalf("expected a FooDefaults informer %v, got %v", tc.served, synced)
+			}
+			if !tc.served {
+				return
+			}
+
+			foo := &demo.Foo{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "one"}, Spec: demo.FooSpec{Image: "busybox"}}
+			a := admission.NewAttributesRecord(foo, nil, demo.Kind("Foo").WithVersion(""), "default", "one",
+				demo.Resource("foos").WithVersion(""), "", admission.Create, &metav1.CreateOptions{}, false, nil)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := plugin.(admission.MutationInterface).Admit(ctx, a, nil); err != nil {
+				t.Fatal(err)
+			}
+			if foo.Spec.Image != "busybox:1.36" {
+				t.Errorf("expected the tag to be defaulted, got %q", foo.Spec.Image)
+			}
+		})
+	}
+}
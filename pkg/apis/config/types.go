@@ -0,0 +1,118 @@
+package config
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	componentbaseconfig "k8s.io/component-base/config"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DemoServerConfiguration configures the demo apiserver. It is loaded from
+// the file given by --config; command line flags take precedence over it.
+type DemoServerConfiguration struct {
+	metav1.TypeMeta
+
+	// KubeConfig is the path to the kubeconfig of the backing Kubernetes cluster
+	KubeConfig string
+
+	Serving   ServingConfiguration
+	Storage   StorageConfiguration
+	Auth      AuthConfiguration
+	Admission AdmissionConfiguration
+	Features  FeaturesConfiguration
+
+	Controllers ControllersConfiguration
+	APIService  APIServiceConfiguration
+	Foos        FoosConfiguration
+
+	// RuntimeConfig enables or disables API versions and resources, as --runtime-config does
+	RuntimeConfig map[string]string
+}
+
+type ServingConfiguration struct {
+	BindAddress       string
+	BindPort          int32
+	CertDirectory     string
+	TLSCertFile       string
+	TLSPrivateKeyFile string
+}
+
+type StorageConfiguration struct {
+	// Enabled turns on etcd storage
+	Enabled     bool
+	EtcdServers []string
+	Prefix      string
+	MediaType   string
+	// EmbeddedEtcd runs etcd inside the server instead of using EtcdServers
+	EmbeddedEtcd EmbeddedEtcdConfiguration
+	// Encryption encrypts demo resources before they are written to etcd
+	Encryption EncryptionConfiguration
+	// WatchCache serves lists and watches from memory
+	WatchCache bool
+	// WatchCacheSizes maps group resources to their watch cache size. Only zero,
+	// which turns the cache off for the resource, has an effect.
+	WatchCacheSizes map[string]int32
+}
+
+type EncryptionConfiguration struct {
+	// ProviderConfigFile turns encryption on when set
+	ProviderConfigFile string
+	AutomaticReload    bool
+}
+
+type EmbeddedEtcdConfiguration struct {
+	// DataDir turns embedded etcd on when set
+	DataDir        string
+	ClientURL      string
+	SnapshotDir    string
+	DefragInterval metav1.Duration
+}
+
+type AuthConfiguration struct {
+	// Enabled turns on delegated authentication and authorization
+	Enabled                  bool
+	AuthenticationKubeconfig string
+	AuthorizationKubeconfig  string
+}
+
+type AdmissionConfiguration struct {
+	// Enabled turns on the admission chain
+	Enabled        bool
+	EnablePlugins  []string
+	DisablePlugins []string
+}
+
+type FeaturesConfiguration struct {
+	EnableProfiling           bool
+	EnableContentionProfiling bool
+	// FeatureGates maps feature names to whether they are enabled
+	FeatureGates map[string]bool
+}
+
+type APIServiceConfiguration struct {
+	// Register creates or updates the demo APIServices in the backing cluster
+	Register bool
+	// CAFile holds the caBundle of the APIServices; defaults to the serving certificate
+	CAFile           string
+	ServiceNamespace string
+	ServiceName      string
+	ServicePort      int32
+	// Replicas is the number of server replicas behind the Service; more than
+	// one need a CAFile they all share
+	Replicas             int32
+	UnregisterOnShutdown bool
+}
+
+type FoosConfiguration struct {
+	// RevisionHistoryLimit is the number of revisions kept per foo; zero turns the history off
+	RevisionHistoryLimit int32
+}
+
+type ControllersConfiguration struct {
+	// Enabled runs the demo controllers inside the server
+	Enabled bool
+	// ConcurrentFooSyncs is the number of Foos synced in parallel
+	ConcurrentFooSyncs int32
+	// LeaderElection picks the replica that runs the controllers
+	LeaderElection componentbaseconfig.LeaderElectionConfiguration
+}
@@ -0,0 +1,93 @@
+package v1alpha1
+
+import (
+	componentbaseconfigv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	DefaultBindPort         = 6443
+	DefaultEtcdPathPrefix   = "/registry/demo"
+	DefaultStorageMediaType = "application/json"
+	DefaultEmbeddedEtcdURL  = "http://127.0.0.1:2379"
+
+	DefaultConcurrentFooSyncs         = 2
+	DefaultLeaderElectionResourceName = "demo-apiserver-controllers"
+	DefaultLeaderElectionNamespace    = "kube-system"
+
+	DefaultServiceNamespace = "demo"
+	DefaultServiceName      = "apiserver"
+	DefaultServicePort      = 443
+
+	DefaultFooRevisionHistoryLimit = 10
+)
+
+func SetDefaults_DemoServerConfiguration(obj *DemoServerConfiguration) {
+	if obj.Serving.BindPort == nil {
+		obj.Serving.BindPort = ptr.To[int32](DefaultBindPort)
+	}
+	if obj.Storage.Enabled == nil {
+		obj.Storage.Enabled = ptr.To(false)
+	}
+	if len(obj.Storage.Prefix) == 0 {
+		obj.Storage.Prefix = DefaultEtcdPathPrefix
+	}
+	if len(obj.Storage.MediaType) == 0 {
+		obj.Storage.MediaType = DefaultStorageMediaType
+	}
+	if len(obj.Storage.EmbeddedEtcd.ClientURL) == 0 {
+		obj.Storage.EmbeddedEtcd.ClientURL = DefaultEmbeddedEtcdURL
+	}
+	if obj.Storage.WatchCache == nil {
+		obj.Storage.WatchCache = ptr.To(true)
+	}
+	if obj.Foos.RevisionHistoryLimit == nil {
+		obj.Foos.RevisionHistoryLimit = ptr.To[int32](DefaultFooRevisionHistoryLimit)
+	}
+	if obj.Auth.Enabled == nil {
+		obj.Auth.Enabled = ptr.To(false)
+	}
+	if obj.Admission.Enabled == nil {
+		obj.Admission.Enabled = ptr.To(false)
+	}
+	if obj.Features.EnableProfiling == nil {
+		obj.Features.EnableProfiling = ptr.To(true)
+	}
+	if obj.Features.EnableContentionProfiling == nil {
+		obj.Features.EnableContentionProfiling = ptr.To(false)
+	}
+	if obj.Controllers.Enabled == nil {
+		obj.Controllers.Enabled = ptr.To(false)
+	}
+	if obj.Controllers.ConcurrentFooSyncs == nil {
+		obj.Controllers.ConcurrentFooSyncs = ptr.To[int32](DefaultConcurrentFooSyncs)
+	}
+	if len(obj.Controllers.LeaderElection.ResourceLock) == 0 {
+		obj.Controllers.LeaderElection.ResourceLock = "leases"
+	}
+	if len(obj.Controllers.LeaderElection.ResourceName) == 0 {
+		obj.Controllers.LeaderElection.ResourceName = DefaultLeaderElectionResourceName
+	}
+	if len(obj.Controllers.LeaderElection.ResourceNamespace) == 0 {
+		obj.Controllers.LeaderElection.ResourceNamespace = DefaultLeaderElectionNamespace
+	}
+	componentbaseconfigv1alpha1.RecommendedDefaultLeaderElectionConfiguration(&obj.Controllers.LeaderElection)
+	if obj.APIService.Register == nil {
+		obj.APIService.Register = ptr.To(false)
+	}
+	if len(obj.APIService.ServiceNamespace) == 0 {
+		obj.APIService.ServiceNamespace = DefaultServiceNamespace
+	}
+	if len(obj.APIService.ServiceName) == 0 {
+		obj.APIService.ServiceName = DefaultServiceName
+	}
+	if obj.APIService.ServicePort == nil {
+		obj.APIService.ServicePort = ptr.To[int32](DefaultServicePort)
+	}
+	if obj.APIService.Replicas == nil {
+		obj.APIService.Replicas = ptr.To[int32](1)
+	}
+	if obj.APIService.UnregisterOnShutdown == nil {
+		obj.APIService.UnregisterOnShutdown = ptr.To(false)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	componentbaseconfigv1alpha1 "k8s.io/component-base/config/v1alpha1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DemoServerConfiguration configures the demo apiserver. It is loaded from
+// the file given by --config; command line flags take precedence over it.
+type DemoServerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// kubeConfig is the path to the kubeconfig of the backing Kubernetes cluster.
+	// Defaults to the in-cluster config.
+	KubeConfig string `json:"kubeConfig,omitempty"`
+
+	// serving configures the secure port
+	Serving ServingConfiguration `json:"serving"`
+	// storage configures the etcd storage backend
+	Storage StorageConfiguration `json:"storage"`
+	// auth configures delegated authentication and authorization
+	Auth AuthConfiguration `json:"auth"`
+	// admission configures the admission chain
+	Admission AdmissionConfiguration `json:"admission"`
+	// features configures profiling and feature gates
+	Features FeaturesConfiguration `json:"features"`
+	// controllers configures the demo controllers embedded in the server
+	Controllers ControllersConfiguration `json:"controllers"`
+	// apiService registers the server with the aggregator of the backing cluster
+	APIService APIServiceConfiguration `json:"apiService"`
+	// foos configures how the server handles foos
+	Foos FoosConfiguration `json:"foos"`
+
+	// runtimeConfig enables or disables API versions and resources of the demo
+	// group, e.g. {"demo.k8s.io/v1alpha1": "false"}. Same syntax as --runtime-config.
+	RuntimeConfig map[string]string `json:"runtimeConfig,omitempty"`
+}
+
+type ServingConfiguration struct {
+	// bindAddress is the IP address to serve on. Defaults to all interfaces.
+	BindAddress string `json:"bindAddress,omitempty"`
+	// bindPort is the port to serve HTTPS on. Defaults to 6443.
+	BindPort *int32 `json:"bindPort,omitempty"`
+	// certDirectory holds the self-signed certificate when no TLS files are given.
+	CertDirectory string `json:"certDirectory,omitempty"`
+	// tlsCertFile is the serving certificate. It is reloaded when it changes on disk.
+	TLSCertFile string `json:"tlsCertFile,omitempty"`
+	// tlsPrivateKeyFile is the key matching tlsCertFile.
+	TLSPrivateKeyFile string `json:"tlsPrivateKeyFile,omitempty"`
+}
+
+type StorageConfiguration struct {
+	// enabled turns on etcd storage. Defaults to false.
+	Enabled *bool `json:"enabled,omitempty"`
+	// etcdServers lists the etcd endpoints, e.g. http://localhost:2379
+	EtcdServers []string `json:"etcdServers,omitempty"`
+	// prefix is the key prefix of all demo resources. Defaults to /registry/demo.
+	Prefix string `json:"prefix,omitempty"`
+	// mediaType is the media type objects are stored with, one of application/json,
+	// application/yaml or application/vnd.kubernetes.protobuf. Defaults to
+	// application/json.
+	MediaType string `json:"mediaType,omitempty"`
+	// embeddedEtcd runs a single member etcd inside the server. It is used
+	// instead of etcdServers when its dataDir is set.
+	EmbeddedEtcd EmbeddedEtcdConfiguration `json:"embeddedEtcd"`
+	// encryption encrypts demo resources before they are written to etcd.
+	Encryption EncryptionConfiguration `json:"encryption"`
+	// watchCache serves lists and watches of demo resources from memory. Defaults to true.
+	WatchCache *bool `json:"watchCache,omitempty"`
+	// watchCacheSizes maps resources, e.g. foos.demo.k8s.io, to their watch
+	// cache size. Watch caches are sized automatically, so only zero, which
+	// turns the cache off for the resource, has an effect.
+	WatchCacheSizes map[string]int32 `json:"watchCacheSizes,omitempty"`
+}
+
+type EncryptionConfiguration struct {
+	// providerConfigFile is an apiserver.config.k8s.io/v1 EncryptionConfiguration
+	// listing the providers of foos.demo.k8s.io and configs.demo.k8s.io.
+	// Encryption is off when empty.
+	ProviderConfigFile string `json:"providerConfigFile,omitempty"`
+	// automaticReload picks up changes of providerConfigFile without a restart,
+	// e.g. to rotate keys.
+	AutomaticReload bool `json:"automaticReload,omitempty"`
+}
+
+type EmbeddedEtcdConfiguration struct {
+	// dataDir is where the embedded etcd keeps its data. Embedded etcd is off when empty.
+	DataDir string `json:"dataDir,omitempty"`
+	// clientURL is the URL the embedded etcd serves clients on. Defaults to http://127.0.0.1:2379.
+	ClientURL string `json:"clientURL,omitempty"`
+	// snapshotDir, if set, receives an etcd snapshot when the server shuts down.
+	SnapshotDir string `json:"snapshotDir,omitempty"`
+	// defragInterval is how often the embedded etcd is defragmented. Zero turns it off.
+	DefragInterval metav1.Duration `json:"defragInterval,omitempty"`
+}
+
+type AuthConfiguration struct {
+	// enabled turns on delegated authentication and authorization. Defaults to false.
+	Enabled *bool `json:"enabled,omitempty"`
+	// authenticationKubeconfig points to the cluster that reviews tokens.
+	// Defaults to the in-cluster config.
+	AuthenticationKubeconfig string `json:"authenticationKubeconfig,omitempty"`
+	// authorizationKubeconfig points to the cluster that reviews subject access.
+	// Defaults to the in-cluster config.
+	AuthorizationKubeconfig string `json:"authorizationKubeconfig,omitempty"`
+}
+
+type AdmissionConfiguration struct {
+	// enabled turns on the admission chain. Defaults to false.
+	Enabled *bool `json:"enabled,omitempty"`
+	// enablePlugins are admission plugins to enable in addition to the default ones.
+	EnablePlugins []string `json:"enablePlugins,omitempty"`
+	// disablePlugins are admission plugins to disable, even if they are on by default.
+	DisablePlugins []string `json:"disablePlugins,omitempty"`
+}
+
+type FeaturesConfiguration struct {
+	// enableProfiling serves /debug/pprof. Defaults to true.
+	EnableProfiling *bool `json:"enableProfiling,omitempty"`
+	// enableContentionProfiling turns on block profiling when profiling is enabled.
+	// Defaults to false.
+	EnableContentionProfiling *bool `json:"enableContentionProfiling,omitempty"`
+	// featureGates maps feature names to whether they are enabled, like --feature-gates.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+type APIServiceConfiguration struct {
+	// register creates or updates an APIService for every served demo version,
+	// with the caBundle kept in sync with caFile. Defaults to false.
+	Register *bool `json:"register,omitempty"`
+	// caFile is the CA bundle that signs the serving certificate. It is
+	// reloaded when it changes. Defaults to the serving certificate itself,
+	// which works for the self-signed one.
+	CAFile string `json:"caFile,omitempty"`
+	// serviceNamespace is the namespace of the Service in front of the server. Defaults to "demo".
+	ServiceNamespace string `json:"serviceNamespace,omitempty"`
+	// serviceName is the name of the Service in front of the server. Defaults to "apiserver".
+	ServiceName string `json:"serviceName,omitempty"`
+	// servicePort is the port of the Service in front of the server. Defaults to 443.
+	ServicePort *int32 `json:"servicePort,omitempty"`
+	// replicas is the number of server replicas behind the Service. Each
+	// replica writes the caBundle, so with more than one, caFile must be set
+	// to a CA that signs the serving certificates of all of them. Defaults to 1.
+	Replicas *int32 `json:"replicas,omitempty"`
+	// unregisterOnShutdown deletes the APIServices when the server shuts down
+	// cleanly. Only use it with a single replica. Defaults to false.
+	UnregisterOnShutdown *bool `json:"unregisterOnShutdown,omitempty"`
+}
+
+type FoosConfiguration struct {
+	// revisionHistoryLimit is the number of revisions kept per foo for the
+	// foos/history and foos/rollback subresources. Zero turns the history off.
+	// Defaults to 10.
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+}
+
+type ControllersConfiguration struct {
+	// enabled runs the demo controllers inside the server instead of a
+	// separate manager. Defaults to false.
+	Enabled *bool `json:"enabled,omitempty"`
+	// concurrentFooSyncs is the number of Foos synced in parallel. Defaults to 2.
+	ConcurrentFooSyncs *int32 `json:"concurrentFooSyncs,omitempty"`
+	// leaderElection picks the one server replica that runs the controllers.
+	// The lock lives in the backing cluster.
+	LeaderElection componentbaseconfigv1alpha1.LeaderElectionConfiguration `json:"leaderElection"`
+}
@@ -0,0 +1,547 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by conversion-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	unsafe "unsafe"
+
+	config "github.com/guodoliu/apiserver/pkg/apis/config"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+)
+
+func init() {
+	localSchemeBuilder.Register(RegisterConversions)
+}
+
+// RegisterConversions adds conversion functions to the given scheme.
+// Public to allow building arbitrary schemes.
+func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*APIServiceConfiguration)(nil), (*config.APIServiceConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_APIServiceConfiguration_To_config_APIServiceConfiguration(a.(*APIServiceConfiguration), b.(*config.APIServiceConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.APIServiceConfiguration)(nil), (*APIServiceConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_APIServiceConfiguration_To_v1alpha1_APIServiceConfiguration(a.(*config.APIServiceConfiguration), b.(*APIServiceConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AdmissionConfiguration)(nil), (*config.AdmissionConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_AdmissionConfiguration_To_config_AdmissionConfiguration(a.(*AdmissionConfiguration), b.(*config.AdmissionConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.AdmissionConfiguration)(nil), (*AdmissionConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_AdmissionConfiguration_To_v1alpha1_AdmissionConfiguration(a.(*config.AdmissionConfiguration), b.(*AdmissionConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AuthConfiguration)(nil), (*config.AuthConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_AuthConfiguration_To_config_AuthConfiguration(a.(*AuthConfiguration), b.(*config.AuthConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.AuthConfiguration)(nil), (*AuthConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_AuthConfiguration_To_v1alpha1_AuthConfiguration(a.(*config.AuthConfiguration), b.(*AuthConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ControllersConfiguration)(nil), (*config.ControllersConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ControllersConfiguration_To_config_ControllersConfiguration(a.(*ControllersConfiguration), b.(*config.ControllersConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ControllersConfiguration)(nil), (*ControllersConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ControllersConfiguration_To_v1alpha1_ControllersConfiguration(a.(*config.ControllersConfiguration), b.(*ControllersConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DemoServerConfiguration)(nil), (*config.DemoServerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DemoServerConfiguration_To_config_DemoServerConfiguration(a.(*DemoServerConfiguration), b.(*config.DemoServerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DemoServerConfiguration)(nil), (*DemoServerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DemoServerConfiguration_To_v1alpha1_DemoServerConfiguration(a.(*config.DemoServerConfiguration), b.(*DemoServerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*EmbeddedEtcdConfiguration)(nil), (*config.EmbeddedEtcdConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_EmbeddedEtcdConfiguration_To_config_EmbeddedEtcdConfiguration(a.(*EmbeddedEtcdConfiguration), b.(*config.EmbeddedEtcdConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.EmbeddedEtcdConfiguration)(nil), (*EmbeddedEtcdConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_EmbeddedEtcdConfiguration_To_v1alpha1_EmbeddedEtcdConfiguration(a.(*config.EmbeddedEtcdConfiguration), b.(*EmbeddedEtcdConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*EncryptionConfiguration)(nil), (*config.EncryptionConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_EncryptionConfiguration_To_config_EncryptionConfiguration(a.(*EncryptionConfiguration), b.(*config.EncryptionConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.EncryptionConfiguration)(nil), (*EncryptionConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_EncryptionConfiguration_To_v1alpha1_EncryptionConfiguration(a.(*config.EncryptionConfiguration), b.(*EncryptionConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FeaturesConfiguration)(nil), (*config.FeaturesConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FeaturesConfiguration_To_config_FeaturesConfiguration(a.(*FeaturesConfiguration), b.(*config.FeaturesConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.FeaturesConfiguration)(nil), (*FeaturesConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_FeaturesConfiguration_To_v1alpha1_FeaturesConfiguration(a.(*config.FeaturesConfiguration), b.(*FeaturesConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FoosConfiguration)(nil), (*config.FoosConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FoosConfiguration_To_config_FoosConfiguration(a.(*FoosConfiguration), b.(*config.FoosConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.FoosConfiguration)(nil), (*FoosConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_FoosConfiguration_To_v1alpha1_FoosConfiguration(a.(*config.FoosConfiguration), b.(*FoosConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ServingConfiguration)(nil), (*config.ServingConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ServingConfiguration_To_config_ServingConfiguration(a.(*ServingConfiguration), b.(*config.ServingConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ServingConfiguration)(nil), (*ServingConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ServingConfiguration_To_v1alpha1_ServingConfiguration(a.(*config.ServingConfiguration), b.(*ServingConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*StorageConfiguration)(nil), (*config.StorageConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_StorageConfiguration_To_config_StorageConfiguration(a.(*StorageConfiguration), b.(*config.StorageConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.StorageConfiguration)(nil), (*StorageConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_StorageConfiguration_To_v1alpha1_StorageConfiguration(a.(*config.StorageConfiguration), b.(*StorageConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1alpha1_APIServiceConfiguration_To_config_APIServiceConfiguration(in *APIServiceConfiguration, out *config.APIServiceConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_bool_To_bool(&in.Register, &out.Register, s); err != nil {
+		return err
+	}
+	out.CAFile = in.CAFile
+	out.ServiceNamespace = in.ServiceNamespace
+	out.ServiceName = in.ServiceName
+	if err := v1.Convert_Pointer_int32_To_int32(&in.ServicePort, &out.ServicePort, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.Replicas, &out.Replicas, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.UnregisterOnShutdown, &out.UnregisterOnShutdown, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_APIServiceConfiguration_To_config_APIServiceConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_APIServiceConfiguration_To_config_APIServiceConfiguration(in *APIServiceConfiguration, out *config.APIServiceConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_APIServiceConfiguration_To_config_APIServiceConfiguration(in, out, s)
+}
+
+func autoConvert_config_APIServiceConfiguration_To_v1alpha1_APIServiceConfiguration(in *config.APIServiceConfiguration, out *APIServiceConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_bool_To_Pointer_bool(&in.Register, &out.Register, s); err != nil {
+		return err
+	}
+	out.CAFile = in.CAFile
+	out.ServiceNamespace = in.ServiceNamespace
+	out.ServiceName = in.ServiceName
+	if err := v1.Convert_int32_To_Pointer_int32(&in.ServicePort, &out.ServicePort, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.Replicas, &out.Replicas, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.UnregisterOnShutdown, &out.UnregisterOnShutdown, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_APIServiceConfiguration_To_v1alpha1_APIServiceConfiguration is an autogenerated conversion function.
+func Convert_config_APIServiceConfiguration_To_v1alpha1_APIServiceConfiguration(in *config.APIServiceConfiguration, out *APIServiceConfiguration, s conversion.Scope) error {
+	return autoConvert_config_APIServiceConfiguration_To_v1alpha1_APIServiceConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_AdmissionConfiguration_To_config_AdmissionConfiguration(in *AdmissionConfiguration, out *config.AdmissionConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_bool_To_bool(&in.Enabled, &out.Enabled, s); err != nil {
+		return err
+	}
+	out.EnablePlugins = *(*[]string)(unsafe.Pointer(&in.EnablePlugins))
+	out.DisablePlugins = *(*[]string)(unsafe.Pointer(&in.DisablePlugins))
+	return nil
+}
+
+// Convert_v1alpha1_AdmissionConfiguration_To_config_AdmissionConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_AdmissionConfiguration_To_config_AdmissionConfiguration(in *AdmissionConfiguration, out *config.AdmissionConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_AdmissionConfiguration_To_config_AdmissionConfiguration(in, out, s)
+}
+
+func autoConvert_config_AdmissionConfiguration_To_v1alpha1_AdmissionConfiguration(in *config.AdmissionConfiguration, out *AdmissionConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_bool_To_Pointer_bool(&in.Enabled, &out.Enabled, s); err != nil {
+		return err
+	}
+	out.EnablePlugins = *(*[]string)(unsafe.Pointer(&in.EnablePlugins))
+	out.DisablePlugins = *(*[]string)(unsafe.Pointer(&in.DisablePlugins))
+	return nil
+}
+
+// Convert_config_AdmissionConfiguration_To_v1alpha1_AdmissionConfiguration is an autogenerated conversion function.
+func Convert_config_AdmissionConfiguration_To_v1alpha1_AdmissionConfiguration(in *config.AdmissionConfiguration, out *AdmissionConfiguration, s conversion.Scope) error {
+	return autoConvert_config_AdmissionConfiguration_To_v1alpha1_AdmissionConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_AuthConfiguration_To_config_AuthConfiguration(in *AuthConfiguration, out *config.AuthConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_bool_To_bool(&in.Enabled, &out.Enabled, s); err != nil {
+		return err
+	}
+	out.AuthenticationKubeconfig = in.AuthenticationKubeconfig
+	out.AuthorizationKubeconfig = in.AuthorizationKubeconfig
+	return nil
+}
+
+// Convert_v1alpha1_AuthConfiguration_To_config_AuthConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_AuthConfiguration_To_config_AuthConfiguration(in *AuthConfiguration, out *config.AuthConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_AuthConfiguration_To_config_AuthConfiguration(in, out, s)
+}
+
+func autoConvert_config_AuthConfiguration_To_v1alpha1_AuthConfiguration(in *config.AuthConfiguration, out *AuthConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_bool_To_Pointer_bool(&in.Enabled, &out.Enabled, s); err != nil {
+		return err
+	}
+	out.AuthenticationKubeconfig = in.AuthenticationKubeconfig
+	out.AuthorizationKubeconfig = in.AuthorizationKubeconfig
+	return nil
+}
+
+// Convert_config_AuthConfiguration_To_v1alpha1_AuthConfiguration is an autogenerated conversion function.
+func Convert_config_AuthConfiguration_To_v1alpha1_AuthConfiguration(in *config.AuthConfiguration, out *AuthConfiguration, s conversion.Scope) error {
+	return autoConvert_config_AuthConfiguration_To_v1alpha1_AuthConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_ControllersConfiguration_To_config_ControllersConfiguration(in *ControllersConfiguration, out *config.ControllersConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_bool_To_bool(&in.Enabled, &out.Enabled, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.ConcurrentFooSyncs, &out.ConcurrentFooSyncs, s); err != nil {
+		return err
+	}
+	if err := configv1alpha1.Convert_v1alpha1_LeaderElectionConfiguration_To_config_LeaderElectionConfiguration(&in.LeaderElection, &out.LeaderElection, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_ControllersConfiguration_To_config_ControllersConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_ControllersConfiguration_To_config_ControllersConfiguration(in *ControllersConfiguration, out *config.ControllersConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ControllersConfiguration_To_config_ControllersConfiguration(in, out, s)
+}
+
+func autoConvert_config_ControllersConfiguration_To_v1alpha1_ControllersConfiguration(in *config.ControllersConfiguration, out *ControllersConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_bool_To_Pointer_bool(&in.Enabled, &out.Enabled, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.ConcurrentFooSyncs, &out.ConcurrentFooSyncs, s); err != nil {
+		return err
+	}
+	if err := configv1alpha1.Convert_config_LeaderElectionConfiguration_To_v1alpha1_LeaderElectionConfiguration(&in.LeaderElection, &out.LeaderElection, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_ControllersConfiguration_To_v1alpha1_ControllersConfiguration is an autogenerated conversion function.
+func Convert_config_ControllersConfiguration_To_v1alpha1_ControllersConfiguration(in *config.ControllersConfiguration, out *ControllersConfiguration, s conversion.Scope) error {
+	return autoConvert_config_ControllersConfiguration_To_v1alpha1_ControllersConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_DemoServerConfiguration_To_config_DemoServerConfiguration(in *DemoServerConfiguration, out *config.DemoServerConfiguration, s conversion.Scope) error {
+	out.KubeConfig = in.KubeConfig
+	if err := Convert_v1alpha1_ServingConfiguration_To_config_ServingConfiguration(&in.Serving, &out.Serving, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_StorageConfiguration_To_config_StorageConfiguration(&in.Storage, &out.Storage, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_AuthConfiguration_To_config_AuthConfiguration(&in.Auth, &out.Auth, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_AdmissionConfiguration_To_config_AdmissionConfiguration(&in.Admission, &out.Admission, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_FeaturesConfiguration_To_config_FeaturesConfiguration(&in.Features, &out.Features, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_ControllersConfiguration_To_config_ControllersConfiguration(&in.Controllers, &out.Controllers, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_APIServiceConfiguration_To_config_APIServiceConfiguration(&in.APIService, &out.APIService, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_FoosConfiguration_To_config_FoosConfiguration(&in.Foos, &out.Foos, s); err != nil {
+		return err
+	}
+	out.RuntimeConfig = *(*map[string]string)(unsafe.Pointer(&in.RuntimeConfig))
+	return nil
+}
+
+// Convert_v1alpha1_DemoServerConfiguration_To_config_DemoServerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_DemoServerConfiguration_To_config_DemoServerConfiguration(in *DemoServerConfiguration, out *config.DemoServerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DemoServerConfiguration_To_config_DemoServerConfiguration(in, out, s)
+}
+
+func autoConvert_config_DemoServerConfiguration_To_v1alpha1_DemoServerConfiguration(in *config.DemoServerConfiguration, out *DemoServerConfiguration, s conversion.Scope) error {
+	out.KubeConfig = in.KubeConfig
+	if err := Convert_config_ServingConfiguration_To_v1alpha1_ServingConfiguration(&in.Serving, &out.Serving, s); err != nil {
+		return err
+	}
+	if err := Convert_config_StorageConfiguration_To_v1alpha1_StorageConfiguration(&in.Storage, &out.Storage, s); err != nil {
+		return err
+	}
+	if err := Convert_config_AuthConfiguration_To_v1alpha1_AuthConfiguration(&in.Auth, &out.Auth, s); err != nil {
+		return err
+	}
+	if err := Convert_config_AdmissionConfiguration_To_v1alpha1_AdmissionConfiguration(&in.Admission, &out.Admission, s); err != nil {
+		return err
+	}
+	if err := Convert_config_FeaturesConfiguration_To_v1alpha1_FeaturesConfiguration(&in.Features, &out.Features, s); err != nil {
+		return err
+	}
+	if err := Convert_config_ControllersConfiguration_To_v1alpha1_ControllersConfiguration(&in.Controllers, &out.Controllers, s); err != nil {
+		return err
+	}
+	if err := Convert_config_APIServiceConfiguration_To_v1alpha1_APIServiceConfiguration(&in.APIService, &out.APIService, s); err != nil {
+		return err
+	}
+	if err := Convert_config_FoosConfiguration_To_v1alpha1_FoosConfiguration(&in.Foos, &out.Foos, s); err != nil {
+		return err
+	}
+	out.RuntimeConfig = *(*map[string]string)(unsafe.Pointer(&in.RuntimeConfig))
+	return nil
+}
+
+// Convert_config_DemoServerConfiguration_To_v1alpha1_DemoServerConfiguration is an autogenerated conversion function.
+func Convert_config_DemoServerConfiguration_To_v1alpha1_DemoServerConfiguration(in *config.DemoServerConfiguration, out *DemoServerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_DemoServerConfiguration_To_v1alpha1_DemoServerConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_EmbeddedEtcdConfiguration_To_config_EmbeddedEtcdConfiguration(in *EmbeddedEtcdConfiguration, out *config.EmbeddedEtcdConfiguration, s conversion.Scope) error {
+	out.DataDir = in.DataDir
+	out.ClientURL = in.ClientURL
+	out.SnapshotDir = in.SnapshotDir
+	out.DefragInterval = in.DefragInterval
+	return nil
+}
+
+// Convert_v1alpha1_EmbeddedEtcdConfiguration_To_config_EmbeddedEtcdConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_EmbeddedEtcdConfiguration_To_config_EmbeddedEtcdConfiguration(in *EmbeddedEtcdConfiguration, out *config.EmbeddedEtcdConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_EmbeddedEtcdConfiguration_To_config_EmbeddedEtcdConfiguration(in, out, s)
+}
+
+func autoConvert_config_EmbeddedEtcdConfiguration_To_v1alpha1_EmbeddedEtcdConfiguration(in *config.EmbeddedEtcdConfiguration, out *EmbeddedEtcdConfiguration, s conversion.Scope) error {
+	out.DataDir = in.DataDir
+	out.ClientURL = in.ClientURL
+	out.SnapshotDir = in.SnapshotDir
+	out.DefragInterval = in.DefragInterval
+	return nil
+}
+
+// Convert_config_EmbeddedEtcdConfiguration_To_v1alpha1_EmbeddedEtcdConfiguration is an autogenerated conversion function.
+func Convert_config_EmbeddedEtcdConfiguration_To_v1alpha1_EmbeddedEtcdConfiguration(in *config.EmbeddedEtcdConfiguration, out *EmbeddedEtcdConfiguration, s conversion.Scope) error {
+	return autoConvert_config_EmbeddedEtcdConfiguration_To_v1alpha1_EmbeddedEtcdConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_EncryptionConfiguration_To_config_EncryptionConfiguration(in *EncryptionConfiguration, out *config.EncryptionConfiguration, s conversion.Scope) error {
+	out.ProviderConfigFile = in.ProviderConfigFile
+	out.AutomaticReload = in.AutomaticReload
+	return nil
+}
+
+// Convert_v1alpha1_EncryptionConfiguration_To_config_EncryptionConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_EncryptionConfiguration_To_config_EncryptionConfiguration(in *EncryptionConfiguration, out *config.EncryptionConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_EncryptionConfiguration_To_config_EncryptionConfiguration(in, out, s)
+}
+
+func autoConvert_config_EncryptionConfiguration_To_v1alpha1_EncryptionConfiguration(in *config.EncryptionConfiguration, out *EncryptionConfiguration, s conversion.Scope) error {
+	out.ProviderConfigFile = in.ProviderConfigFile
+	out.AutomaticReload = in.AutomaticReload
+	return nil
+}
+
+// Convert_config_EncryptionConfiguration_To_v1alpha1_EncryptionConfiguration is an autogenerated conversion function.
+func Convert_config_EncryptionConfiguration_To_v1alpha1_EncryptionConfiguration(in *config.EncryptionConfiguration, out *EncryptionConfiguration, s conversion.Scope) error {
+	return autoConvert_config_EncryptionConfiguration_To_v1alpha1_EncryptionConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_FeaturesConfiguration_To_config_FeaturesConfiguration(in *FeaturesConfiguration, out *config.FeaturesConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_bool_To_bool(&in.EnableProfiling, &out.EnableProfiling, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.EnableContentionProfiling, &out.EnableContentionProfiling, s); err != nil {
+		return err
+	}
+	out.FeatureGates = *(*map[string]bool)(unsafe.Pointer(&in.FeatureGates))
+	return nil
+}
+
+// Convert_v1alpha1_FeaturesConfiguration_To_config_FeaturesConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_FeaturesConfiguration_To_config_FeaturesConfiguration(in *FeaturesConfiguration, out *config.FeaturesConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FeaturesConfiguration_To_config_FeaturesConfiguration(in, out, s)
+}
+
+func autoConvert_config_FeaturesConfiguration_To_v1alpha1_FeaturesConfiguration(in *config.FeaturesConfiguration, out *FeaturesConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_bool_To_Pointer_bool(&in.EnableProfiling, &out.EnableProfiling, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.EnableContentionProfiling, &out.EnableContentionProfiling, s); err != nil {
+		return err
+	}
+	out.FeatureGates = *(*map[string]bool)(unsafe.Pointer(&in.FeatureGates))
+	return nil
+}
+
+// Convert_config_FeaturesConfiguration_To_v1alpha1_FeaturesConfiguration is an autogenerated conversion function.
+func Convert_config_FeaturesConfiguration_To_v1alpha1_FeaturesConfiguration(in *config.FeaturesConfiguration, out *FeaturesConfiguration, s conversion.Scope) error {
+	return autoConvert_config_FeaturesConfiguration_To_v1alpha1_FeaturesConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_FoosConfiguration_To_config_FoosConfiguration(in *FoosConfiguration, out *config.FoosConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_int32_To_int32(&in.RevisionHistoryLimit, &out.RevisionHistoryLimit, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_FoosConfiguration_To_config_FoosConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_FoosConfiguration_To_config_FoosConfiguration(in *FoosConfiguration, out *config.FoosConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FoosConfiguration_To_config_FoosConfiguration(in, out, s)
+}
+
+func autoConvert_config_FoosConfiguration_To_v1alpha1_FoosConfiguration(in *config.FoosConfiguration, out *FoosConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_int32_To_Pointer_int32(&in.RevisionHistoryLimit, &out.RevisionHistoryLimit, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_FoosConfiguration_To_v1alpha1_FoosConfiguration is an autogenerated conversion function.
+func Convert_config_FoosConfiguration_To_v1alpha1_FoosConfiguration(in *config.FoosConfiguration, out *FoosConfiguration, s conversion.Scope) error {
+	return autoConvert_config_FoosConfiguration_To_v1alpha1_FoosConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_ServingConfiguration_To_config_ServingConfiguration(in *ServingConfiguration, out *config.ServingConfiguration, s conversion.Scope) error {
+	out.BindAddress = in.BindAddress
+	if err := v1.Convert_Pointer_int32_To_int32(&in.BindPort, &out.BindPort, s); err != nil {
+		return err
+	}
+	out.CertDirectory = in.CertDirectory
+	out.TLSCertFile = in.TLSCertFile
+	out.TLSPrivateKeyFile = in.TLSPrivateKeyFile
+	return nil
+}
+
+// Convert_v1alpha1_ServingConfiguration_To_config_ServingConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_ServingConfiguration_To_config_ServingConfiguration(in *ServingConfiguration, out *config.ServingConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ServingConfiguration_To_config_ServingConfiguration(in, out, s)
+}
+
+func autoConvert_config_ServingConfiguration_To_v1alpha1_ServingConfiguration(in *config.ServingConfiguration, out *ServingConfiguration, s conversion.Scope) error {
+	out.BindAddress = in.BindAddress
+	if err := v1.Convert_int32_To_Pointer_int32(&in.BindPort, &out.BindPort, s); err != nil {
+		return err
+	}
+	out.CertDirectory = in.CertDirectory
+	out.TLSCertFile = in.TLSCertFile
+	out.TLSPrivateKeyFile = in.TLSPrivateKeyFile
+	return nil
+}
+
+// Convert_config_ServingConfiguration_To_v1alpha1_ServingConfiguration is an autogenerated conversion function.
+func Convert_config_ServingConfiguration_To_v1alpha1_ServingConfiguration(in *config.ServingConfiguration, out *ServingConfiguration, s conversion.Scope) error {
+	return autoConvert_config_ServingConfiguration_To_v1alpha1_ServingConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_StorageConfiguration_To_config_StorageConfiguration(in *StorageConfiguration, out *config.StorageConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_bool_To_bool(&in.Enabled, &out.Enabled, s); err != nil {
+		return err
+	}
+	out.EtcdServers = *(*[]string)(unsafe.Pointer(&in.EtcdServers))
+	out.Prefix = in.Prefix
+	out.MediaType = in.MediaType
+	if err := Convert_v1alpha1_EmbeddedEtcdConfiguration_To_config_EmbeddedEtcdConfiguration(&in.EmbeddedEtcd, &out.EmbeddedEtcd, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_EncryptionConfiguration_To_config_EncryptionConfiguration(&in.Encryption, &out.Encryption, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.WatchCache, &out.WatchCache, s); err != nil {
+		return err
+	}
+	out.WatchCacheSizes = *(*map[string]int32)(unsafe.Pointer(&in.WatchCacheSizes))
+	return nil
+}
+
+// Convert_v1alpha1_StorageConfiguration_To_config_StorageConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_StorageConfiguration_To_config_StorageConfiguration(in *StorageConfiguration, out *config.StorageConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_StorageConfiguration_To_config_StorageConfiguration(in, out, s)
+}
+
+func autoConvert_config_StorageConfiguration_To_v1alpha1_StorageConfiguration(in *config.StorageConfiguration, out *StorageConfiguration, s conversion.Scope) error {
+	if err := v1.Convert_bool_To_Pointer_bool(&in.Enabled, &out.Enabled, s); err != nil {
+		return err
+	}
+	out.EtcdServers = *(*[]string)(unsafe.Pointer(&in.EtcdServers))
+	out.Prefix = in.Prefix
+	out.MediaType = in.MediaType
+	if err := Convert_config_EmbeddedEtcdConfiguration_To_v1alpha1_EmbeddedEtcdConfiguration(&in.EmbeddedEtcd, &out.EmbeddedEtcd, s); err != nil {
+		return err
+	}
+	if err := Convert_config_EncryptionConfiguration_To_v1alpha1_EncryptionConfiguration(&in.Encryption, &out.Encryption, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.WatchCache, &out.WatchCache, s); err != nil {
+		return err
+	}
+	out.WatchCacheSizes = *(*map[string]int32)(unsafe.Pointer(&in.WatchCacheSizes))
+	return nil
+}
+
+// Convert_config_StorageConfiguration_To_v1alpha1_StorageConfiguration is an autogenerated conversion function.
+func Convert_config_StorageConfiguration_To_v1alpha1_StorageConfiguration(in *config.StorageConfiguration, out *StorageConfiguration, s conversion.Scope) error {
+	return autoConvert_config_StorageConfiguration_To_v1alpha1_StorageConfiguration(in, out, s)
+}
@@ -0,0 +1,140 @@
+package validation
+
+import (
+	"net"
+	"net/url"
+
+	"github.com/guodoliu/apiserver/pkg/apis/config"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	componentbasevalidation "k8s.io/component-base/config/validation"
+)
+
+var supportedStorageMediaTypes = sets.New(
+	runtime.ContentTypeJSON,
+	runtime.ContentTypeYAML,
+	runtime.ContentTypeProtobuf,
+)
+
+// ValidateDemoServerConfiguration ensures validation of the DemoServerConfiguration struct
+func ValidateDemoServerConfiguration(cfg *config.DemoServerConfiguration) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateServing(&cfg.Serving, field.NewPath("serving"))...)
+	allErrs = append(allErrs, validateStorage(&cfg.Storage, field.NewPath("storage"))...)
+	allErrs = append(allErrs, validateAdmission(&cfg.Admission, field.NewPath("admission"))...)
+	allErrs = append(allErrs, ValidateControllers(&cfg.Controllers, field.NewPath("controllers"))...)
+	allErrs = append(allErrs, ValidateAPIService(&cfg.APIService, field.NewPath("apiService"))...)
+	if cfg.Foos.RevisionHistoryLimit < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("foos", "revisionHistoryLimit"), cfg.Foos.RevisionHistoryLimit, "must not be negative"))
+	}
+	return allErrs
+}
+
+func validateServing(cfg *config.ServingConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(cfg.BindAddress) > 0 && net.ParseIP(cfg.BindAddress) == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("bindAddress"), cfg.BindAddress, "must be a valid IP address"))
+	}
+	for _, msg := range utilvalidation.IsValidPortNum(int(cfg.BindPort)) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("bindPort"), cfg.BindPort, msg))
+	}
+	if (len(cfg.TLSCertFile) == 0) != (len(cfg.TLSPrivateKeyFile) == 0) {
+		allErrs = append(allErrs, field.Required(fldPath.Child("tlsPrivateKeyFile"), "tlsCertFile and tlsPrivateKeyFile must be set together"))
+	}
+	return allErrs
+}
+
+func validateStorage(cfg *config.StorageConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	embedded := len(cfg.EmbeddedEtcd.DataDir) > 0
+	if cfg.Enabled && !embedded && len(cfg.EtcdServers) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("etcdServers"), "required when storage is enabled without embeddedEtcd"))
+	}
+	if embedded {
+		if len(cfg.EtcdServers) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("etcdServers"), "may not be set together with embeddedEtcd.dataDir"))
+		}
+		if u, err := url.Parse(cfg.EmbeddedEtcd.ClientURL); err != nil || u.Scheme != "http" || len(u.Host) == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("embeddedEtcd", "clientURL"), cfg.EmbeddedEtcd.ClientURL, "must be an http URL"))
+		}
+		if cfg.EmbeddedEtcd.DefragInterval.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("embeddedEtcd", "defragInterval"), cfg.EmbeddedEtcd.DefragInterval.Duration.String(), "must not be negative"))
+		}
+	}
+	if len(cfg.Encryption.ProviderConfigFile) > 0 && !cfg.Enabled && !embedded {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("encryption", "providerConfigFile"), "only applies when storage is enabled"))
+	}
+	if cfg.Encryption.AutomaticReload && len(cfg.Encryption.ProviderConfigFile) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("encryption", "providerConfigFile"), "required when automaticReload is set"))
+	}
+	for resource, size := range cfg.WatchCacheSizes {
+		if size < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("watchCacheSizes").Key(resource), size, "must not be negative"))
+		}
+	}
+	if len(cfg.Prefix) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("prefix"), ""))
+	}
+	if !supportedStorageMediaTypes.Has(cfg.MediaType) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("mediaType"), cfg.MediaType, sets.List(supportedStorageMediaTypes)))
+	}
+	return allErrs
+}
+
+func validateAdmission(cfg *config.AdmissionConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	enabled := sets.New(cfg.EnablePlugins...)
+	for i, name := range cfg.DisablePlugins {
+		if enabled.Has(name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("disablePlugins").Index(i), name, "plugin is also listed in enablePlugins"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateControllers checks the embedded controller settings. Leader election
+// is only validated when the controllers are enabled.
+func ValidateControllers(cfg *config.ControllersConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !cfg.Enabled {
+		return allErrs
+	}
+	if cfg.ConcurrentFooSyncs <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("concurrentFooSyncs"), cfg.ConcurrentFooSyncs, "must be greater than 0"))
+	}
+	allErrs = append(allErrs, componentbasevalidation.ValidateLeaderElectionConfiguration(&cfg.LeaderElection, fldPath.Child("leaderElection"))...)
+	return allErrs
+}
+
+// ValidateAPIService checks the APIService registration settings when
+// registration is enabled.
+func ValidateAPIService(cfg *config.APIServiceConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !cfg.Register {
+		return allErrs
+	}
+	for _, msg := range utilvalidation.IsDNS1123Label(cfg.ServiceNamespace) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("serviceNamespace"), cfg.ServiceNamespace, msg))
+	}
+	for _, msg := range utilvalidation.IsDNS1035Label(cfg.ServiceName) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("serviceName"), cfg.ServiceName, msg))
+	}
+	for _, msg := range utilvalidation.IsValidPortNum(int(cfg.ServicePort)) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("servicePort"), cfg.ServicePort, msg))
+	}
+	switch {
+	case cfg.Replicas < 1:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), cfg.Replicas, "must be greater than 0"))
+	case cfg.Replicas > 1:
+		// every replica would put its own serving certificate into the caBundle
+		if len(cfg.CAFile) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("caFile"), "a CA shared by all replicas is required with more than one replica"))
+		}
+		if cfg.UnregisterOnShutdown {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("unregisterOnShutdown"), cfg.UnregisterOnShutdown, "must be false with more than one replica"))
+		}
+	}
+	return allErrs
+}
@@ -32,7 +32,17 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&Foo{},
 		&FooList{},
+		&FooRevision{},
+		&FooRevisionList{},
+		&FooRollback{},
+		&FooRendering{},
+		&FooReview{},
+		&FooSummary{},
+		&FooSummaryList{},
 		&Config{},
+		&ConfigList{},
+		&FooDefaults{},
+		&FooDefaultsList{},
 	)
 	return nil
 }
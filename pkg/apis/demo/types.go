@@ -1,6 +1,9 @@
 package demo
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -27,6 +30,16 @@ type FooSpec struct {
 	Image string
 	// Config is the configuration used by foo container
 	Config FooConfig
+	// ConfigName refers to a Config in the same namespace.
+	// Only kept when the ConfigResource feature gate is enabled.
+	// +optional
+	ConfigName string
+	// Replicas is the number of workers the Foo runs
+	Replicas int32
+	// TerminationGracePeriodSeconds is the grace period of the deletion of
+	// the Foo and of its workers
+	// +optional
+	TerminationGracePeriodSeconds *int64
 }
 
 type FooConfig struct {
@@ -41,11 +54,21 @@ type FooPhase string
 const (
 	FooPhaseProcessing FooPhase = "Processing"
 	FooPhaseReady      FooPhase = "Ready"
+	// FooPhaseTerminating is set by the registry when a Foo is deleted
+	FooPhaseTerminating FooPhase = "Terminating"
 )
 
+// FooCleanupFinalizer keeps a deleted Foo until the controller removed what
+// it created for it
+const FooCleanupFinalizer = "demo.k8s.io/cleanup"
+
 type FooStatus struct {
 	Phase      FooPhase
 	Conditions []FooCondition
+	// Replicas is the number of workers that run
+	Replicas int32
+	// Selector selects the pods of the workers, in the string form of a label selector
+	Selector string
 }
 
 type FooConditionType string
@@ -62,14 +85,178 @@ type FooCondition struct {
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// FooRevision is an immutable snapshot of the spec of a Foo. The registry
+// records one for every spec a Foo is created or updated with.
+type FooRevision struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	// Revision orders the revisions of a Foo, starting at 1
+	Revision int64
+	Spec     FooSpec
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type FooRevisionList struct {
+	metav1.TypeMeta
+	metav1.ListMeta
+
+	Items []FooRevision
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FooRollback restores the spec of a Foo from one of its revisions
+type FooRollback struct {
+	metav1.TypeMeta
+	// Name is the name of the Foo
+	metav1.ObjectMeta
+
+	Revision int64
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FooRendering is what the controller applies to the backing cluster for a Foo
+type FooRendering struct {
+	metav1.TypeMeta
+	// Name and Namespace are those of the Foo
+	metav1.ObjectMeta
+
+	// Spec is the spec of the Foo after defaulting
+	Spec FooSpec
+	// Objects are the ConfigMap and the Deployment of the Foo
+	Objects []runtime.RawExtension
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FooReview checks a Foo the way creating it would, without storing it
+type FooReview struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	Spec   FooReviewSpec
+	Status FooReviewStatus
+}
+
+type FooReviewSpec struct {
+	// Foo is the Foo to check. Its namespace defaults to "default".
+	Foo Foo
+}
+
+type FooReviewStatus struct {
+	// Allowed is true if creating the Foo would succeed
+	Allowed bool
+	// Foo is the Foo after defaulting and mutating admission
+	Foo Foo
+	// Errors are all reasons creating the Foo would fail
+	Errors []metav1.StatusCause
+	// Warnings are the warnings creating the Foo would return
+	Warnings []string
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FooSummary counts the Foos of one namespace
+type FooSummary struct {
+	metav1.TypeMeta
+	// Name is the namespace of the counted Foos
+	metav1.ObjectMeta
+
+	Status FooSummaryStatus
+}
+
+type FooSummaryStatus struct {
+	// Foos is the number of Foos in the namespace
+	Foos int32
+	// Phases counts the Foos by phase. Foos without a phase are not counted.
+	Phases map[FooPhase]int32
+	// Conditions counts the Foos by the status of each condition type
+	Conditions []FooConditionSummary
+	// Images counts the Foos by image
+	Images map[string]int32
+}
+
+type FooConditionSummary struct {
+	Type    FooConditionType
+	True    int32
+	False   int32
+	Unknown int32
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type FooSummaryList struct {
+	metav1.TypeMeta
+	metav1.ListMeta
+
+	Items []FooSummary
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 type Config struct {
 	metav1.TypeMeta
 	metav1.ObjectMeta
 
 	Spec ConfigSpec
+	// Immutable keeps the spec from being changed and itself from being
+	// unset once it is true
+	Immutable *bool
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ConfigList struct {
+	metav1.TypeMeta
+	metav1.ListMeta
+
+	Items []Config
 }
 
 type ConfigSpec struct {
 	Msg  string
 	Msg1 string
 }
+
+// FooDefaultsAnnotation lists the fields of a Foo that FooDefaults supplied
+// on creation, with the FooDefaults each came from
+const FooDefaultsAnnotation = "demo.k8s.io/applied-foodefaults"
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FooDefaults supplies defaults to the Foos created in its namespace.
+type FooDefaults struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	Spec FooDefaultsSpec
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type FooDefaultsList struct {
+	metav1.TypeMeta
+	metav1.ListMeta
+
+	Items []FooDefaults
+}
+
+type FooDefaultsSpec struct {
+	// Selector limits the defaults to the Foos whose labels match. Nil
+	// selects every Foo.
+	Selector *metav1.LabelSelector
+	// Priority orders the FooDefaults that select the same Foo, highest
+	// first, then by name
+	Priority int32
+	// ImageRegistry is prepended to images that name no registry
+	ImageRegistry string
+	// ImageTag is appended to images that have neither tag nor digest
+	ImageTag string
+	// Msg is the default of spec.config.msg
+	Msg string
+	// Labels are added to Foos that do not have them
+	Labels map[string]string
+}
@@ -1,8 +1,13 @@
 package v1alpha1
 
+import "k8s.io/utils/ptr"
+
 func SetDefaults_Foo(obj *Foo) {
 	if obj.Labels == nil {
 		obj.Labels = make(map[string]string)
 	}
 	obj.Labels["demo.k8s.io/metadata.name"] = obj.Name
+	if obj.Spec.Replicas == nil {
+		obj.Spec.Replicas = ptr.To[int32](1)
+	}
 }
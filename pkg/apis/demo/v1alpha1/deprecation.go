@@ -0,0 +1,42 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// The methods below mark v1alpha1 Foos as deprecated in favour of v1beta1.
+// Requests against them and the discovery document of v1alpha1 get a Warning
+// header, and they are counted in the apiserver_requested_deprecated_apis
+// metric. The releases are those of Kubernetes, whose libraries the server is
+// built with: v1beta1 came with 1.30, and v1alpha1 Foos get three releases
+// like a beta version would, since they were the only ones before.
+
+// APILifecycleDeprecated returns the release in which the API struct was or will be deprecated.
+func (in *Foo) APILifecycleDeprecated() (major, minor int) {
+	return 1, 30
+}
+
+// APILifecycleRemoved returns the release in which the API is no longer served.
+func (in *Foo) APILifecycleRemoved() (major, minor int) {
+	return 1, 33
+}
+
+// APILifecycleReplacement returns the GVK of the replacement for the given API.
+func (in *Foo) APILifecycleReplacement() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: GroupName, Version: "v1beta1", Kind: "Foo"}
+}
+
+// APILifecycleDeprecated returns the release in which the API struct was or will be deprecated.
+func (in *FooList) APILifecycleDeprecated() (major, minor int) {
+	return 1, 30
+}
+
+// APILifecycleRemoved returns the release in which the API is no longer served.
+func (in *FooList) APILifecycleRemoved() (major, minor int) {
+	return 1, 33
+}
+
+// APILifecycleReplacement returns the GVK of the replacement for the given API.
+func (in *FooList) APILifecycleReplacement() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: GroupName, Version: "v1beta1", Kind: "FooList"}
+}
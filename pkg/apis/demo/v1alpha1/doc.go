@@ -23,6 +23,6 @@ limitations under the License.
 // +k8s:defaulter-gen=TypeMeta
 // +k8s:protobuf-gen=package
 // +k8s:conversion-gen=github.com/guodoliu/apiserver/pkg/apis/demo
-// +groupName=demo.guodoliu.bytedance.com
+// +groupName=demo.k8s.io
 
 package v1alpha1 // import "apiserver/pkg/apis/demo/v1alpha1"
@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	kjson "sigs.k8s.io/json"
+	"strings"
+)
+
+// UnmarshalJSON also accepts the capitalized field names FooSpec and FooConfig
+// were serialized with before they had JSON tags, that is Image, Config, Msg
+// and Msg1, so that older clients keep working and foos stored by older
+// servers stay readable. Field names are otherwise matched case-sensitively
+// like the apimachinery decoder does. A field given twice, or under both its
+// name and its legacy name, is an error.
+//
+// Unknown fields are dropped without an error whatever the requested field
+// validation, because foos stored by newer servers must stay readable after a
+// downgrade.
+func (in *FooSpec) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+
+	type fooSpec FooSpec
+	spec := struct {
+		fooSpec
+		LegacyImage  *string          `json:"Image"`
+		LegacyConfig *legacyFooConfig `json:"Config"`
+	}{fooSpec: fooSpec(*in)}
+	if err := unmarshalLegacy(data, &spec, map[string]string{"Image": "image", "Config": "config"}); err != nil {
+		return err
+	}
+	if spec.LegacyImage != nil {
+		spec.Image = *spec.LegacyImage
+	}
+	if spec.LegacyConfig != nil {
+		if spec.LegacyConfig.Msg != nil {
+			spec.Config.Msg = *spec.LegacyConfig.Msg
+		}
+		if spec.LegacyConfig.Msg1 != nil {
+			spec.Config.Msg1 = *spec.LegacyConfig.Msg1
+		}
+	}
+	*in = FooSpec(spec.fooSpec)
+	return nil
+}
+
+// legacyFooConfig is FooConfig as serialized before it had JSON tags.
+// +k8s:openapi-gen=false
+type legacyFooConfig struct {
+	Msg  *string `json:"Msg"`
+	Msg1 *string `json:"Msg1"`
+}
+
+func (in *legacyFooConfig) UnmarshalJSON(data []byte) error {
+	type config legacyFooConfig
+	return unmarshalLegacy(data, (*config)(in), nil)
+}
+
+// unmarshalLegacy decodes data into v case-sensitively, failing on duplicate
+// fields and on fields given under both a legacy name and the name it maps to
+// in legacyNames.
+func unmarshalLegacy(data []byte, v interface{}, legacyNames map[string]string) error {
+	strictErrs, err := kjson.UnmarshalStrict(data, v, kjson.DisallowDuplicateFields)
+	if err != nil {
+		return err
+	}
+	if len(strictErrs) > 0 {
+		messages := make([]string, 0, len(strictErrs))
+		for _, err := range strictErrs {
+			messages = append(messages, err.Error())
+		}
+		return fmt.Errorf("%s", strings.Join(messages, ", "))
+	}
+	if len(legacyNames) == 0 {
+		return nil
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := kjson.UnmarshalCaseSensitivePreserveInts(data, &fields); err != nil {
+		return err
+	}
+	for legacy, name := range legacyNames {
+		_, hasLegacy := fields[legacy]
+		if _, ok := fields[name]; ok && hasLegacy {
+			return fmt.Errorf("%q and its legacy name %q must not both be set", name, legacy)
+		}
+	}
+	return nil
+}
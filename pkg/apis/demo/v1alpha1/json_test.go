@@ -0,0 +1,125 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFooSpecUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		// initial is what the spec holds before decoding
+		initial FooSpec
+		want    FooSpec
+		// err is a substring of the expected error
+		err string
+	}{
+		{
+			name: "current names",
+			data: `{"image":"busybox:1.36","config":{"msg":"hi","msg1":"there"},"configName":"cfg"}`,
+			want: FooSpec{Image: "busybox:1.36", Config: FooConfig{Msg: "hi", Msg1: "there"}, ConfigName: "cfg"},
+		},
+		{
+			name: "legacy names",
+			data: `{"Image":"busybox:1.36","Config":{"Msg":"hi","Msg1":"there"}}`,
+			want: FooSpec{Image: "busybox:1.36", Config: FooConfig{Msg: "hi", Msg1: "there"}},
+		},
+		{
+			name: "legacy and current names for different fields",
+			data: `{"Image":"busybox:1.36","config":{"msg":"hi"}}`,
+			want: FooSpec{Image: "busybox:1.36", Config: FooConfig{Msg: "hi"}},
+		},
+		{
+			name:    "null",
+			data:    `null`,
+			initial: FooSpec{Image: "busybox:1.36"},
+			want:    FooSpec{Image: "busybox:1.36"},
+		},
+		{
+			name:    "fields not given are kept",
+			data:    `{"config":{"msg":"hi"}}`,
+			initial: FooSpec{Image: "busybox:1.36"},
+			want:    FooSpec{Image: "busybox:1.36", Config: FooConfig{Msg: "hi"}},
+		},
+		{
+			name: "other capitalizations are unknown",
+			data: `{"image":"busybox:1.36","IMAGE":"alpine","config":{"msg":"hi","MSG":"ignored"}}`,
+			want: FooSpec{Image: "busybox:1.36", Config: FooConfig{Msg: "hi"}},
+		},
+		{
+			name: "current names in a legacy config are unknown",
+			data: `{"image":"busybox:1.36","Config":{"msg":"ignored","Msg":"hi"}}`,
+			want: FooSpec{Image: "busybox:1.36", Config: FooConfig{Msg: "hi"}},
+		},
+		{
+			name: "unknown fields",
+			data: `{"image":"busybox:1.36","newField":true}`,
+			want: FooSpec{Image: "busybox:1.36"},
+		},
+		{
+			name: "image under both names",
+			data: `{"image":"a","Image":"b"}`,
+			err:  `"image" and its legacy name "Image"`,
+		},
+		{
+			name: "config under both names",
+			data: `{"image":"a","config":{"msg":"hi"},"Config":{"Msg":"hi"}}`,
+			err:  `"config" and its legacy name "Config"`,
+		},
+		{
+			name: "duplicate field",
+			data: `{"image":"a","image":"b"}`,
+			err:  `duplicate field "image"`,
+		},
+		{
+			name: "duplicate legacy field",
+			data: `{"image":"a","Config":{"Msg":"hi","Msg":"there"}}`,
+			err:  `duplicate field "Msg"`,
+		},
+		{
+			name: "wrong type",
+			data: `{"image":1}`,
+			err:  "cannot unmarshal number",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.initial
+			err := json.Unmarshal([]byte(tc.data), &got)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected an error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFooSpecRoundTrip(t *testing.T) {
+	for _, spec := range []FooSpec{
+		{Image: "busybox:1.36"},
+		{Image: "busybox:1.36", Config: FooConfig{Msg: "hi", Msg1: "there"}, ConfigName: "cfg"},
+	} {
+		data, err := json.Marshal(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got FooSpec
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, spec) {
+			t.Errorf("expected %+v after a round trip through %s, got %+v", spec, data, got)
+		}
+	}
+}
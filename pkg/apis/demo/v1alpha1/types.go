@@ -23,48 +23,131 @@ import (
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// Foo runs a configured image as a Deployment in the backing cluster
 type Foo struct {
-	metav1.TypeMeta   `json:",inline"`
-	metav1.ObjectMeta `json:"metadata,omitempty"`
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
 
-	Spec   FooSpec   `json:"spec,omitempty"`
-	Status FooStatus `json:"status,omitempty"`
+	// Spec is the desired state of the Foo
+	// +optional
+	Spec FooSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	// Status is the observed state of the Foo
+	// +optional
+	Status FooStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
 }
 
-// FooList
+// FooList is a list of Foo objects.
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type FooList struct {
 	metav1.TypeMeta `json:",inline"`
-	metav1.ListMeta `json:"metadata,omitempty"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
 
-	Items []Foo `json:"items"`
+	Items []Foo `json:"items" protobuf:"bytes,2,rep,name=items"`
 }
 
 // FooSpec defines the desired state of Foo
 type FooSpec struct {
-	// Container image that the container is running to do our foo work
-	Image string
+	// Image is the container image that the container is running to do our foo work
+	Image string `json:"image" protobuf:"bytes,1,opt,name=image"`
 	// Config is the configuration used by foo container
-	Config FooConfig
+	// +optional
+	Config FooConfig `json:"config,omitempty" protobuf:"bytes,2,opt,name=config"`
+	// ConfigName refers to a Config in the same namespace, whose messages
+	// are used in place of config.
+	// Only kept when the ConfigResource feature gate is enabled.
+	// +optional
+	ConfigName string `json:"configName,omitempty" protobuf:"bytes,3,opt,name=configName"`
+	// Replicas is the number of workers the Foo runs. Defaults to 1.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty" protobuf:"varint,4,opt,name=replicas"`
+	// TerminationGracePeriodSeconds is how long a deleted Foo waits for its
+	// workers to stop, unless the delete request asks for another period. It
+	// is also the grace period of the pods of the workers. Foos created while
+	// the server does not run its controllers are deleted without waiting.
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty" protobuf:"varint,5,opt,name=terminationGracePeriodSeconds"`
 }
 
+// FooConfig is mounted into the container of a Foo from a ConfigMap
 type FooConfig struct {
 	// Msg says hello world!
-	Msg string
+	Msg string `json:"msg" protobuf:"bytes,1,opt,name=msg"`
+	// Msg1 is a second message.
+	// Deprecated: put the whole message in msg.
 	// +optional
-	Msg1 string
+	Msg1 string `json:"msg1,omitempty" protobuf:"bytes,2,opt,name=msg1"`
 }
 
+// FooStatus is the observed state of a Foo, reported by the controller
 type FooStatus struct {
-	Phase      FooPhase       `json:"phase,omitempty"`
-	Conditions []FooCondition `json:"conditions,omitempty"`
+	// Phase is Ready once the workers of the Foo are up, Processing before,
+	// and Terminating once the Foo is deleted
+	// +optional
+	Phase FooPhase `json:"phase,omitempty" protobuf:"bytes,1,opt,name=phase,casttype=FooPhase"`
+	// Conditions report the state of the Config and the Worker of the Foo,
+	// at most one per type
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []FooCondition `json:"conditions,omitempty" protobuf:"bytes,2,rep,name=conditions"`
+	// Replicas is the number of workers that run
+	// +optional
+	Replicas int32 `json:"replicas,omitempty" protobuf:"varint,3,opt,name=replicas"`
+	// Selector selects the pods of the workers, in the string form of a
+	// label selector
+	// +optional
+	Selector string `json:"selector,omitempty" protobuf:"bytes,4,opt,name=selector"`
 }
 
+// FooPhase is Processing, Ready or Terminating
 type FooPhase string
 
+// FooConditionType is Config or Worker
 type FooConditionType string
 
+// FooCondition is the state of one aspect of a Foo
 type FooCondition struct {
-	Type   FooConditionType       `json:"type"`
-	Status metav1.ConditionStatus `json:"status"`
+	// Type is Config or Worker
+	Type FooConditionType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=FooConditionType"`
+	// Status is True, False or Unknown
+	Status metav1.ConditionStatus `json:"status" protobuf:"bytes,2,opt,name=status,casttype=k8s.io/apimachinery/pkg/apis/meta/v1.ConditionStatus"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Config holds settings that Foos in the same namespace can share
+type Config struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Spec holds the shared settings
+	// +optional
+	Spec ConfigSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	// Immutable, if true, ensures that the spec cannot be updated. It cannot
+	// be unset either; the Config can only be deleted and created again.
+	// Controllers do not need to watch immutable Configs for changes.
+	// +optional
+	Immutable *bool `json:"immutable,omitempty" protobuf:"varint,3,opt,name=immutable"`
+}
+
+// ConfigList is a list of Config objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []Config `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// ConfigSpec holds the messages of the Foos that refer to the Config
+type ConfigSpec struct {
+	// Msg says hello world!
+	// +optional
+	Msg string `json:"msg,omitempty" protobuf:"bytes,1,opt,name=msg"`
+	// Msg1 is a second message
+	// +optional
+	Msg1 string `json:"msg1,omitempty" protobuf:"bytes,2,opt,name=msg1"`
 }
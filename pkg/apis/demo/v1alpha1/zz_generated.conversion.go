@@ -36,6 +36,36 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*Config)(nil), (*demo.Config)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Config_To_demo_Config(a.(*Config), b.(*demo.Config), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.Config)(nil), (*Config)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_Config_To_v1alpha1_Config(a.(*demo.Config), b.(*Config), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ConfigList)(nil), (*demo.ConfigList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ConfigList_To_demo_ConfigList(a.(*ConfigList), b.(*demo.ConfigList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.ConfigList)(nil), (*ConfigList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_ConfigList_To_v1alpha1_ConfigList(a.(*demo.ConfigList), b.(*ConfigList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ConfigSpec)(nil), (*demo.ConfigSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ConfigSpec_To_demo_ConfigSpec(a.(*ConfigSpec), b.(*demo.ConfigSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.ConfigSpec)(nil), (*ConfigSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_ConfigSpec_To_v1alpha1_ConfigSpec(a.(*demo.ConfigSpec), b.(*ConfigSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Foo)(nil), (*demo.Foo)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_Foo_To_demo_Foo(a.(*Foo), b.(*demo.Foo), scope)
 	}); err != nil {
@@ -99,6 +129,78 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1alpha1_Config_To_demo_Config(in *Config, out *demo.Config, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha1_ConfigSpec_To_demo_ConfigSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	out.Immutable = (*bool)(unsafe.Pointer(in.Immutable))
+	return nil
+}
+
+// Convert_v1alpha1_Config_To_demo_Config is an autogenerated conversion function.
+func Convert_v1alpha1_Config_To_demo_Config(in *Config, out *demo.Config, s conversion.Scope) error {
+	return autoConvert_v1alpha1_Config_To_demo_Config(in, out, s)
+}
+
+func autoConvert_demo_Config_To_v1alpha1_Config(in *demo.Config, out *Config, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_demo_ConfigSpec_To_v1alpha1_ConfigSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	out.Immutable = (*bool)(unsafe.Pointer(in.Immutable))
+	return nil
+}
+
+// Convert_demo_Config_To_v1alpha1_Config is an autogenerated conversion function.
+func Convert_demo_Config_To_v1alpha1_Config(in *demo.Config, out *Config, s conversion.Scope) error {
+	return autoConvert_demo_Config_To_v1alpha1_Config(in, out, s)
+}
+
+func autoConvert_v1alpha1_ConfigList_To_demo_ConfigList(in *ConfigList, out *demo.ConfigList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]demo.Config)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1alpha1_ConfigList_To_demo_ConfigList is an autogenerated conversion function.
+func Convert_v1alpha1_ConfigList_To_demo_ConfigList(in *ConfigList, out *demo.ConfigList, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ConfigList_To_demo_ConfigList(in, out, s)
+}
+
+func autoConvert_demo_ConfigList_To_v1alpha1_ConfigList(in *demo.ConfigList, out *ConfigList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]Config)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_demo_ConfigList_To_v1alpha1_ConfigList is an autogenerated conversion function.
+func Convert_demo_ConfigList_To_v1alpha1_ConfigList(in *demo.ConfigList, out *ConfigList, s conversion.Scope) error {
+	return autoConvert_demo_ConfigList_To_v1alpha1_ConfigList(in, out, s)
+}
+
+func autoConvert_v1alpha1_ConfigSpec_To_demo_ConfigSpec(in *ConfigSpec, out *demo.ConfigSpec, s conversion.Scope) error {
+	out.Msg = in.Msg
+	out.Msg1 = in.Msg1
+	return nil
+}
+
+// Convert_v1alpha1_ConfigSpec_To_demo_ConfigSpec is an autogenerated conversion function.
+func Convert_v1alpha1_ConfigSpec_To_demo_ConfigSpec(in *ConfigSpec, out *demo.ConfigSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ConfigSpec_To_demo_ConfigSpec(in, out, s)
+}
+
+func autoConvert_demo_ConfigSpec_To_v1alpha1_ConfigSpec(in *demo.ConfigSpec, out *ConfigSpec, s conversion.Scope) error {
+	out.Msg = in.Msg
+	out.Msg1 = in.Msg1
+	return nil
+}
+
+// Convert_demo_ConfigSpec_To_v1alpha1_ConfigSpec is an autogenerated conversion function.
+func Convert_demo_ConfigSpec_To_v1alpha1_ConfigSpec(in *demo.ConfigSpec, out *ConfigSpec, s conversion.Scope) error {
+	return autoConvert_demo_ConfigSpec_To_v1alpha1_ConfigSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_Foo_To_demo_Foo(in *Foo, out *demo.Foo, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha1_FooSpec_To_demo_FooSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -177,7 +279,17 @@ func Convert_demo_FooConfig_To_v1alpha1_FooConfig(in *demo.FooConfig, out *FooCo
 
 func autoConvert_v1alpha1_FooList_To_demo_FooList(in *FooList, out *demo.FooList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]demo.Foo)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]demo.Foo, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_Foo_To_demo_Foo(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -188,7 +300,17 @@ func Convert_v1alpha1_FooList_To_demo_FooList(in *FooList, out *demo.FooList, s
 
 func autoConvert_demo_FooList_To_v1alpha1_FooList(in *demo.FooList, out *FooList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]Foo)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Foo, len(*in))
+		for i := range *in {
+			if err := Convert_demo_Foo_To_v1alpha1_Foo(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -202,6 +324,11 @@ func autoConvert_v1alpha1_FooSpec_To_demo_FooSpec(in *FooSpec, out *demo.FooSpec
 	if err := Convert_v1alpha1_FooConfig_To_demo_FooConfig(&in.Config, &out.Config, s); err != nil {
 		return err
 	}
+	out.ConfigName = in.ConfigName
+	if err := v1.Convert_Pointer_int32_To_int32(&in.Replicas, &out.Replicas, s); err != nil {
+		return err
+	}
+	out.TerminationGracePeriodSeconds = (*int64)(unsafe.Pointer(in.TerminationGracePeriodSeconds))
 	return nil
 }
 
@@ -215,6 +342,11 @@ func autoConvert_demo_FooSpec_To_v1alpha1_FooSpec(in *demo.FooSpec, out *FooSpec
 	if err := Convert_demo_FooConfig_To_v1alpha1_FooConfig(&in.Config, &out.Config, s); err != nil {
 		return err
 	}
+	out.ConfigName = in.ConfigName
+	if err := v1.Convert_int32_To_Pointer_int32(&in.Replicas, &out.Replicas, s); err != nil {
+		return err
+	}
+	out.TerminationGracePeriodSeconds = (*int64)(unsafe.Pointer(in.TerminationGracePeriodSeconds))
 	return nil
 }
 
@@ -226,6 +358,8 @@ func Convert_demo_FooSpec_To_v1alpha1_FooSpec(in *demo.FooSpec, out *FooSpec, s
 func autoConvert_v1alpha1_FooStatus_To_demo_FooStatus(in *FooStatus, out *demo.FooStatus, s conversion.Scope) error {
 	out.Phase = demo.FooPhase(in.Phase)
 	out.Conditions = *(*[]demo.FooCondition)(unsafe.Pointer(&in.Conditions))
+	out.Replicas = in.Replicas
+	out.Selector = in.Selector
 	return nil
 }
 
@@ -237,6 +371,8 @@ func Convert_v1alpha1_FooStatus_To_demo_FooStatus(in *FooStatus, out *demo.FooSt
 func autoConvert_demo_FooStatus_To_v1alpha1_FooStatus(in *demo.FooStatus, out *FooStatus, s conversion.Scope) error {
 	out.Phase = FooPhase(in.Phase)
 	out.Conditions = *(*[]FooCondition)(unsafe.Pointer(&in.Conditions))
+	out.Replicas = in.Replicas
+	out.Selector = in.Selector
 	return nil
 }
 
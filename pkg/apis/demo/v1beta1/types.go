@@ -0,0 +1,337 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +genclient:method=History,verb=get,subresource=history,result=FooRevisionList
+// +genclient:method=Rollback,verb=create,subresource=rollback,input=FooRollback,result=Foo
+// +genclient:method=Render,verb=get,subresource=render,result=FooRendering
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Foo runs a configured image as a Deployment in the backing cluster
+type Foo struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Spec is the desired state of the Foo
+	// +optional
+	Spec FooSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+	// Status is the observed state of the Foo
+	// +optional
+	Status FooStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// FooList is a list of Foo objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type FooList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []Foo `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// FooSpec defines the desired state of Foo
+type FooSpec struct {
+	// Image is the container image that the container is running to do our foo work
+	Image string `json:"image" protobuf:"bytes,1,opt,name=image"`
+	// Config is the configuration used by foo container
+	// +optional
+	Config FooConfig `json:"config,omitempty" protobuf:"bytes,2,opt,name=config"`
+	// ConfigName refers to a Config in the same namespace, whose messages
+	// are used in place of config.
+	// Only kept when the ConfigResource feature gate is enabled.
+	// +optional
+	ConfigName string `json:"configName,omitempty" protobuf:"bytes,3,opt,name=configName"`
+	// Replicas is the number of workers the Foo runs. Defaults to 1.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty" protobuf:"varint,4,opt,name=replicas"`
+	// TerminationGracePeriodSeconds is how long a deleted Foo waits for its
+	// workers to stop, unless the delete request asks for another period. It
+	// is also the grace period of the pods of the workers. Foos created while
+	// the server does not run its controllers are deleted without waiting.
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty" protobuf:"varint,5,opt,name=terminationGracePeriodSeconds"`
+}
+
+// FooConfig is mounted into the container of a Foo from a ConfigMap
+type FooConfig struct {
+	// Msg says hello world!
+	Msg string `json:"msg" protobuf:"bytes,1,opt,name=msg"`
+	// Msg1 is a second message.
+	// Deprecated: put the whole message in msg.
+	// +optional
+	Msg1 string `json:"msg1,omitempty" protobuf:"bytes,2,opt,name=msg1"`
+}
+
+// FooStatus is the observed state of a Foo, reported by the controller
+type FooStatus struct {
+	// Phase is Ready once the workers of the Foo are up, Processing before,
+	// and Terminating once the Foo is deleted
+	// +optional
+	Phase FooPhase `json:"phase,omitempty" protobuf:"bytes,1,opt,name=phase,casttype=FooPhase"`
+	// Conditions report the state of the Config and the Worker of the Foo,
+	// at most one per type
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []FooCondition `json:"conditions,omitempty" protobuf:"bytes,2,rep,name=conditions"`
+	// Replicas is the number of workers that run
+	// +optional
+	Replicas int32 `json:"replicas,omitempty" protobuf:"varint,3,opt,name=replicas"`
+	// Selector selects the pods of the workers, in the string form of a
+	// label selector. It is what the scale subresource reports.
+	// +optional
+	Selector string `json:"selector,omitempty" protobuf:"bytes,4,opt,name=selector"`
+}
+
+// FooPhase is Processing, Ready or Terminating
+type FooPhase string
+
+const (
+	FooPhaseProcessing  FooPhase = "Processing"
+	FooPhaseReady       FooPhase = "Ready"
+	FooPhaseTerminating FooPhase = "Terminating"
+)
+
+// FooCleanupFinalizer is added to every Foo on creation if the server runs
+// the embedded controllers. The controller removes it once the Deployment and
+// ConfigMap of a deleted Foo are gone.
+const FooCleanupFinalizer = "demo.k8s.io/cleanup"
+
+// FooConditionType is Config or Worker
+type FooConditionType string
+
+const (
+	FooConditionTypeWorker FooConditionType = "Worker"
+	FooConditionTypeConfig FooConditionType = "Config"
+)
+
+// FooCondition is the state of one aspect of a Foo
+type FooCondition struct {
+	// Type is Config or Worker
+	Type FooConditionType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=FooConditionType"`
+	// Status is True, False or Unknown
+	Status metav1.ConditionStatus `json:"status" protobuf:"bytes,2,opt,name=status,casttype=k8s.io/apimachinery/pkg/apis/meta/v1.ConditionStatus"`
+}
+
+// FooRevision is an immutable snapshot of the spec of a Foo. The server
+// records one for every spec a Foo is created or updated with and lists them
+// through the foos/history subresource.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type FooRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Revision orders the revisions of a Foo, starting at 1
+	Revision int64 `json:"revision" protobuf:"varint,2,opt,name=revision"`
+	// Spec is the spec of the Foo at this revision
+	Spec FooSpec `json:"spec" protobuf:"bytes,3,opt,name=spec"`
+}
+
+// FooRevisionList is returned by the foos/history subresource, oldest revision first
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type FooRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []FooRevision `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// FooRollback is posted to the foos/rollback subresource to restore the spec
+// of a Foo from one of its revisions.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type FooRollback struct {
+	metav1.TypeMeta `json:",inline"`
+	// name is the name of the Foo
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// revision is the revision whose spec is restored
+	Revision int64 `json:"revision" protobuf:"varint,2,opt,name=revision"`
+}
+
+// FooRendering is returned by the foos/render subresource. It shows what the
+// controller applies to the backing cluster for a Foo.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type FooRendering struct {
+	metav1.TypeMeta `json:",inline"`
+	// name and namespace are those of the Foo
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// spec is the spec of the Foo after defaulting
+	Spec FooSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+	// objects are the ConfigMap and the Deployment of the Foo
+	// +listType=atomic
+	Objects []runtime.RawExtension `json:"objects" protobuf:"bytes,3,rep,name=objects"`
+}
+
+// FooReview checks a Foo the way creating it would: it is defaulted,
+// validated and admitted, but never stored. Only create is supported.
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=create
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type FooReview struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Spec FooReviewSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+	// +optional
+	Status FooReviewStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// FooReviewSpec is the Foo a FooReview checks
+type FooReviewSpec struct {
+	// foo is the Foo to check. Its namespace defaults to "default".
+	Foo Foo `json:"foo" protobuf:"bytes,1,opt,name=foo"`
+}
+
+// FooReviewStatus is the outcome of a FooReview
+type FooReviewStatus struct {
+	// allowed is true if creating the Foo would succeed
+	Allowed bool `json:"allowed" protobuf:"varint,1,opt,name=allowed"`
+	// foo is the Foo after defaulting and mutating admission
+	// +optional
+	Foo Foo `json:"foo,omitempty" protobuf:"bytes,2,opt,name=foo"`
+	// errors are all reasons creating the Foo would fail. Field paths are
+	// relative to the Foo.
+	// +optional
+	// +listType=atomic
+	Errors []metav1.StatusCause `json:"errors,omitempty" protobuf:"bytes,3,rep,name=errors"`
+	// warnings are the warnings creating the Foo would return
+	// +optional
+	// +listType=atomic
+	Warnings []string `json:"warnings,omitempty" protobuf:"bytes,4,rep,name=warnings"`
+}
+
+// FooSummary counts the Foos of one namespace by phase, condition and image.
+// Summaries are computed from a watch on the Foos and are read-only.
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:onlyVerbs=get,list,watch
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type FooSummary struct {
+	metav1.TypeMeta `json:",inline"`
+	// name is the namespace of the counted Foos
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// status holds the counts
+	// +optional
+	Status FooSummaryStatus `json:"status,omitempty" protobuf:"bytes,2,opt,name=status"`
+}
+
+// FooSummaryStatus counts the Foos of a namespace
+type FooSummaryStatus struct {
+	// foos is the number of Foos in the namespace
+	Foos int32 `json:"foos" protobuf:"varint,1,opt,name=foos"`
+	// phases counts the Foos by phase. Foos without a phase are not counted.
+	// +optional
+	Phases map[FooPhase]int32 `json:"phases,omitempty" protobuf:"bytes,2,rep,name=phases,castkey=FooPhase"`
+	// conditions counts the Foos by the status of each condition type
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []FooConditionSummary `json:"conditions,omitempty" protobuf:"bytes,3,rep,name=conditions"`
+	// images counts the Foos by image
+	// +optional
+	Images map[string]int32 `json:"images,omitempty" protobuf:"bytes,4,rep,name=images"`
+}
+
+// FooConditionSummary counts the Foos by the status of one condition type
+type FooConditionSummary struct {
+	// type is the condition type
+	Type FooConditionType `json:"type" protobuf:"bytes,1,opt,name=type,casttype=FooConditionType"`
+	// true is the number of Foos whose condition is True
+	// +optional
+	True int32 `json:"true,omitempty" protobuf:"varint,2,opt,name=true"`
+	// false is the number of Foos whose condition is False
+	// +optional
+	False int32 `json:"false,omitempty" protobuf:"varint,3,opt,name=false"`
+	// unknown is the number of Foos whose condition is neither True nor False
+	// +optional
+	Unknown int32 `json:"unknown,omitempty" protobuf:"varint,4,opt,name=unknown"`
+}
+
+// FooSummaryList is a list of FooSummary objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type FooSummaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []FooSummary `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// FooDefaultsAnnotation is set on Foos that FooDefaults supplied defaults
+// to. It lists the defaulted fields with the FooDefaults each came from, as
+// in "imageRegistry=registry,labels.team=team-a".
+const FooDefaultsAnnotation = "demo.k8s.io/applied-foodefaults"
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FooDefaults supplies defaults to the Foos created in its namespace. A
+// field that several FooDefaults of a Foo set is taken from the one with the
+// highest priority; FooDefaults of equal priority are ordered by name. Foos
+// that already exist are left as they are.
+type FooDefaults struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Spec holds the defaults and the Foos they apply to
+	// +optional
+	Spec FooDefaultsSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// FooDefaultsList is a list of FooDefaults objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type FooDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	Items []FooDefaults `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// FooDefaultsSpec holds the defaults of the Foos a FooDefaults selects
+type FooDefaultsSpec struct {
+	// Selector limits the defaults to the Foos whose labels match, before
+	// any labels are defaulted. Unset, it selects every Foo of the namespace.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty" protobuf:"bytes,1,opt,name=selector"`
+	// Priority orders the FooDefaults that select the same Foo, highest
+	// first. Between 0 and 1000, defaults to 0.
+	// +optional
+	Priority int32 `json:"priority,omitempty" protobuf:"varint,2,opt,name=priority"`
+	// ImageRegistry is prepended to images that name no registry, such as
+	// "registry.example.com" or "registry.example.com:5000/team"
+	// +optional
+	ImageRegistry string `json:"imageRegistry,omitempty" protobuf:"bytes,3,opt,name=imageRegistry"`
+	// ImageTag is appended to images that have neither tag nor digest
+	// +optional
+	ImageTag string `json:"imageTag,omitempty" protobuf:"bytes,4,opt,name=imageTag"`
+	// Msg is the default of spec.config.msg
+	// +optional
+	Msg string `json:"msg,omitempty" protobuf:"bytes,5,opt,name=msg"`
+	// Labels are added to Foos that do not have them
+	// +optional
+	Labels map[string]string `json:"labels,omitempty" protobuf:"bytes,6,rep,name=labels"`
+}
@@ -0,0 +1,804 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by conversion-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	unsafe "unsafe"
+
+	demo "github.com/guodoliu/apiserver/pkg/apis/demo"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+func init() {
+	localSchemeBuilder.Register(RegisterConversions)
+}
+
+// RegisterConversions adds conversion functions to the given scheme.
+// Public to allow building arbitrary schemes.
+func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*Foo)(nil), (*demo.Foo)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Foo_To_demo_Foo(a.(*Foo), b.(*demo.Foo), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.Foo)(nil), (*Foo)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_Foo_To_v1beta1_Foo(a.(*demo.Foo), b.(*Foo), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooCondition)(nil), (*demo.FooCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooCondition_To_demo_FooCondition(a.(*FooCondition), b.(*demo.FooCondition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooCondition)(nil), (*FooCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooCondition_To_v1beta1_FooCondition(a.(*demo.FooCondition), b.(*FooCondition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooConditionSummary)(nil), (*demo.FooConditionSummary)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooConditionSummary_To_demo_FooConditionSummary(a.(*FooConditionSummary), b.(*demo.FooConditionSummary), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooConditionSummary)(nil), (*FooConditionSummary)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooConditionSummary_To_v1beta1_FooConditionSummary(a.(*demo.FooConditionSummary), b.(*FooConditionSummary), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooConfig)(nil), (*demo.FooConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooConfig_To_demo_FooConfig(a.(*FooConfig), b.(*demo.FooConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooConfig)(nil), (*FooConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooConfig_To_v1beta1_FooConfig(a.(*demo.FooConfig), b.(*FooConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooDefaults)(nil), (*demo.FooDefaults)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooDefaults_To_demo_FooDefaults(a.(*FooDefaults), b.(*demo.FooDefaults), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooDefaults)(nil), (*FooDefaults)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooDefaults_To_v1beta1_FooDefaults(a.(*demo.FooDefaults), b.(*FooDefaults), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooDefaultsList)(nil), (*demo.FooDefaultsList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooDefaultsList_To_demo_FooDefaultsList(a.(*FooDefaultsList), b.(*demo.FooDefaultsList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooDefaultsList)(nil), (*FooDefaultsList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooDefaultsList_To_v1beta1_FooDefaultsList(a.(*demo.FooDefaultsList), b.(*FooDefaultsList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooDefaultsSpec)(nil), (*demo.FooDefaultsSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooDefaultsSpec_To_demo_FooDefaultsSpec(a.(*FooDefaultsSpec), b.(*demo.FooDefaultsSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooDefaultsSpec)(nil), (*FooDefaultsSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooDefaultsSpec_To_v1beta1_FooDefaultsSpec(a.(*demo.FooDefaultsSpec), b.(*FooDefaultsSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooList)(nil), (*demo.FooList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooList_To_demo_FooList(a.(*FooList), b.(*demo.FooList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooList)(nil), (*FooList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooList_To_v1beta1_FooList(a.(*demo.FooList), b.(*FooList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooRendering)(nil), (*demo.FooRendering)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooRendering_To_demo_FooRendering(a.(*FooRendering), b.(*demo.FooRendering), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooRendering)(nil), (*FooRendering)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooRendering_To_v1beta1_FooRendering(a.(*demo.FooRendering), b.(*FooRendering), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooReview)(nil), (*demo.FooReview)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooReview_To_demo_FooReview(a.(*FooReview), b.(*demo.FooReview), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooReview)(nil), (*FooReview)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooReview_To_v1beta1_FooReview(a.(*demo.FooReview), b.(*FooReview), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooReviewSpec)(nil), (*demo.FooReviewSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooReviewSpec_To_demo_FooReviewSpec(a.(*FooReviewSpec), b.(*demo.FooReviewSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooReviewSpec)(nil), (*FooReviewSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooReviewSpec_To_v1beta1_FooReviewSpec(a.(*demo.FooReviewSpec), b.(*FooReviewSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooReviewStatus)(nil), (*demo.FooReviewStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooReviewStatus_To_demo_FooReviewStatus(a.(*FooReviewStatus), b.(*demo.FooReviewStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooReviewStatus)(nil), (*FooReviewStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooReviewStatus_To_v1beta1_FooReviewStatus(a.(*demo.FooReviewStatus), b.(*FooReviewStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooRevision)(nil), (*demo.FooRevision)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooRevision_To_demo_FooRevision(a.(*FooRevision), b.(*demo.FooRevision), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooRevision)(nil), (*FooRevision)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooRevision_To_v1beta1_FooRevision(a.(*demo.FooRevision), b.(*FooRevision), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooRevisionList)(nil), (*demo.FooRevisionList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooRevisionList_To_demo_FooRevisionList(a.(*FooRevisionList), b.(*demo.FooRevisionList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooRevisionList)(nil), (*FooRevisionList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooRevisionList_To_v1beta1_FooRevisionList(a.(*demo.FooRevisionList), b.(*FooRevisionList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooRollback)(nil), (*demo.FooRollback)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooRollback_To_demo_FooRollback(a.(*FooRollback), b.(*demo.FooRollback), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooRollback)(nil), (*FooRollback)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooRollback_To_v1beta1_FooRollback(a.(*demo.FooRollback), b.(*FooRollback), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooSpec)(nil), (*demo.FooSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooSpec_To_demo_FooSpec(a.(*FooSpec), b.(*demo.FooSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooSpec)(nil), (*FooSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooSpec_To_v1beta1_FooSpec(a.(*demo.FooSpec), b.(*FooSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooStatus)(nil), (*demo.FooStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooStatus_To_demo_FooStatus(a.(*FooStatus), b.(*demo.FooStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooStatus)(nil), (*FooStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooStatus_To_v1beta1_FooStatus(a.(*demo.FooStatus), b.(*FooStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooSummary)(nil), (*demo.FooSummary)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooSummary_To_demo_FooSummary(a.(*FooSummary), b.(*demo.FooSummary), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooSummary)(nil), (*FooSummary)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooSummary_To_v1beta1_FooSummary(a.(*demo.FooSummary), b.(*FooSummary), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooSummaryList)(nil), (*demo.FooSummaryList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooSummaryList_To_demo_FooSummaryList(a.(*FooSummaryList), b.(*demo.FooSummaryList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooSummaryList)(nil), (*FooSummaryList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooSummaryList_To_v1beta1_FooSummaryList(a.(*demo.FooSummaryList), b.(*FooSummaryList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FooSummaryStatus)(nil), (*demo.FooSummaryStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_FooSummaryStatus_To_demo_FooSummaryStatus(a.(*FooSummaryStatus), b.(*demo.FooSummaryStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*demo.FooSummaryStatus)(nil), (*FooSummaryStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_demo_FooSummaryStatus_To_v1beta1_FooSummaryStatus(a.(*demo.FooSummaryStatus), b.(*FooSummaryStatus), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1beta1_Foo_To_demo_Foo(in *Foo, out *demo.Foo, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_FooSpec_To_demo_FooSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_FooStatus_To_demo_FooStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_Foo_To_demo_Foo is an autogenerated conversion function.
+func Convert_v1beta1_Foo_To_demo_Foo(in *Foo, out *demo.Foo, s conversion.Scope) error {
+	return autoConvert_v1beta1_Foo_To_demo_Foo(in, out, s)
+}
+
+func autoConvert_demo_Foo_To_v1beta1_Foo(in *demo.Foo, out *Foo, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_demo_FooSpec_To_v1beta1_FooSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_demo_FooStatus_To_v1beta1_FooStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_demo_Foo_To_v1beta1_Foo is an autogenerated conversion function.
+func Convert_demo_Foo_To_v1beta1_Foo(in *demo.Foo, out *Foo, s conversion.Scope) error {
+	return autoConvert_demo_Foo_To_v1beta1_Foo(in, out, s)
+}
+
+func autoConvert_v1beta1_FooCondition_To_demo_FooCondition(in *FooCondition, out *demo.FooCondition, s conversion.Scope) error {
+	out.Type = demo.FooConditionType(in.Type)
+	out.Status = v1.ConditionStatus(in.Status)
+	return nil
+}
+
+// Convert_v1beta1_FooCondition_To_demo_FooCondition is an autogenerated conversion function.
+func Convert_v1beta1_FooCondition_To_demo_FooCondition(in *FooCondition, out *demo.FooCondition, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooCondition_To_demo_FooCondition(in, out, s)
+}
+
+func autoConvert_demo_FooCondition_To_v1beta1_FooCondition(in *demo.FooCondition, out *FooCondition, s conversion.Scope) error {
+	out.Type = FooConditionType(in.Type)
+	out.Status = v1.ConditionStatus(in.Status)
+	return nil
+}
+
+// Convert_demo_FooCondition_To_v1beta1_FooCondition is an autogenerated conversion function.
+func Convert_demo_FooCondition_To_v1beta1_FooCondition(in *demo.FooCondition, out *FooCondition, s conversion.Scope) error {
+	return autoConvert_demo_FooCondition_To_v1beta1_FooCondition(in, out, s)
+}
+
+func autoConvert_v1beta1_FooConditionSummary_To_demo_FooConditionSummary(in *FooConditionSummary, out *demo.FooConditionSummary, s conversion.Scope) error {
+	out.Type = demo.FooConditionType(in.Type)
+	out.True = in.True
+	out.False = in.False
+	out.Unknown = in.Unknown
+	return nil
+}
+
+// Convert_v1beta1_FooConditionSummary_To_demo_FooConditionSummary is an autogenerated conversion function.
+func Convert_v1beta1_FooConditionSummary_To_demo_FooConditionSummary(in *FooConditionSummary, out *demo.FooConditionSummary, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooConditionSummary_To_demo_FooConditionSummary(in, out, s)
+}
+
+func autoConvert_demo_FooConditionSummary_To_v1beta1_FooConditionSummary(in *demo.FooConditionSummary, out *FooConditionSummary, s conversion.Scope) error {
+	out.Type = FooConditionType(in.Type)
+	out.True = in.True
+	out.False = in.False
+	out.Unknown = in.Unknown
+	return nil
+}
+
+// Convert_demo_FooConditionSummary_To_v1beta1_FooConditionSummary is an autogenerated conversion function.
+func Convert_demo_FooConditionSummary_To_v1beta1_FooConditionSummary(in *demo.FooConditionSummary, out *FooConditionSummary, s conversion.Scope) error {
+	return autoConvert_demo_FooConditionSummary_To_v1beta1_FooConditionSummary(in, out, s)
+}
+
+func autoConvert_v1beta1_FooConfig_To_demo_FooConfig(in *FooConfig, out *demo.FooConfig, s conversion.Scope) error {
+	out.Msg = in.Msg
+	out.Msg1 = in.Msg1
+	return nil
+}
+
+// Convert_v1beta1_FooConfig_To_demo_FooConfig is an autogenerated conversion function.
+func Convert_v1beta1_FooConfig_To_demo_FooConfig(in *FooConfig, out *demo.FooConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooConfig_To_demo_FooConfig(in, out, s)
+}
+
+func autoConvert_demo_FooConfig_To_v1beta1_FooConfig(in *demo.FooConfig, out *FooConfig, s conversion.Scope) error {
+	out.Msg = in.Msg
+	out.Msg1 = in.Msg1
+	return nil
+}
+
+// Convert_demo_FooConfig_To_v1beta1_FooConfig is an autogenerated conversion function.
+func Convert_demo_FooConfig_To_v1beta1_FooConfig(in *demo.FooConfig, out *FooConfig, s conversion.Scope) error {
+	return autoConvert_demo_FooConfig_To_v1beta1_FooConfig(in, out, s)
+}
+
+func autoConvert_v1beta1_FooDefaults_To_demo_FooDefaults(in *FooDefaults, out *demo.FooDefaults, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_FooDefaultsSpec_To_demo_FooDefaultsSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_FooDefaults_To_demo_FooDefaults is an autogenerated conversion function.
+func Convert_v1beta1_FooDefaults_To_demo_FooDefaults(in *FooDefaults, out *demo.FooDefaults, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooDefaults_To_demo_FooDefaults(in, out, s)
+}
+
+func autoConvert_demo_FooDefaults_To_v1beta1_FooDefaults(in *demo.FooDefaults, out *FooDefaults, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_demo_FooDefaultsSpec_To_v1beta1_FooDefaultsSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_demo_FooDefaults_To_v1beta1_FooDefaults is an autogenerated conversion function.
+func Convert_demo_FooDefaults_To_v1beta1_FooDefaults(in *demo.FooDefaults, out *FooDefaults, s conversion.Scope) error {
+	return autoConvert_demo_FooDefaults_To_v1beta1_FooDefaults(in, out, s)
+}
+
+func autoConvert_v1beta1_FooDefaultsList_To_demo_FooDefaultsList(in *FooDefaultsList, out *demo.FooDefaultsList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]demo.FooDefaults)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1beta1_FooDefaultsList_To_demo_FooDefaultsList is an autogenerated conversion function.
+func Convert_v1beta1_FooDefaultsList_To_demo_FooDefaultsList(in *FooDefaultsList, out *demo.FooDefaultsList, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooDefaultsList_To_demo_FooDefaultsList(in, out, s)
+}
+
+func autoConvert_demo_FooDefaultsList_To_v1beta1_FooDefaultsList(in *demo.FooDefaultsList, out *FooDefaultsList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]FooDefaults)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_demo_FooDefaultsList_To_v1beta1_FooDefaultsList is an autogenerated conversion function.
+func Convert_demo_FooDefaultsList_To_v1beta1_FooDefaultsList(in *demo.FooDefaultsList, out *FooDefaultsList, s conversion.Scope) error {
+	return autoConvert_demo_FooDefaultsList_To_v1beta1_FooDefaultsList(in, out, s)
+}
+
+func autoConvert_v1beta1_FooDefaultsSpec_To_demo_FooDefaultsSpec(in *FooDefaultsSpec, out *demo.FooDefaultsSpec, s conversion.Scope) error {
+	out.Selector = (*v1.LabelSelector)(unsafe.Pointer(in.Selector))
+	out.Priority = in.Priority
+	out.ImageRegistry = in.ImageRegistry
+	out.ImageTag = in.ImageTag
+	out.Msg = in.Msg
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	return nil
+}
+
+// Convert_v1beta1_FooDefaultsSpec_To_demo_FooDefaultsSpec is an autogenerated conversion function.
+func Convert_v1beta1_FooDefaultsSpec_To_demo_FooDefaultsSpec(in *FooDefaultsSpec, out *demo.FooDefaultsSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooDefaultsSpec_To_demo_FooDefaultsSpec(in, out, s)
+}
+
+func autoConvert_demo_FooDefaultsSpec_To_v1beta1_FooDefaultsSpec(in *demo.FooDefaultsSpec, out *FooDefaultsSpec, s conversion.Scope) error {
+	out.Selector = (*v1.LabelSelector)(unsafe.Pointer(in.Selector))
+	out.Priority = in.Priority
+	out.ImageRegistry = in.ImageRegistry
+	out.ImageTag = in.ImageTag
+	out.Msg = in.Msg
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	return nil
+}
+
+// Convert_demo_FooDefaultsSpec_To_v1beta1_FooDefaultsSpec is an autogenerated conversion function.
+func Convert_demo_FooDefaultsSpec_To_v1beta1_FooDefaultsSpec(in *demo.FooDefaultsSpec, out *FooDefaultsSpec, s conversion.Scope) error {
+	return autoConvert_demo_FooDefaultsSpec_To_v1beta1_FooDefaultsSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_FooList_To_demo_FooList(in *FooList, out *demo.FooList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]demo.Foo, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_Foo_To_demo_Foo(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1beta1_FooList_To_demo_FooList is an autogenerated conversion function.
+func Convert_v1beta1_FooList_To_demo_FooList(in *FooList, out *demo.FooList, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooList_To_demo_FooList(in, out, s)
+}
+
+func autoConvert_demo_FooList_To_v1beta1_FooList(in *demo.FooList, out *FooList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Foo, len(*in))
+		for i := range *in {
+			if err := Convert_demo_Foo_To_v1beta1_Foo(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_demo_FooList_To_v1beta1_FooList is an autogenerated conversion function.
+func Convert_demo_FooList_To_v1beta1_FooList(in *demo.FooList, out *FooList, s conversion.Scope) error {
+	return autoConvert_demo_FooList_To_v1beta1_FooList(in, out, s)
+}
+
+func autoConvert_v1beta1_FooRendering_To_demo_FooRendering(in *FooRendering, out *demo.FooRendering, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_FooSpec_To_demo_FooSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	out.Objects = *(*[]runtime.RawExtension)(unsafe.Pointer(&in.Objects))
+	return nil
+}
+
+// Convert_v1beta1_FooRendering_To_demo_FooRendering is an autogenerated conversion function.
+func Convert_v1beta1_FooRendering_To_demo_FooRendering(in *FooRendering, out *demo.FooRendering, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooRendering_To_demo_FooRendering(in, out, s)
+}
+
+func autoConvert_demo_FooRendering_To_v1beta1_FooRendering(in *demo.FooRendering, out *FooRendering, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_demo_FooSpec_To_v1beta1_FooSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	out.Objects = *(*[]runtime.RawExtension)(unsafe.Pointer(&in.Objects))
+	return nil
+}
+
+// Convert_demo_FooRendering_To_v1beta1_FooRendering is an autogenerated conversion function.
+func Convert_demo_FooRendering_To_v1beta1_FooRendering(in *demo.FooRendering, out *FooRendering, s conversion.Scope) error {
+	return autoConvert_demo_FooRendering_To_v1beta1_FooRendering(in, out, s)
+}
+
+func autoConvert_v1beta1_FooReview_To_demo_FooReview(in *FooReview, out *demo.FooReview, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_FooReviewSpec_To_demo_FooReviewSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_FooReviewStatus_To_demo_FooReviewStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_FooReview_To_demo_FooReview is an autogenerated conversion function.
+func Convert_v1beta1_FooReview_To_demo_FooReview(in *FooReview, out *demo.FooReview, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooReview_To_demo_FooReview(in, out, s)
+}
+
+func autoConvert_demo_FooReview_To_v1beta1_FooReview(in *demo.FooReview, out *FooReview, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_demo_FooReviewSpec_To_v1beta1_FooReviewSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_demo_FooReviewStatus_To_v1beta1_FooReviewStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_demo_FooReview_To_v1beta1_FooReview is an autogenerated conversion function.
+func Convert_demo_FooReview_To_v1beta1_FooReview(in *demo.FooReview, out *FooReview, s conversion.Scope) error {
+	return autoConvert_demo_FooReview_To_v1beta1_FooReview(in, out, s)
+}
+
+func autoConvert_v1beta1_FooReviewSpec_To_demo_FooReviewSpec(in *FooReviewSpec, out *demo.FooReviewSpec, s conversion.Scope) error {
+	if err := Convert_v1beta1_Foo_To_demo_Foo(&in.Foo, &out.Foo, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_FooReviewSpec_To_demo_FooReviewSpec is an autogenerated conversion function.
+func Convert_v1beta1_FooReviewSpec_To_demo_FooReviewSpec(in *FooReviewSpec, out *demo.FooReviewSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooReviewSpec_To_demo_FooReviewSpec(in, out, s)
+}
+
+func autoConvert_demo_FooReviewSpec_To_v1beta1_FooReviewSpec(in *demo.FooReviewSpec, out *FooReviewSpec, s conversion.Scope) error {
+	if err := Convert_demo_Foo_To_v1beta1_Foo(&in.Foo, &out.Foo, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_demo_FooReviewSpec_To_v1beta1_FooReviewSpec is an autogenerated conversion function.
+func Convert_demo_FooReviewSpec_To_v1beta1_FooReviewSpec(in *demo.FooReviewSpec, out *FooReviewSpec, s conversion.Scope) error {
+	return autoConvert_demo_FooReviewSpec_To_v1beta1_FooReviewSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_FooReviewStatus_To_demo_FooReviewStatus(in *FooReviewStatus, out *demo.FooReviewStatus, s conversion.Scope) error {
+	out.Allowed = in.Allowed
+	if err := Convert_v1beta1_Foo_To_demo_Foo(&in.Foo, &out.Foo, s); err != nil {
+		return err
+	}
+	out.Errors = *(*[]v1.StatusCause)(unsafe.Pointer(&in.Errors))
+	out.Warnings = *(*[]string)(unsafe.Pointer(&in.Warnings))
+	return nil
+}
+
+// Convert_v1beta1_FooReviewStatus_To_demo_FooReviewStatus is an autogenerated conversion function.
+func Convert_v1beta1_FooReviewStatus_To_demo_FooReviewStatus(in *FooReviewStatus, out *demo.FooReviewStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooReviewStatus_To_demo_FooReviewStatus(in, out, s)
+}
+
+func autoConvert_demo_FooReviewStatus_To_v1beta1_FooReviewStatus(in *demo.FooReviewStatus, out *FooReviewStatus, s conversion.Scope) error {
+	out.Allowed = in.Allowed
+	if err := Convert_demo_Foo_To_v1beta1_Foo(&in.Foo, &out.Foo, s); err != nil {
+		return err
+	}
+	out.Errors = *(*[]v1.StatusCause)(unsafe.Pointer(&in.Errors))
+	out.Warnings = *(*[]string)(unsafe.Pointer(&in.Warnings))
+	return nil
+}
+
+// Convert_demo_FooReviewStatus_To_v1beta1_FooReviewStatus is an autogenerated conversion function.
+func Convert_demo_FooReviewStatus_To_v1beta1_FooReviewStatus(in *demo.FooReviewStatus, out *FooReviewStatus, s conversion.Scope) error {
+	return autoConvert_demo_FooReviewStatus_To_v1beta1_FooReviewStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_FooRevision_To_demo_FooRevision(in *FooRevision, out *demo.FooRevision, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Revision = in.Revision
+	if err := Convert_v1beta1_FooSpec_To_demo_FooSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_FooRevision_To_demo_FooRevision is an autogenerated conversion function.
+func Convert_v1beta1_FooRevision_To_demo_FooRevision(in *FooRevision, out *demo.FooRevision, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooRevision_To_demo_FooRevision(in, out, s)
+}
+
+func autoConvert_demo_FooRevision_To_v1beta1_FooRevision(in *demo.FooRevision, out *FooRevision, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Revision = in.Revision
+	if err := Convert_demo_FooSpec_To_v1beta1_FooSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_demo_FooRevision_To_v1beta1_FooRevision is an autogenerated conversion function.
+func Convert_demo_FooRevision_To_v1beta1_FooRevision(in *demo.FooRevision, out *FooRevision, s conversion.Scope) error {
+	return autoConvert_demo_FooRevision_To_v1beta1_FooRevision(in, out, s)
+}
+
+func autoConvert_v1beta1_FooRevisionList_To_demo_FooRevisionList(in *FooRevisionList, out *demo.FooRevisionList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]demo.FooRevision, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_FooRevision_To_demo_FooRevision(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1beta1_FooRevisionList_To_demo_FooRevisionList is an autogenerated conversion function.
+func Convert_v1beta1_FooRevisionList_To_demo_FooRevisionList(in *FooRevisionList, out *demo.FooRevisionList, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooRevisionList_To_demo_FooRevisionList(in, out, s)
+}
+
+func autoConvert_demo_FooRevisionList_To_v1beta1_FooRevisionList(in *demo.FooRevisionList, out *FooRevisionList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FooRevision, len(*in))
+		for i := range *in {
+			if err := Convert_demo_FooRevision_To_v1beta1_FooRevision(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_demo_FooRevisionList_To_v1beta1_FooRevisionList is an autogenerated conversion function.
+func Convert_demo_FooRevisionList_To_v1beta1_FooRevisionList(in *demo.FooRevisionList, out *FooRevisionList, s conversion.Scope) error {
+	return autoConvert_demo_FooRevisionList_To_v1beta1_FooRevisionList(in, out, s)
+}
+
+func autoConvert_v1beta1_FooRollback_To_demo_FooRollback(in *FooRollback, out *demo.FooRollback, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Revision = in.Revision
+	return nil
+}
+
+// Convert_v1beta1_FooRollback_To_demo_FooRollback is an autogenerated conversion function.
+func Convert_v1beta1_FooRollback_To_demo_FooRollback(in *FooRollback, out *demo.FooRollback, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooRollback_To_demo_FooRollback(in, out, s)
+}
+
+func autoConvert_demo_FooRollback_To_v1beta1_FooRollback(in *demo.FooRollback, out *FooRollback, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Revision = in.Revision
+	return nil
+}
+
+// Convert_demo_FooRollback_To_v1beta1_FooRollback is an autogenerated conversion function.
+func Convert_demo_FooRollback_To_v1beta1_FooRollback(in *demo.FooRollback, out *FooRollback, s conversion.Scope) error {
+	return autoConvert_demo_FooRollback_To_v1beta1_FooRollback(in, out, s)
+}
+
+func autoConvert_v1beta1_FooSpec_To_demo_FooSpec(in *FooSpec, out *demo.FooSpec, s conversion.Scope) error {
+	out.Image = in.Image
+	if err := Convert_v1beta1_FooConfig_To_demo_FooConfig(&in.Config, &out.Config, s); err != nil {
+		return err
+	}
+	out.ConfigName = in.ConfigName
+	if err := v1.Convert_Pointer_int32_To_int32(&in.Replicas, &out.Replicas, s); err != nil {
+		return err
+	}
+	out.TerminationGracePeriodSeconds = (*int64)(unsafe.Pointer(in.TerminationGracePeriodSeconds))
+	return nil
+}
+
+// Convert_v1beta1_FooSpec_To_demo_FooSpec is an autogenerated conversion function.
+func Convert_v1beta1_FooSpec_To_demo_FooSpec(in *FooSpec, out *demo.FooSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooSpec_To_demo_FooSpec(in, out, s)
+}
+
+func autoConvert_demo_FooSpec_To_v1beta1_FooSpec(in *demo.FooSpec, out *FooSpec, s conversion.Scope) error {
+	out.Image = in.Image
+	if err := Convert_demo_FooConfig_To_v1beta1_FooConfig(&in.Config, &out.Config, s); err != nil {
+		return err
+	}
+	out.ConfigName = in.ConfigName
+	if err := v1.Convert_int32_To_Pointer_int32(&in.Replicas, &out.Replicas, s); err != nil {
+		return err
+	}
+	out.TerminationGracePeriodSeconds = (*int64)(unsafe.Pointer(in.TerminationGracePeriodSeconds))
+	return nil
+}
+
+// Convert_demo_FooSpec_To_v1beta1_FooSpec is an autogenerated conversion function.
+func Convert_demo_FooSpec_To_v1beta1_FooSpec(in *demo.FooSpec, out *FooSpec, s conversion.Scope) error {
+	return autoConvert_demo_FooSpec_To_v1beta1_FooSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_FooStatus_To_demo_FooStatus(in *FooStatus, out *demo.FooStatus, s conversion.Scope) error {
+	out.Phase = demo.FooPhase(in.Phase)
+	out.Conditions = *(*[]demo.FooCondition)(unsafe.Pointer(&in.Conditions))
+	out.Replicas = in.Replicas
+	out.Selector = in.Selector
+	return nil
+}
+
+// Convert_v1beta1_FooStatus_To_demo_FooStatus is an autogenerated conversion function.
+func Convert_v1beta1_FooStatus_To_demo_FooStatus(in *FooStatus, out *demo.FooStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooStatus_To_demo_FooStatus(in, out, s)
+}
+
+func autoConvert_demo_FooStatus_To_v1beta1_FooStatus(in *demo.FooStatus, out *FooStatus, s conversion.Scope) error {
+	out.Phase = FooPhase(in.Phase)
+	out.Conditions = *(*[]FooCondition)(unsafe.Pointer(&in.Conditions))
+	out.Replicas = in.Replicas
+	out.Selector = in.Selector
+	return nil
+}
+
+// Convert_demo_FooStatus_To_v1beta1_FooStatus is an autogenerated conversion function.
+func Convert_demo_FooStatus_To_v1beta1_FooStatus(in *demo.FooStatus, out *FooStatus, s conversion.Scope) error {
+	return autoConvert_demo_FooStatus_To_v1beta1_FooStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_FooSummary_To_demo_FooSummary(in *FooSummary, out *demo.FooSummary, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_FooSummaryStatus_To_demo_FooSummaryStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_FooSummary_To_demo_FooSummary is an autogenerated conversion function.
+func Convert_v1beta1_FooSummary_To_demo_FooSummary(in *FooSummary, out *demo.FooSummary, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooSummary_To_demo_FooSummary(in, out, s)
+}
+
+func autoConvert_demo_FooSummary_To_v1beta1_FooSummary(in *demo.FooSummary, out *FooSummary, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_demo_FooSummaryStatus_To_v1beta1_FooSummaryStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_demo_FooSummary_To_v1beta1_FooSummary is an autogenerated conversion function.
+func Convert_demo_FooSummary_To_v1beta1_FooSummary(in *demo.FooSummary, out *FooSummary, s conversion.Scope) error {
+	return autoConvert_demo_FooSummary_To_v1beta1_FooSummary(in, out, s)
+}
+
+func autoConvert_v1beta1_FooSummaryList_To_demo_FooSummaryList(in *FooSummaryList, out *demo.FooSummaryList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]demo.FooSummary)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1beta1_FooSummaryList_To_demo_FooSummaryList is an autogenerated conversion function.
+func Convert_v1beta1_FooSummaryList_To_demo_FooSummaryList(in *FooSummaryList, out *demo.FooSummaryList, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooSummaryList_To_demo_FooSummaryList(in, out, s)
+}
+
+func autoConvert_demo_FooSummaryList_To_v1beta1_FooSummaryList(in *demo.FooSummaryList, out *FooSummaryList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]FooSummary)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_demo_FooSummaryList_To_v1beta1_FooSummaryList is an autogenerated conversion function.
+func Convert_demo_FooSummaryList_To_v1beta1_FooSummaryList(in *demo.FooSummaryList, out *FooSummaryList, s conversion.Scope) error {
+	return autoConvert_demo_FooSummaryList_To_v1beta1_FooSummaryList(in, out, s)
+}
+
+func autoConvert_v1beta1_FooSummaryStatus_To_demo_FooSummaryStatus(in *FooSummaryStatus, out *demo.FooSummaryStatus, s conversion.Scope) error {
+	out.Foos = in.Foos
+	out.Phases = *(*map[demo.FooPhase]int32)(unsafe.Pointer(&in.Phases))
+	out.Conditions = *(*[]demo.FooConditionSummary)(unsafe.Pointer(&in.Conditions))
+	out.Images = *(*map[string]int32)(unsafe.Pointer(&in.Images))
+	return nil
+}
+
+// Convert_v1beta1_FooSummaryStatus_To_demo_FooSummaryStatus is an autogenerated conversion function.
+func Convert_v1beta1_FooSummaryStatus_To_demo_FooSummaryStatus(in *FooSummaryStatus, out *demo.FooSummaryStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_FooSummaryStatus_To_demo_FooSummaryStatus(in, out, s)
+}
+
+func autoConvert_demo_FooSummaryStatus_To_v1beta1_FooSummaryStatus(in *demo.FooSummaryStatus, out *FooSummaryStatus, s conversion.Scope) error {
+	out.Foos = in.Foos
+	out.Phases = *(*map[FooPhase]int32)(unsafe.Pointer(&in.Phases))
+	out.Conditions = *(*[]FooConditionSummary)(unsafe.Pointer(&in.Conditions))
+	out.Images = *(*map[string]int32)(unsafe.Pointer(&in.Images))
+	return nil
+}
+
+// Convert_demo_FooSummaryStatus_To_v1beta1_FooSummaryStatus is an autogenerated conversion function.
+func Convert_demo_FooSummaryStatus_To_v1beta1_FooSummaryStatus(in *demo.FooSummaryStatus, out *FooSummaryStatus, s conversion.Scope) error {
+	return autoConvert_demo_FooSummaryStatus_To_v1beta1_FooSummaryStatus(in, out, s)
+}
@@ -1,17 +1,41 @@
 package apiserver
 
 import (
+	"fmt"
 	"github.com/guodoliu/apiserver/pkg/apis/demo"
 	"github.com/guodoliu/apiserver/pkg/apis/demo/install"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"github.com/guodoliu/apiserver/pkg/features"
+	informers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions"
 	"github.com/guodoliu/apiserver/pkg/registry"
+	configstorage "github.com/guodoliu/apiserver/pkg/registry/demo/config"
 	foostorage "github.com/guodoliu/apiserver/pkg/registry/demo/foo"
+	foodefaultsstorage "github.com/guodoliu/apiserver/pkg/registry/demo/foodefaults"
+	fooreviewstorage "github.com/guodoliu/apiserver/pkg/registry/demo/fooreview"
+	foorevisionstorage "github.com/guodoliu/apiserver/pkg/registry/demo/foorevision"
+	foosummarystorage "github.com/guodoliu/apiserver/pkg/registry/demo/foosummary"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apiserver/pkg/registry/rest"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/healthz"
+	serverstorage "k8s.io/apiserver/pkg/server/storage"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	clientrest "k8s.io/client-go/rest"
+	autoscaling "k8s.io/client-go/scale/scheme"
+	autoscalingv1 "k8s.io/client-go/scale/scheme/autoscalingv1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"maps"
+	"net/http"
+	"reflect"
+	"sort"
 )
 
 var (
@@ -22,6 +46,9 @@ var (
 func init() {
 	install.Install(Scheme)
 	metav1.AddToGroupVersion(Scheme, schema.GroupVersion{Group: "", Version: "v1"})
+	// foos/scale serves autoscaling/v1 Scale
+	utilruntime.Must(autoscaling.AddToScheme(Scheme))
+	utilruntime.Must(autoscalingv1.AddToScheme(Scheme))
 
 	unversioned := schema.GroupVersion{Group: "", Version: "v1"}
 	Scheme.AddUnversionedTypes(unversioned,
@@ -33,9 +60,36 @@ func init() {
 	)
 }
 
+// DefaultAPIResourceConfigSource returns the demo versions and resources that
+// are served unless --runtime-config says otherwise.
+func DefaultAPIResourceConfigSource() *serverstorage.ResourceConfig {
+	ret := serverstorage.NewResourceConfig()
+	ret.EnableVersions(
+		v1beta1.SchemeGroupVersion,
+		v1alpha1.SchemeGroupVersion,
+	)
+	return ret
+}
+
 type ExtraConfig struct {
 	Rest              *clientrest.Config
 	EnableEtcdStorage bool
+
+	// DemoInformers is built on the loopback client. It is shared with the
+	// admission plugins and started by a post-start hook.
+	DemoInformers informers.SharedInformerFactory
+
+	// Controllers, if set, runs the demo controllers in this server
+	Controllers *ControllersConfig
+	// APIService, if set, registers the served versions with the aggregator
+	APIService *APIServiceConfig
+	// FooDefaultsAdmission tells whether the FooDefaults admission plugin
+	// applies FooDefaults to new foos
+	FooDefaultsAdmission bool
+	// FooRevisionHistoryLimit is the number of revisions kept per foo. Zero
+	// turns off the revision history and the foos/history and foos/rollback
+	// subresources.
+	FooRevisionHistoryLimit int
 }
 
 type Config struct {
@@ -67,20 +121,153 @@ func (cfg *Config) Complete() CompletedConfig {
 func (c completedConfig) New() (*DemoServer, error) {
 	genericServer, err := c.GenericConfig.New("demo-apiserver", genericapiserver.NewEmptyDelegate())
 	if err != nil {
+		return nil, err
 	}
 
 	s := &DemoServer{
 		GenericAPIServer: genericServer,
 	}
 
+	resourceConfig := c.GenericConfig.MergedResourceConfig
+	v1alpha1Foos := v1alpha1.SchemeGroupVersion.WithResource("foos")
+	v1beta1Foos := v1beta1.SchemeGroupVersion.WithResource("foos")
+
 	apiGroupInfo := genericapiserver.NewDefaultAPIGroupInfo(demo.GroupName, Scheme, metav1.ParameterCodec, Codec)
 
 	v1alpha1storage := map[string]rest.Storage{}
-	v1alpha1storage["foos"] = registry.RESTInPeace(foostorage.NewREST(Scheme, c.GenericConfig.RESTOptionsGetter))
-	apiGroupInfo.VersionedResourcesStorageMap["v1alpha1"] = v1alpha1storage
+	v1beta1storage := map[string]rest.Storage{}
+	// the finalizer is only added when this server runs the controllers that
+	// remove it, the server cannot tell whether a manager runs elsewhere
+	cleanupFinalizer := c.ExtraConfig.Controllers != nil
+	var configStorage *registry.REST
+	if utilfeature.DefaultFeatureGate.Enabled(features.ConfigResource) && resourceConfig.ResourceEnabled(v1alpha1.SchemeGroupVersion.WithResource("configs")) {
+		configStorage = registry.RESTInPeace(configstorage.NewREST(Scheme, c.GenericConfig.RESTOptionsGetter))
+		v1alpha1storage["configs"] = configStorage
+	}
+	if resourceConfig.ResourceEnabled(v1alpha1Foos) || resourceConfig.ResourceEnabled(v1beta1Foos) {
+		// all served versions share one store
+		fooStorage := map[string]rest.Storage{}
+		fooStorage["foos"] = registry.RESTInPeace(foostorage.NewREST(Scheme, c.GenericConfig.RESTOptionsGetter, cleanupFinalizer))
+		if utilfeature.DefaultFeatureGate.Enabled(features.FooStatusSubresource) {
+			fooStorage["foos/status"] = foostorage.NewStatusREST(Scheme, fooStorage["foos"].(*registry.REST))
+		}
+		if resourceConfig.ResourceEnabled(v1alpha1Foos) {
+			maps.Copy(v1alpha1storage, fooStorage)
+		}
+		if resourceConfig.ResourceEnabled(v1beta1Foos) {
+			maps.Copy(v1beta1storage, fooStorage)
+			v1beta1storage["foos/render"] = foostorage.NewRenderREST(Scheme, fooStorage["foos"].(*registry.REST), configStorage)
+			v1beta1storage["foos/scale"] = foostorage.NewScaleREST(fooStorage["foos"].(*registry.REST))
+			if resourceConfig.ResourceEnabled(v1beta1.SchemeGroupVersion.WithResource("foosummaries")) {
+				summaries := foosummarystorage.NewREST(fooStorage["foos"].(*registry.REST))
+				v1beta1storage["foosummaries"] = summaries
+				s.GenericAPIServer.AddPostStartHookOrDie("start-demo-foo-summaries", func(context genericapiserver.PostStartHookContext) error {
+					go summaries.Run(wait.ContextForChannel(context.StopCh))
+					return nil
+				})
+			}
+			if limit := c.ExtraConfig.FooRevisionHistoryLimit; limit > 0 {
+				revisions := registry.RESTInPeace(foorevisionstorage.NewREST(Scheme, c.GenericConfig.RESTOptionsGetter))
+				v1beta1storage["foos/history"], v1beta1storage["foos/rollback"] = foostorage.NewHistoryStorage(fooStorage["foos"].(*registry.REST), revisions, limit)
+			}
+		}
+	}
+	if resourceConfig.ResourceEnabled(v1beta1.SchemeGroupVersion.WithResource("foodefaults")) {
+		v1beta1storage["foodefaults"] = registry.RESTInPeace(foodefaultsstorage.NewREST(Scheme, c.GenericConfig.RESTOptionsGetter, c.ExtraConfig.FooDefaultsAdmission))
+	}
+	if resourceConfig.ResourceEnabled(v1beta1.SchemeGroupVersion.WithResource("fooreviews")) {
+		v1beta1storage["fooreviews"] = fooreviewstorage.NewREST(Scheme, c.GenericConfig.AdmissionControl, cleanupFinalizer)
+	}
+	if len(v1alpha1storage) > 0 {
+		apiGroupInfo.VersionedResourcesStorageMap[v1alpha1.SchemeGroupVersion.Version] = v1alpha1storage
+	}
+	if len(v1beta1storage) > 0 {
+		apiGroupInfo.VersionedResourcesStorageMap[v1beta1.SchemeGroupVersion.Version] = v1beta1storage
+	}
 
-	if err := s.GenericAPIServer.InstallAPIGroup(&apiGroupInfo); err != nil {
-		return nil, err
+	if len(apiGroupInfo.VersionedResourcesStorageMap) > 0 {
+		if err := s.GenericAPIServer.InstallAPIGroup(&apiGroupInfo); err != nil {
+			return nil, err
+		}
+		installDiscoveryDeprecationWarnings(s.GenericAPIServer, deprecationWarnings(apiGroupInfo.VersionedResourcesStorageMap))
+		// InstallAPIGroup publishes the versions in the aggregated discovery
+		// document at /apis as well; order them there like the APIServices
+		// order them in the backing cluster
+		if manager := s.GenericAPIServer.AggregatedDiscoveryGroupManager; manager != nil {
+			for version := range apiGroupInfo.VersionedResourcesStorageMap {
+				manager.SetGroupVersionPriority(metav1.GroupVersion{Group: demo.GroupName, Version: version}, groupPriorityMinimum, int(versionPriorities[version]))
+			}
+		}
+	} else {
+		klog.Warningf("Skipping API group %s because all its versions are disabled.", demo.GroupName)
+	}
+
+	if informerFactory := c.ExtraConfig.DemoInformers; informerFactory != nil {
+		// request the foo informer so that readiness waits for the foo watch
+		// cache. The factory only reports informers once they are started, so
+		// the check also needs to know which ones to wait for.
+		required := map[reflect.Type]cache.InformerSynced{}
+		switch {
+		case resourceConfig.ResourceEnabled(v1beta1Foos):
+			required[reflect.TypeOf(&v1beta1.Foo{})] = informerFactory.Demo().V1beta1().Foos().Informer().HasSynced
+		case resourceConfig.ResourceEnabled(v1alpha1Foos):
+			required[reflect.TypeOf(&v1alpha1.Foo{})] = informerFactory.Demo().V1alpha1().Foos().Informer().HasSynced
+		}
+
+		s.GenericAPIServer.AddPostStartHookOrDie("start-demo-server-informers", func(context genericapiserver.PostStartHookContext) error {
+			informerFactory.Start(context.StopCh)
+			return nil
+		})
+		if err := s.GenericAPIServer.AddReadyzChecks(informerSyncCheck("demo-informer-sync", informerFactory, required)); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.ExtraConfig.APIService != nil {
+		versions := sets.List(sets.KeySet(apiGroupInfo.VersionedResourcesStorageMap))
+		if err := c.installAPIServiceRegistration(s, versions); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.ExtraConfig.Controllers != nil {
+		if err := c.installControllers(s); err != nil {
+			return nil, err
+		}
 	}
 	return s, nil
 }
+
+type cacheSyncWaiter interface {
+	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
+}
+
+// informerSyncCheck reports which informers of the factory have not synced yet.
+// Unlike healthz.NewInformerSyncHealthz it fails until the caches are filled,
+// not only until the informers are started. The required informers are
+// checked even while the factory has not started them.
+func informerSyncCheck(name string, waiter cacheSyncWaiter, required map[reflect.Type]cache.InformerSynced) healthz.HealthChecker {
+	return healthz.NamedCheck(name, func(_ *http.Request) error {
+		stopCh := make(chan struct{})
+		// closing stopCh makes WaitForCacheSync report the current state without blocking
+		close(stopCh)
+
+		status := waiter.WaitForCacheSync(stopCh)
+		for informerType, synced := range required {
+			if _, started := status[informerType]; !started {
+				status[informerType] = synced()
+			}
+		}
+		var unsynced []string
+		for informerType, synced := range status {
+			if !synced {
+				unsynced = append(unsynced, informerType.String())
+			}
+		}
+		if len(unsynced) > 0 {
+			sort.Strings(unsynced)
+			return fmt.Errorf("%d informers not synced yet: %v", len(unsynced), unsynced)
+		}
+		return nil
+	})
+}
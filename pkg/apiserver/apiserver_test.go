@@ -0,0 +1,32 @@
+package apiserver
+
+import (
+	"context"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/fake"
+	informers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions"
+	"k8s.io/client-go/tools/cache"
+	"reflect"
+	"testing"
+)
+
+func TestInformerSyncCheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	required := map[reflect.Type]cache.InformerSynced{
+		reflect.TypeOf(&v1beta1.Foo{}): factory.Demo().V1beta1().Foos().Informer().HasSynced,
+	}
+	check := informerSyncCheck("demo-informer-sync", factory, required)
+
+	if err := check.Check(nil); err == nil {
+		t.Fatal("expected the check to fail before the factory is started")
+	}
+
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+	if err := check.Check(nil); err != nil {
+		t.Fatalf("expected the check to pass once the informers synced: %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"slices"
+	"time"
+)
+
+var apiServiceResource = schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}
+
+// groupPriorityMinimum orders the demo group among all groups of the backing
+// cluster
+const groupPriorityMinimum = 100
+
+// versionPriorities orders the demo versions for the aggregator; higher
+// values are preferred
+var versionPriorities = map[string]int64{
+	v1beta1.SchemeGroupVersion.Version:  15,
+	v1alpha1.SchemeGroupVersion.Version: 10,
+}
+
+const unregisterAPIServicesTimeout = 10 * time.Second
+
+// APIServiceConfig registers the served demo versions with the aggregator of
+// the backing cluster.
+type APIServiceConfig struct {
+	// DynamicClient talks to the backing cluster
+	DynamicClient dynamic.Interface
+	// CABundle is the CA the aggregator uses to verify this server. The
+	// APIServices are updated whenever it changes.
+	CABundle dynamiccertificates.CAContentProvider
+
+	ServiceNamespace string
+	ServiceName      string
+	ServicePort      int32
+
+	// UnregisterOnShutdown deletes the APIServices when the server stops
+	UnregisterOnShutdown bool
+}
+
+// apiServiceRegistrar creates or updates an APIService per served version.
+// Its queue only ever holds one key; every change of the CA bundle queues it.
+type apiServiceRegistrar struct {
+	config   *APIServiceConfig
+	versions []string
+	queue    workqueue.RateLimitingInterface
+}
+
+var _ dynamiccertificates.Listener = &apiServiceRegistrar{}
+
+const apiServiceQueueKey = "apiservices"
+
+func (r *apiServiceRegistrar) Enqueue() {
+	r.queue.Add(apiServiceQueueKey)
+}
+
+func (r *apiServiceRegistrar) run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+	defer r.queue.ShutDown()
+
+	if runner, ok := r.config.CABundle.(dynamiccertificates.ControllerRunner); ok {
+		go runner.Run(ctx, 1)
+	}
+	if notifier, ok := r.config.CABundle.(dynamiccertificates.Notifier); ok {
+		notifier.AddListener(r)
+	}
+	r.Enqueue()
+
+	go wait.UntilWithContext(ctx, r.runWorker, time.Second)
+	<-ctx.Done()
+}
+
+func (r *apiServiceRegistrar) runWorker(ctx context.Context) {
+	for r.processNextWorkItem(ctx) {
+	}
+}
+
+func (r *apiServiceRegistrar) processNextWorkItem(ctx context.Context) bool {
+	key, quit := r.queue.Get()
+	if quit {
+		return false
+	}
+	defer r.queue.Done(key)
+
+	if err := r.sync(ctx); err != nil {
+		utilruntime.HandleError(fmt.Errorf("registering demo APIServices failed: %v", err))
+		r.queue.AddRateLimited(key)
+		return true
+	}
+	r.queue.Forget(key)
+	return true
+}
+
+// sync creates or updates the APIServices of the served versions and deletes
+// those of the versions this server no longer serves.
+func (r *apiServiceRegistrar) sync(ctx context.Context) error {
+	client := r.config.DynamicClient.Resource(apiServiceResource)
+	for _, version := range r.versions {
+		desired := r.newAPIService(version)
+		existing, err := client.Get(ctx, desired.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			if _, err := client.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+				return err
+			}
+			klog.InfoS("Registered APIService", "name", desired.GetName())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if equality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) {
+			continue
+		}
+		updated := existing.DeepCopy()
+		updated.Object["spec"] = desired.Object["spec"]
+		if _, err := client.Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		klog.InfoS("Updated APIService", "name", desired.GetName())
+	}
+
+	for version := range versionPriorities {
+		if slices.Contains(r.versions, version) {
+			continue
+		}
+		existing, err := client.Get(ctx, apiServiceName(version), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		// leave alone what points at another server
+		namespace, _, _ := unstructured.NestedString(existing.Object, "spec", "service", "namespace")
+		name, _, _ := unstructured.NestedString(existing.Object, "spec", "service", "name")
+		if namespace != r.config.ServiceNamespace || name != r.config.ServiceName {
+			continue
+		}
+		err = client.Delete(ctx, existing.GetName(), metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: ptr.To(existing.GetUID())}})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		klog.InfoS("Unregistered APIService of a version that is no longer served", "name", existing.GetName())
+	}
+	return nil
+}
+
+func (r *apiServiceRegistrar) unregister(ctx context.Context) error {
+	client := r.config.DynamicClient.Resource(apiServiceResource)
+	for _, version := range r.versions {
+		name := apiServiceName(version)
+		if err := client.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		klog.InfoS("Unregistered APIService", "name", name)
+	}
+	return nil
+}
+
+func (r *apiServiceRegistrar) newAPIService(version string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiServiceResource.GroupVersion().String(),
+		"kind":       "APIService",
+		"metadata": map[string]interface{}{
+			"name": apiServiceName(version),
+		},
+		"spec": map[string]interface{}{
+			"group":                demo.GroupName,
+			"version":              version,
+			"groupPriorityMinimum": int64(groupPriorityMinimum),
+			"versionPriority":      versionPriorities[version],
+			"caBundle":             base64.StdEncoding.EncodeToString(r.config.CABundle.CurrentCABundleContent()),
+			"service": map[string]interface{}{
+				"namespace": r.config.ServiceNamespace,
+				"name":      r.config.ServiceName,
+				"port":      int64(r.config.ServicePort),
+			},
+		},
+	}}
+}
+
+func apiServiceName(version string) string {
+	return version + "." + demo.GroupName
+}
+
+// installAPIServiceRegistration registers the given versions from a post-start
+// hook and, if asked to, removes them again from a pre-shutdown hook.
+func (c completedConfig) installAPIServiceRegistration(s *DemoServer, versions []string) error {
+	r := &apiServiceRegistrar{
+		config:   c.ExtraConfig.APIService,
+		versions: versions,
+		queue:    workqueue.NewRateLimitingQueueWithConfig(workqueue.DefaultControllerRateLimiter(), workqueue.RateLimitingQueueConfig{Name: "demo_apiservice_registration"}),
+	}
+	if err := s.GenericAPIServer.AddPostStartHook("demo-apiservice-registration", func(hookContext genericapiserver.PostStartHookContext) error {
+		go r.run(wait.ContextForChannel(hookContext.StopCh))
+		return nil
+	}); err != nil {
+		return err
+	}
+	if !r.config.UnregisterOnShutdown {
+		return nil
+	}
+	return s.GenericAPIServer.AddPreShutdownHook("demo-apiservice-unregistration", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), unregisterAPIServicesTimeout)
+		defer cancel()
+		return r.unregister(ctx)
+	})
+}
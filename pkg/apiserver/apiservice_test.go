@@ -0,0 +1,145 @@
+package apiserver
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"slices"
+	"testing"
+)
+
+// staticCA hands out a fixed caBundle without parsing it.
+type staticCA []byte
+
+func (ca staticCA) Name() string                              { return "test-ca" }
+func (ca staticCA) CurrentCABundleContent() []byte            { return ca }
+func (ca staticCA) VerifyOptions() (x509.VerifyOptions, bool) { return x509.VerifyOptions{}, false }
+func (ca staticCA) AddListener(dynamiccertificates.Listener)  {}
+
+var _ dynamiccertificates.CAContentProvider = staticCA(nil)
+
+func newTestRegistrar(ca string, versions []string, objects ...runtime.Object) (*apiServiceRegistrar, *dynamicfake.FakeDynamicClient) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{apiServiceResource: "APIServiceList"}, objects...)
+	return &apiServiceRegistrar{
+		config: &APIServiceConfig{
+			DynamicClient:    client,
+			CABundle:         staticCA(ca),
+			ServiceNamespace: "demo",
+			ServiceName:      "apiserver",
+			ServicePort:      443,
+		},
+		versions: versions,
+	}, client
+}
+
+// registeredAPIService returns the APIService of version as a registrar
+// with the given CA and Service name writes it.
+func registeredAPIService(version, ca, serviceName string) *unstructured.Unstructured {
+	r, _ := newTestRegistrar(ca, nil)
+	r.config.ServiceName = serviceName
+	apiService := r.newAPIService(version)
+	apiService.SetUID(types.UID("uid-" + version))
+	return apiService
+}
+
+// apiServiceActions lists the verb and name of the writes, like
+// "update v1beta1.demo.k8s.io".
+func apiServiceActions(actions []k8stesting.Action) []string {
+	var got []string
+	for _, action := range actions {
+		switch action := action.(type) {
+		case k8stesting.DeleteAction:
+			got = append(got, "delete "+action.GetName())
+		case k8stesting.CreateAction:
+			// updates are create actions too
+			got = append(got, action.GetVerb()+" "+action.GetObject().(*unstructured.Unstructured).GetName())
+		}
+	}
+	return got
+}
+
+func TestAPIServiceSync(t *testing.T) {
+	both := []string{"v1alpha1", "v1beta1"}
+	tests := []struct {
+		name     string
+		versions []string
+		existing []runtime.Object
+		want     []string
+	}{
+		{
+			name:     "created",
+			versions: both,
+			want:     []string{"create v1alpha1.demo.k8s.io", "create v1beta1.demo.k8s.io"},
+		},
+		{
+			name:     "unchanged",
+			versions: both,
+			existing: []runtime.Object{registeredAPIService("v1alpha1", "ca", "apiserver"), registeredAPIService("v1beta1", "ca", "apiserver")},
+		},
+		{
+			name:     "CA rotated",
+			versions: both,
+			existing: []runtime.Object{registeredAPIService("v1alpha1", "old-ca", "apiserver"), registeredAPIService("v1beta1", "ca", "apiserver")},
+			want:     []string{"update v1alpha1.demo.k8s.io"},
+		},
+		{
+			name:     "version no longer served",
+			versions: []string{"v1beta1"},
+			existing: []runtime.Object{registeredAPIService("v1alpha1", "ca", "apiserver"), registeredAPIService("v1beta1", "ca", "apiserver")},
+			want:     []string{"delete v1alpha1.demo.k8s.io"},
+		},
+		{
+			name:     "version of another server",
+			versions: []string{"v1beta1"},
+			existing: []runtime.Object{registeredAPIService("v1alpha1", "ca", "other-apiserver")},
+			want:     []string{"create v1beta1.demo.k8s.io"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, client := newTestRegistrar("ca", tc.versions, tc.existing...)
+			if err := r.sync(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			got := apiServiceActions(client.Actions())
+			slices.Sort(got)
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+
+			for _, version := range tc.versions {
+				apiService, err := client.Resource(apiServiceResource).Get(context.Background(), apiServiceName(version), metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				caBundle, _, _ := unstructured.NestedString(apiService.Object, "spec", "caBundle")
+				if caBundle != base64.StdEncoding.EncodeToString([]byte("ca")) {
+					t.Errorf("expected %s to carry the current CA, got %q", apiService.GetName(), caBundle)
+				}
+			}
+		})
+	}
+}
+
+func TestAPIServiceUnregister(t *testing.T) {
+	r, client := newTestRegistrar("ca", []string{"v1beta1"}, registeredAPIService("v1beta1", "ca", "apiserver"))
+	if err := r.unregister(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// unregistering twice is fine
+	if err := r.unregister(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := apiServiceActions(client.Actions()); !slices.Equal(got, []string{"delete v1beta1.demo.k8s.io", "delete v1beta1.demo.k8s.io"}) {
+		t.Errorf("expected the APIService to be deleted, got %v", got)
+	}
+}
@@ -0,0 +1,137 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	foocontroller "github.com/guodoliu/apiserver/pkg/controller/foo"
+	"github.com/guodoliu/apiserver/pkg/features"
+	clientset "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned"
+	demov1alpha1informers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions/demo/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	componentbaseconfig "k8s.io/component-base/config"
+	"k8s.io/klog/v2"
+	"os"
+	"time"
+)
+
+// ControllersConfig runs the demo controllers inside the server, in place of
+// the separate manager binary, which uses it too.
+type ControllersConfig struct {
+	// KubeClient talks to the backing cluster, where the controllers create
+	// their objects and hold the leader election lock
+	KubeClient         kubernetes.Interface
+	ConcurrentFooSyncs int
+	LeaderElection     componentbaseconfig.LeaderElectionConfiguration
+}
+
+// leaderElectionHealthzTimeout is how long a leader may fail to renew its
+// lease before the leaderElection healthz check fails.
+const leaderElectionHealthzTimeout = 20 * time.Second
+
+// installControllers starts the controllers from a post-start hook. With
+// leader election only one server replica runs them at a time. They work on
+// v1beta1 Foos, or on v1alpha1 Foos if only those are served.
+func (c completedConfig) installControllers(s *DemoServer) error {
+	cfg := c.ExtraConfig.Controllers
+	if c.ExtraConfig.DemoInformers == nil {
+		return fmt.Errorf("embedded controllers need the demo informers")
+	}
+	resourceConfig := c.GenericConfig.MergedResourceConfig
+	// the informers are registered before the factory is started
+	var newFoos func(clientset.Interface) foocontroller.Foos
+	switch {
+	case resourceConfig.ResourceEnabled(v1beta1.SchemeGroupVersion.WithResource("foos")):
+		fooInformer := c.ExtraConfig.DemoInformers.Demo().V1beta1().Foos()
+		fooInformer.Informer()
+		newFoos = func(client clientset.Interface) foocontroller.Foos {
+			return foocontroller.NewV1beta1Foos(client, fooInformer)
+		}
+	case resourceConfig.ResourceEnabled(v1alpha1.SchemeGroupVersion.WithResource("foos")):
+		fooInformer := c.ExtraConfig.DemoInformers.Demo().V1alpha1().Foos()
+		fooInformer.Informer()
+		newFoos = func(client clientset.Interface) foocontroller.Foos {
+			return foocontroller.NewV1alpha1Foos(client, fooInformer)
+		}
+	default:
+		return fmt.Errorf("embedded controllers need foos to be served")
+	}
+	var configInformer demov1alpha1informers.ConfigInformer
+	if utilfeature.DefaultFeatureGate.Enabled(features.ConfigResource) && resourceConfig.ResourceEnabled(v1alpha1.SchemeGroupVersion.WithResource("configs")) {
+		configInformer = c.ExtraConfig.DemoInformers.Demo().V1alpha1().Configs()
+		configInformer.Informer()
+	}
+
+	var watchdog *leaderelection.HealthzAdaptor
+	if cfg.LeaderElection.LeaderElect {
+		watchdog = leaderelection.NewLeaderHealthzAdaptor(leaderElectionHealthzTimeout)
+		if err := s.GenericAPIServer.AddHealthChecks(watchdog); err != nil {
+			return err
+		}
+	}
+
+	return s.GenericAPIServer.AddPostStartHook("start-demo-controllers", func(hookContext genericapiserver.PostStartHookContext) error {
+		demoClient, err := clientset.NewForConfig(DemoClientConfig(hookContext.LoopbackClientConfig))
+		if err != nil {
+			return err
+		}
+		foos := newFoos(demoClient)
+		return RunControllers(wait.ContextForChannel(hookContext.StopCh), cfg, watchdog, func(ctx context.Context) {
+			foocontroller.NewController(foos, cfg.KubeClient, configInformer).Run(ctx, cfg.ConcurrentFooSyncs)
+		})
+	})
+}
+
+// RunControllers starts run in the background, right away without leader
+// election and whenever this process becomes the leader with it. The lease is
+// shared with the manager binary, so only one of them runs the controllers.
+// watchdog may be nil.
+func RunControllers(ctx context.Context, cfg *ControllersConfig, watchdog *leaderelection.HealthzAdaptor, run func(context.Context)) error {
+	if !cfg.LeaderElection.LeaderElect {
+		go run(ctx)
+		return nil
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	lock, err := resourcelock.New(cfg.LeaderElection.ResourceLock,
+		cfg.LeaderElection.ResourceNamespace,
+		cfg.LeaderElection.ResourceName,
+		cfg.KubeClient.CoreV1(),
+		cfg.KubeClient.CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: hostname + "_" + string(uuid.NewUUID())})
+	if err != nil {
+		return err
+	}
+	electionConfig := leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: cfg.LeaderElection.LeaseDuration.Duration,
+		RenewDeadline: cfg.LeaderElection.RenewDeadline.Duration,
+		RetryPeriod:   cfg.LeaderElection.RetryPeriod.Duration,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: run,
+			OnStoppedLeading: func() {
+				klog.Info("No longer the leader, demo controllers stopped")
+			},
+		},
+		ReleaseOnCancel: true,
+		Name:            cfg.LeaderElection.ResourceName,
+	}
+	if watchdog != nil {
+		electionConfig.WatchDog = watchdog
+	}
+	// a server that loses the lease keeps serving and runs for it again
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		leaderelection.RunOrDie(ctx, electionConfig)
+	}, cfg.LeaderElection.RetryPeriod.Duration)
+	return nil
+}
@@ -0,0 +1,62 @@
+package apiserver
+
+import (
+	"context"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	componentbaseconfig "k8s.io/component-base/config"
+	"testing"
+	"time"
+)
+
+func TestRunControllers(t *testing.T) {
+	for _, leaderElect := range []bool{false, true} {
+		name := "without leader election"
+		if leaderElect {
+			name = "with leader election"
+		}
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			kubeClient := fake.NewSimpleClientset()
+			cfg := &ControllersConfig{
+				KubeClient:         kubeClient,
+				ConcurrentFooSyncs: 1,
+				LeaderElection: componentbaseconfig.LeaderElectionConfiguration{
+					LeaderElect:       leaderElect,
+					LeaseDuration:     metav1.Duration{Duration: 2 * time.Second},
+					RenewDeadline:     metav1.Duration{Duration: time.Second},
+					RetryPeriod:       metav1.Duration{Duration: 100 * time.Millisecond},
+					ResourceLock:      resourcelock.LeasesResourceLock,
+					ResourceName:      "demo-apiserver-controllers",
+					ResourceNamespace: "kube-system",
+				},
+			}
+			started := make(chan struct{})
+			if err := RunControllers(ctx, cfg, nil, func(context.Context) { close(started) }); err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case <-started:
+			case <-time.After(wait.ForeverTestTimeout):
+				t.Fatal("expected the controllers to run")
+			}
+
+			if !leaderElect {
+				if actions := kubeClient.Actions(); len(actions) > 0 {
+					t.Errorf("expected no lease to be taken, got %v", actions)
+				}
+				return
+			}
+			lease, err := kubeClient.CoordinationV1().Leases("kube-system").Get(ctx, "demo-apiserver-controllers", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("expected the lease to be taken: %v", err)
+			}
+			if lease.Spec.HolderIdentity == nil || len(*lease.Spec.HolderIdentity) == 0 {
+				t.Errorf("expected the lease to have a holder, got %+v", lease.Spec)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package apiserver
+
+import (
+	"github.com/emicklei/go-restful/v3"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/deprecation"
+	"k8s.io/apiserver/pkg/registry/rest"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/warning"
+	versioninfo "k8s.io/component-base/version"
+	"path"
+	"sort"
+	"strings"
+)
+
+// deprecationWarnings returns the warnings requests against the deprecated
+// kinds of storage get, by the discovery path of their group version. Neither
+// discovery document has a field for deprecation, so the discovery of a
+// version warns like the requests do.
+func deprecationWarnings(storage map[string]map[string]rest.Storage) map[string][]string {
+	currentMajor, currentMinor, _ := deprecation.MajorMinor(versioninfo.Get())
+	warnings := map[string][]string{}
+	for version, resources := range storage {
+		gv := schema.GroupVersion{Group: demo.GroupName, Version: version}
+		for resource, s := range resources {
+			if strings.Contains(resource, "/") {
+				continue
+			}
+			kinds, _, err := Scheme.ObjectKinds(s.New())
+			if err != nil {
+				continue
+			}
+			obj, err := Scheme.New(gv.WithKind(kinds[0].Kind))
+			if err != nil {
+				continue
+			}
+			obj.GetObjectKind().SetGroupVersionKind(gv.WithKind(kinds[0].Kind))
+			if deprecation.IsDeprecated(obj, currentMajor, currentMinor) {
+				discoveryPath := path.Join("/apis", gv.Group, gv.Version)
+				warnings[discoveryPath] = append(warnings[discoveryPath], deprecation.WarningMessage(obj))
+			}
+		}
+	}
+	for _, w := range warnings {
+		sort.Strings(w)
+	}
+	return warnings
+}
+
+// installDiscoveryDeprecationWarnings adds the warnings to the discovery
+// documents of the group versions, which clients such as kubectl
+// api-resources read before any request.
+func installDiscoveryDeprecationWarnings(s *genericapiserver.GenericAPIServer, warnings map[string][]string) {
+	if len(warnings) == 0 {
+		return
+	}
+	s.Handler.GoRestfulContainer.Filter(func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		for _, w := range warnings[strings.TrimSuffix(req.Request.URL.Path, "/")] {
+			warning.AddWarning(req.Request.Context(), "", w)
+		}
+		chain.ProcessFilter(req, resp)
+	})
+}
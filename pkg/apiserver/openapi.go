@@ -0,0 +1,46 @@
+package apiserver
+
+import (
+	"github.com/guodoliu/apiserver/pkg/features"
+	"github.com/guodoliu/apiserver/pkg/generated/openapi"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/kube-openapi/pkg/common"
+)
+
+// gatedField is a property of a generated OpenAPI definition that is only
+// published while its feature gate is enabled.
+type gatedField struct {
+	definition string
+	property   string
+	feature    featuregate.Feature
+}
+
+var gatedFields = []gatedField{
+	{"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1.FooSpec", "configName", features.ConfigResource},
+	{"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1.FooSpec", "configName", features.ConfigResource},
+}
+
+// GetOpenAPIDefinitions returns the generated definitions without the fields
+// of disabled feature gates, so clients only see what the server accepts.
+func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+	defs := openapi.GetOpenAPIDefinitions(ref)
+	for _, f := range gatedFields {
+		if utilfeature.DefaultFeatureGate.Enabled(f.feature) {
+			continue
+		}
+		def, ok := defs[f.definition]
+		if !ok {
+			continue
+		}
+		delete(def.Schema.Properties, f.property)
+		for i, required := range def.Schema.Required {
+			if required == f.property {
+				def.Schema.Required = append(def.Schema.Required[:i:i], def.Schema.Required[i+1:]...)
+				break
+			}
+		}
+		defs[f.definition] = def
+	}
+	return defs
+}
@@ -0,0 +1,41 @@
+package apiserver
+
+import (
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/features"
+	"github.com/guodoliu/apiserver/pkg/generated/openapi"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/kube-openapi/pkg/common"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"testing"
+)
+
+func TestGetOpenAPIDefinitions(t *testing.T) {
+	ref := func(path string) spec.Ref {
+		return spec.MustCreateRef("#/definitions/" + common.EscapeJsonPointer(path))
+	}
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("ConfigResource=%v", enabled), func(t *testing.T) {
+			featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ConfigResource, enabled)
+			defs := GetOpenAPIDefinitions(ref)
+			for _, definition := range []string{
+				"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1.FooSpec",
+				"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1.FooSpec",
+			} {
+				properties := defs[definition].Schema.Properties
+				if _, ok := properties["configName"]; ok != enabled {
+					t.Errorf("expected configName in %s: %v, got %v", definition, enabled, ok)
+				}
+				if _, ok := properties["image"]; !ok {
+					t.Errorf("expected the other fields of %s to stay, got %v", definition, properties)
+				}
+			}
+		})
+	}
+
+	// the generated definitions are left alone
+	if _, ok := openapi.GetOpenAPIDefinitions(ref)["github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1.FooSpec"].Schema.Properties["configName"]; !ok {
+		t.Error("expected the generated definitions to keep configName")
+	}
+}
@@ -0,0 +1,16 @@
+package apiserver
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+)
+
+// DemoClientConfig returns a copy of config for the demo clientset that
+// negotiates protobuf, falling back to JSON for responses such as errors of
+// proxies in between.
+func DemoClientConfig(config *rest.Config) *rest.Config {
+	config = rest.CopyConfig(config)
+	config.ContentType = runtime.ContentTypeProtobuf
+	config.AcceptContentTypes = runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
+	return config
+}
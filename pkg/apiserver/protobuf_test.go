@@ -0,0 +1,162 @@
+package apiserver
+
+import (
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testFoo returns a Foo as the controller leaves it, with i in its name.
+func testFoo(i int) *v1beta1.Foo {
+	return &v1beta1.Foo{
+		TypeMeta: metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "Foo"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("foo-%d", i),
+			Namespace:         metav1.NamespaceDefault,
+			UID:               "6f1a2c1e-3b0e-4a53-9a7d-3f2c7d1b9e40",
+			ResourceVersion:   "12345",
+			Generation:        3,
+			CreationTimestamp: metav1.NewTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)),
+			Labels:            map[string]string{"app": "foo", "tier": "backend"},
+			Finalizers:        []string{v1beta1.FooCleanupFinalizer},
+		},
+		Spec: v1beta1.FooSpec{
+			Image:                         "busybox:1.36",
+			Config:                        v1beta1.FooConfig{Msg: "hello world", Msg1: "hello again"},
+			Replicas:                      ptr.To[int32](3),
+			TerminationGracePeriodSeconds: ptr.To[int64](30),
+		},
+		Status: v1beta1.FooStatus{
+			Phase: v1beta1.FooPhaseReady,
+			Conditions: []v1beta1.FooCondition{
+				{Type: v1beta1.FooConditionTypeConfig, Status: metav1.ConditionTrue},
+				{Type: v1beta1.FooConditionTypeWorker, Status: metav1.ConditionTrue},
+			},
+			Replicas: 3,
+			Selector: "demo.k8s.io/foo=foo",
+		},
+	}
+}
+
+func serializerFor(t testing.TB, mediaType string) runtime.Serializer {
+	info, ok := runtime.SerializerInfoForMediaType(Codec.SupportedMediaTypes(), mediaType)
+	if !ok {
+		t.Fatalf("no serializer for %s", mediaType)
+	}
+	return info.Serializer
+}
+
+// TestProtobufKinds checks that every kind of the demo versions has generated
+// protobuf code, since clients and etcd are always offered protobuf.
+func TestProtobufKinds(t *testing.T) {
+	for _, gv := range []schema.GroupVersion{v1alpha1.SchemeGroupVersion, v1beta1.SchemeGroupVersion} {
+		for kind, typ := range Scheme.KnownTypes(gv) {
+			if _, ok := reflect.New(typ).Interface().(runtime.ProtobufMarshaller); !ok {
+				t.Errorf("%s %s cannot be encoded as protobuf, run hack/update_codegen.sh", gv, kind)
+			}
+		}
+	}
+}
+
+func TestDemoClientConfig(t *testing.T) {
+	config := &rest.Config{Host: "https://127.0.0.1:6443"}
+	demoConfig := DemoClientConfig(config)
+	if demoConfig.ContentType != runtime.ContentTypeProtobuf || demoConfig.AcceptContentTypes != runtime.ContentTypeProtobuf+","+runtime.ContentTypeJSON {
+		t.Errorf("expected protobuf to be negotiated, got %q and %q", demoConfig.ContentType, demoConfig.AcceptContentTypes)
+	}
+	if len(config.ContentType) > 0 || len(config.AcceptContentTypes) > 0 {
+		t.Errorf("expected config to be left alone, got %q and %q", config.ContentType, config.AcceptContentTypes)
+	}
+}
+
+func TestProtobufRoundTrip(t *testing.T) {
+	alpha := &v1alpha1.Foo{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "Foo"},
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: metav1.NamespaceDefault},
+		Spec:       v1alpha1.FooSpec{Image: "busybox:1.36", Config: v1alpha1.FooConfig{Msg: "hello"}, Replicas: ptr.To[int32](2)},
+		Status:     v1alpha1.FooStatus{Phase: "Processing"},
+	}
+	config := &v1alpha1.Config{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "Config"},
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: metav1.NamespaceDefault},
+		Spec:       v1alpha1.ConfigSpec{Msg: "hello"},
+		Immutable:  ptr.To(true),
+	}
+	defaults := &v1beta1.FooDefaults{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "FooDefaults"},
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults"},
+		Spec: v1beta1.FooDefaultsSpec{
+			Selector:      &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+			Priority:      10,
+			ImageRegistry: "registry.example.com",
+			Labels:        map[string]string{"team": "demo"},
+		},
+	}
+
+	protobuf := serializerFor(t, runtime.ContentTypeProtobuf)
+	for _, obj := range []runtime.Object{testFoo(0), alpha, config, defaults} {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		t.Run(gvk.Version+"/"+gvk.Kind, func(t *testing.T) {
+			data, err := runtime.Encode(protobuf, obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := runtime.Decode(protobuf, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !apiequality.Semantic.DeepEqual(obj, got) {
+				t.Errorf("expected the %s to round trip, got %#v", gvk.Kind, got)
+			}
+		})
+	}
+}
+
+// BenchmarkFooEncode encodes a Foo as JSON and as protobuf and reports the
+// size of the result.
+func BenchmarkFooEncode(b *testing.B) {
+	foo := testFoo(0)
+	for _, mediaType := range []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf} {
+		b.Run(mediaType, func(b *testing.B) {
+			serializer := serializerFor(b, mediaType)
+			var data []byte
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var err error
+				if data, err = runtime.Encode(serializer, foo); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/object")
+		})
+	}
+}
+
+// BenchmarkFooDecode decodes a Foo from JSON and from protobuf.
+func BenchmarkFooDecode(b *testing.B) {
+	for _, mediaType := range []string{runtime.ContentTypeJSON, runtime.ContentTypeProtobuf} {
+		b.Run(mediaType, func(b *testing.B) {
+			serializer := serializerFor(b, mediaType)
+			data, err := runtime.Encode(serializer, testFoo(0))
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := serializer.Decode(data, nil, &v1beta1.Foo{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package foo
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"github.com/guodoliu/apiserver/pkg/features"
+	demov1alpha1informers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions/demo/v1alpha1"
+	demov1alpha1listers "github.com/guodoliu/apiserver/pkg/generated/listers/demo/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"slices"
+	"time"
+)
+
+// readyPollInterval is how often a Foo whose Deployment is not available yet
+// is looked at again. Deployments of the backing cluster are not watched.
+const readyPollInterval = 10 * time.Second
+
+// Controller keeps a ConfigMap and a Deployment in the backing cluster in line
+// with every Foo and reports their state in the Foo status.
+type Controller struct {
+	foos       Foos
+	kubeClient kubernetes.Interface
+
+	// configInformer and configLister are nil unless Configs are served
+	configInformer cache.SharedIndexInformer
+	configLister   demov1alpha1listers.ConfigLister
+
+	queue workqueue.RateLimitingInterface
+}
+
+// NewController returns a controller for foos, the Foos of the version the
+// demo server serves. kubeClient talks to the backing cluster. The Configs
+// that Foos refer to come from configInformer; without one,
+// spec.configName is ignored.
+func NewController(foos Foos, kubeClient kubernetes.Interface, configInformer demov1alpha1informers.ConfigInformer) *Controller {
+	c := &Controller{
+		foos:       foos,
+		kubeClient: kubeClient,
+		queue:      workqueue.NewRateLimitingQueueWithConfig(workqueue.DefaultControllerRateLimiter(), workqueue.RateLimitingQueueConfig{Name: "foo"}),
+	}
+	if configInformer != nil {
+		c.configInformer = configInformer.Informer()
+		c.configLister = configInformer.Lister()
+	}
+	return c
+}
+
+// Run syncs Foos with the given number of workers until ctx is done. The
+// event handlers only live as long as Run, so a controller that lost and
+// regained leadership can be replaced by a new one on the same informer.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx)
+	logger.Info("Starting foo controller")
+	defer logger.Info("Shutting down foo controller")
+
+	registration, err := c.foos.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueue,
+		UpdateFunc: func(_, obj interface{}) { c.enqueue(obj) },
+	})
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	defer func() {
+		if err := c.foos.Informer().RemoveEventHandler(registration); err != nil {
+			utilruntime.HandleError(err)
+		}
+	}()
+
+	synced := []cache.InformerSynced{registration.HasSynced}
+	if c.configInformer != nil {
+		configRegistration, err := c.configInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.enqueueFoosOf,
+			UpdateFunc: c.updateConfig,
+			DeleteFunc: c.enqueueFoosOf,
+		})
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+		defer func() {
+			if err := c.configInformer.RemoveEventHandler(configRegistration); err != nil {
+				utilruntime.HandleError(err)
+			}
+		}()
+		synced = append(synced, configRegistration.HasSynced)
+	}
+
+	if !cache.WaitForNamedCacheSync("foo", ctx.Done(), synced...) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// updateConfig syncs the Foos of a changed Config. An immutable Config is
+// treated as cached forever: none of its updates can change what its Foos
+// use, only its deletion can.
+func (c *Controller) updateConfig(old, obj interface{}) {
+	if oldConfig, ok := old.(*v1alpha1.Config); ok && ptr.Deref(oldConfig.Immutable, false) {
+		return
+	}
+	c.enqueueFoosOf(obj)
+}
+
+// enqueueFoosOf queues the Foos that refer to a Config.
+func (c *Controller) enqueueFoosOf(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	config, ok := obj.(*v1alpha1.Config)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("not a Config: %T", obj))
+		return
+	}
+	foos, err := c.foos.List(config.Namespace)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, foo := range foos {
+		if foo.Spec.ConfigName == config.Name {
+			c.enqueue(foo)
+		}
+	}
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(ctx, key.(string)); err != nil {
+		utilruntime.HandleError(fmt.Errorf("syncing Foo %q failed: %v", key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) sync(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	foo, err := c.foos.Get(namespace, name)
+	if errors.IsNotFound(err) {
+		// the garbage collector removes what we created through the owner references
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if foo.DeletionTimestamp != nil {
+		return c.finalize(ctx, key, foo)
+	}
+
+	// the ConfigMap is left as it is while the Config is missing
+	config, err := c.config(foo)
+	configFound := !errors.IsNotFound(err)
+	if err != nil && configFound {
+		return err
+	}
+	if configFound {
+		if err := c.syncConfigMap(ctx, foo, config); err != nil {
+			return err
+		}
+	}
+	deployment, err := c.syncDeployment(ctx, foo)
+	if err != nil {
+		return err
+	}
+
+	status := newStatus(foo, deployment, configFound)
+	if !equality.Semantic.DeepEqual(foo.Status, status) {
+		if err := c.updateStatus(ctx, foo, status); err != nil {
+			return err
+		}
+	}
+	if status.Phase != v1beta1.FooPhaseReady {
+		c.queue.AddAfter(key, readyPollInterval)
+	}
+	return nil
+}
+
+// finalize deletes the Deployment of a deleted foo and, once it and its pods
+// are gone, the ConfigMap. Then it removes the cleanup finalizer and ends the
+// grace period of the foo, if it was deleted with one.
+func (c *Controller) finalize(ctx context.Context, key string, foo *v1beta1.Foo) error {
+	if slices.Contains(foo.Finalizers, v1beta1.FooCleanupFinalizer) {
+		done, err := c.cleanup(ctx, key, foo)
+		if err != nil || !done {
+			return err
+		}
+	}
+	return c.endGracePeriod(ctx, key, foo)
+}
+
+// cleanup removes what the controller created for a deleted foo and then the
+// cleanup finalizer. It returns false while it waits for the backing cluster.
+func (c *Controller) cleanup(ctx context.Context, key string, foo *v1beta1.Foo) (bool, error) {
+	// the Deployment stays until its pods stopped, with the grace period of
+	// spec.terminationGracePeriodSeconds
+	deployments := c.kubeClient.AppsV1().Deployments(foo.Namespace)
+	gone, err := deleteOwned(ctx, foo, deployments.Get, deployments.Delete)
+	if err != nil {
+		return false, err
+	}
+	if !gone {
+		c.queue.AddAfter(key, readyPollInterval)
+		return false, nil
+	}
+	configMaps := c.kubeClient.CoreV1().ConfigMaps(foo.Namespace)
+	if gone, err = deleteOwned(ctx, foo, configMaps.Get, configMaps.Delete); err != nil {
+		return false, err
+	}
+	if !gone {
+		c.queue.AddAfter(key, readyPollInterval)
+		return false, nil
+	}
+
+	updated := foo.DeepCopy()
+	updated.Finalizers = slices.DeleteFunc(updated.Finalizers, func(f string) bool {
+		return f == v1beta1.FooCleanupFinalizer
+	})
+	if err := c.foos.Update(ctx, updated, false); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// endGracePeriod deletes a foo whose grace period is over for good. The
+// server only deletes a foo by itself once its grace period is 0, so this is
+// also what finishes foos deleted gracefully without the cleanup finalizer.
+func (c *Controller) endGracePeriod(ctx context.Context, key string, foo *v1beta1.Foo) error {
+	if ptr.Deref(foo.DeletionGracePeriodSeconds, 0) == 0 {
+		return nil
+	}
+	// the deletion timestamp is when the grace period ends
+	if remaining := time.Until(foo.DeletionTimestamp.Time); remaining > 0 {
+		c.queue.AddAfter(key, remaining)
+		return nil
+	}
+	err := c.foos.Delete(ctx, foo.Namespace, foo.Name, metav1.DeleteOptions{
+		GracePeriodSeconds: ptr.To[int64](0),
+		Preconditions:      metav1.NewUIDPreconditions(string(foo.UID)),
+	})
+	if errors.IsNotFound(err) || errors.IsConflict(err) {
+		return nil
+	}
+	return err
+}
+
+// deleteOwned deletes the object of the backing cluster named after foo, in
+// the foreground, if foo controls it. It returns true once there is no such
+// object left.
+func deleteOwned[T metav1.Object](ctx context.Context, foo *v1beta1.Foo,
+	get func(context.Context, string, metav1.GetOptions) (T, error),
+	del func(context.Context, string, metav1.DeleteOptions) error) (bool, error) {
+	existing, err := get(ctx, foo.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !metav1.IsControlledBy(existing, foo) {
+		// not ours to delete
+		return true, nil
+	}
+	if existing.GetDeletionTimestamp() != nil {
+		return false, nil
+	}
+	err = del(ctx, foo.Name, metav1.DeleteOptions{
+		PropagationPolicy: ptr.To(metav1.DeletePropagationForeground),
+		Preconditions:     metav1.NewUIDPreconditions(string(existing.GetUID())),
+	})
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	return false, err
+}
+
+// config returns the Config foo refers to, or nil if it refers to none or
+// Configs are not served.
+func (c *Controller) config(foo *v1beta1.Foo) (*v1alpha1.Config, error) {
+	if len(foo.Spec.ConfigName) == 0 || c.configLister == nil {
+		return nil, nil
+	}
+	return c.configLister.Configs(foo.Namespace).Get(foo.Spec.ConfigName)
+}
+
+func (c *Controller) syncConfigMap(ctx context.Context, foo *v1beta1.Foo, config *v1alpha1.Config) error {
+	desired := NewConfigMap(foo, config)
+	c.setOwnerVersion(&desired.ObjectMeta)
+	client := c.kubeClient.CoreV1().ConfigMaps(foo.Namespace)
+
+	existing, err := client.Get(ctx, desired.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = client.Create(ctx, desired, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(existing, foo) {
+		return fmt.Errorf("ConfigMap %s/%s already exists and is not owned by the Foo", existing.Namespace, existing.Name)
+	}
+	if equality.Semantic.DeepEqual(existing.Data, desired.Data) {
+		return nil
+	}
+	updated := existing.DeepCopy()
+	updated.Data = desired.Data
+	_, err = client.Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *Controller) syncDeployment(ctx context.Context, foo *v1beta1.Foo) (*appsv1.Deployment, error) {
+	desired := NewDeployment(foo)
+	c.setOwnerVersion(&desired.ObjectMeta)
+	client := c.kubeClient.AppsV1().Deployments(foo.Namespace)
+
+	existing, err := client.Get(ctx, desired.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return client.Create(ctx, desired, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !metav1.IsControlledBy(existing, foo) {
+		return nil, fmt.Errorf("Deployment %s/%s already exists and is not owned by the Foo", existing.Namespace, existing.Name)
+	}
+	// the backing cluster defaults fields we leave empty
+	if equality.Semantic.DeepDerivative(desired.Spec, existing.Spec) {
+		return existing, nil
+	}
+	updated := existing.DeepCopy()
+	updated.Spec = desired.Spec
+	return client.Update(ctx, updated, metav1.UpdateOptions{})
+}
+
+// setOwnerVersion makes the owner references refer to the served version of
+// Foo, which the garbage collector of the backing cluster has to find.
+func (c *Controller) setOwnerVersion(meta *metav1.ObjectMeta) {
+	for i := range meta.OwnerReferences {
+		meta.OwnerReferences[i].APIVersion = c.foos.GroupVersion().String()
+	}
+}
+
+// newStatus reports the Config as synced unless the Config the Foo refers to
+// is missing, and the Worker as up once the Deployment has rolled out and all
+// its replicas are available. The replicas and selector are those the scale
+// subresource reports.
+func newStatus(foo *v1beta1.Foo, deployment *appsv1.Deployment, configFound bool) v1beta1.FooStatus {
+	workerReady := deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == deployment.Status.Replicas &&
+		deployment.Status.AvailableReplicas >= ptr.Deref(deployment.Spec.Replicas, 1)
+
+	status := v1beta1.FooStatus{
+		Phase: v1beta1.FooPhaseProcessing,
+		Conditions: []v1beta1.FooCondition{
+			{Type: v1beta1.FooConditionTypeConfig, Status: metav1.ConditionTrue},
+			{Type: v1beta1.FooConditionTypeWorker, Status: metav1.ConditionFalse},
+		},
+		Replicas: deployment.Status.Replicas,
+		Selector: WorkerSelector(foo).String(),
+	}
+	if !configFound {
+		status.Conditions[0].Status = metav1.ConditionFalse
+	}
+	if workerReady {
+		status.Conditions[1].Status = metav1.ConditionTrue
+	}
+	if configFound && workerReady {
+		status.Phase = v1beta1.FooPhaseReady
+	}
+	return status
+}
+
+func (c *Controller) updateStatus(ctx context.Context, foo *v1beta1.Foo, status v1beta1.FooStatus) error {
+	updated := foo.DeepCopy()
+	updated.Status = status
+
+	return c.foos.Update(ctx, updated, utilfeature.DefaultFeatureGate.Enabled(features.FooStatusSubresource))
+}
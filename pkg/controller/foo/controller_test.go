@@ -0,0 +1,515 @@
+package foo
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"github.com/guodoliu/apiserver/pkg/features"
+	demofake "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/fake"
+	informers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions"
+	appsv1 "k8s.io/api/apps/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestController returns a controller whose informers hold the given
+// demo objects, with fake clients of this server and the backing cluster
+// that start out with the same objects.
+func newTestController(t *testing.T, demoObjects []runtime.Object, kubeObjects ...runtime.Object) (*Controller, *demofake.Clientset, *kubefake.Clientset) {
+	t.Helper()
+	demoClient := demofake.NewSimpleClientset(demoObjects...)
+	kubeClient := kubefake.NewSimpleClientset(kubeObjects...)
+	factory := informers.NewSharedInformerFactory(demoClient, 0)
+	fooInformer := factory.Demo().V1beta1().Foos()
+	configInformer := factory.Demo().V1alpha1().Configs()
+	for _, obj := range demoObjects {
+		var err error
+		switch obj.(type) {
+		case *v1beta1.Foo:
+			err = fooInformer.Informer().GetIndexer().Add(obj)
+		default:
+			err = configInformer.Informer().GetIndexer().Add(obj)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := NewController(NewV1beta1Foos(demoClient, fooInformer), kubeClient, configInformer)
+	t.Cleanup(c.queue.ShutDown)
+	demoClient.ClearActions()
+	kubeClient.ClearActions()
+	return c, demoClient, kubeClient
+}
+
+func newFoo(name string) *v1beta1.Foo {
+	return &v1beta1.Foo{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault, UID: types.UID("uid-" + name)},
+		Spec:       v1beta1.FooSpec{Image: "busybox:1.36", Config: v1beta1.FooConfig{Msg: "hello"}},
+	}
+}
+
+// deletedFoo returns a foo deleted with a grace period of 30s that ends
+// after the given time from now.
+func deletedFoo(name string, remaining time.Duration, finalizers ...string) *v1beta1.Foo {
+	foo := newFoo(name)
+	foo.DeletionTimestamp = ptr.To(metav1.NewTime(time.Now().Add(remaining)))
+	foo.DeletionGracePeriodSeconds = ptr.To[int64](30)
+	foo.Finalizers = finalizers
+	return foo
+}
+
+// verbs lists the verb and resource of every action, like "delete foos".
+func verbs(actions []k8stesting.Action) []string {
+	var verbs []string
+	for _, action := range actions {
+		verbs = append(verbs, action.GetVerb()+" "+action.GetResource().Resource)
+	}
+	return verbs
+}
+
+func TestFinalize(t *testing.T) {
+	tests := []struct {
+		name string
+		foo  *v1beta1.Foo
+		// kube objects are owned by the foo
+		deployment, configMap bool
+		demoVerbs, kubeVerbs  []string
+		finalizerRemoved      bool
+	}{
+		{
+			name:       "Deployment deleted first",
+			foo:        deletedFoo("foo", -time.Second, v1beta1.FooCleanupFinalizer),
+			deployment: true, configMap: true,
+			kubeVerbs: []string{"get deployments", "delete deployments"},
+		},
+		{
+			name:      "ConfigMap deleted once the Deployment is gone",
+			foo:       deletedFoo("foo", -time.Second, v1beta1.FooCleanupFinalizer),
+			configMap: true,
+			kubeVerbs: []string{"get deployments", "get configmaps", "delete configmaps"},
+		},
+		{
+			name:             "cleaned up after the grace period",
+			foo:              deletedFoo("foo", -time.Second, v1beta1.FooCleanupFinalizer),
+			kubeVerbs:        []string{"get deployments", "get configmaps"},
+			demoVerbs:        []string{"update foos", "delete foos"},
+			finalizerRemoved: true,
+		},
+		{
+			name:             "cleaned up within the grace period",
+			foo:              deletedFoo("foo", time.Hour, v1beta1.FooCleanupFinalizer),
+			kubeVerbs:        []string{"get deployments", "get configmaps"},
+			demoVerbs:        []string{"update foos"},
+			finalizerRemoved: true,
+		},
+		{
+			name:      "grace period over without the finalizer",
+			foo:       deletedFoo("foo", -time.Second),
+			demoVerbs: []string{"delete foos"},
+		},
+		{
+			name: "grace period running without the finalizer",
+			foo:  deletedFoo("foo", time.Hour),
+		},
+		{
+			name: "deleted without a grace period",
+			foo: func() *v1beta1.Foo {
+				foo := deletedFoo("foo", 0, v1beta1.FooCleanupFinalizer)
+				foo.DeletionGracePeriodSeconds = nil
+				return foo
+			}(),
+			kubeVerbs:        []string{"get deployments", "get configmaps"},
+			demoVerbs:        []string{"update foos"},
+			finalizerRemoved: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var kubeObjects []runtime.Object
+			if tc.deployment {
+				kubeObjects = append(kubeObjects, NewDeployment(tc.foo))
+			}
+			if tc.configMap {
+				kubeObjects = append(kubeObjects, NewConfigMap(tc.foo, nil))
+			}
+			c, demoClient, kubeClient := newTestController(t, []runtime.Object{tc.foo}, kubeObjects...)
+
+			if err := c.sync(context.Background(), "default/foo"); err != nil {
+				t.Fatal(err)
+			}
+			if got := verbs(kubeClient.Actions()); !slices.Equal(got, tc.kubeVerbs) {
+				t.Errorf("expected %v in the backing cluster, got %v", tc.kubeVerbs, got)
+			}
+			if got := verbs(demoClient.Actions()); !slices.Equal(got, tc.demoVerbs) {
+				t.Fatalf("expected %v of foos, got %v", tc.demoVerbs, got)
+			}
+			for _, action := range demoClient.Actions() {
+				switch action := action.(type) {
+				case k8stesting.UpdateAction:
+					if finalizers := action.GetObject().(*v1beta1.Foo).Finalizers; tc.finalizerRemoved != (len(finalizers) == 0) {
+						t.Errorf("expected the finalizer to be removed: %v, got %v", tc.finalizerRemoved, finalizers)
+					}
+				case k8stesting.DeleteAction:
+					options := action.GetDeleteOptions()
+					if ptr.Deref(options.GracePeriodSeconds, -1) != 0 || options.Preconditions == nil || *options.Preconditions.UID != tc.foo.UID {
+						t.Errorf("expected the foo to be deleted without grace period, got %+v", options)
+					}
+				}
+			}
+			if tc.kubeVerbs != nil && tc.kubeVerbs[len(tc.kubeVerbs)-1] == "delete deployments" {
+				options := kubeClient.Actions()[1].(k8stesting.DeleteAction).GetDeleteOptions()
+				if ptr.Deref(options.PropagationPolicy, "") != metav1.DeletePropagationForeground {
+					t.Errorf("expected the Deployment to be deleted in the foreground, got %+v", options)
+				}
+			}
+		})
+	}
+}
+
+// readyDeployment returns the Deployment of foo with all its replicas rolled
+// out and available.
+func readyDeployment(foo *v1beta1.Foo) *appsv1.Deployment {
+	deployment := NewDeployment(foo)
+	deployment.Status = appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	return deployment
+}
+
+func TestSync(t *testing.T) {
+	readyStatus := v1beta1.FooStatus{
+		Phase: v1beta1.FooPhaseReady,
+		Conditions: []v1beta1.FooCondition{
+			{Type: v1beta1.FooConditionTypeConfig, Status: metav1.ConditionTrue},
+			{Type: v1beta1.FooConditionTypeWorker, Status: metav1.ConditionTrue},
+		},
+		Replicas: 1,
+		Selector: "demo.k8s.io/foo=foo",
+	}
+	tests := []struct {
+		name        string
+		foo         *v1beta1.Foo
+		kubeObjects func(foo *v1beta1.Foo) []runtime.Object
+		kubeVerbs   []string
+		wantErr     string
+		// status is what the foo is updated to, nil if it is left alone
+		status *v1beta1.FooStatus
+	}{
+		{
+			name:      "created",
+			foo:       newFoo("foo"),
+			kubeVerbs: []string{"get configmaps", "create configmaps", "get deployments", "create deployments"},
+			status: &v1beta1.FooStatus{
+				Phase: v1beta1.FooPhaseProcessing,
+				Conditions: []v1beta1.FooCondition{
+					{Type: v1beta1.FooConditionTypeConfig, Status: metav1.ConditionTrue},
+					{Type: v1beta1.FooConditionTypeWorker, Status: metav1.ConditionFalse},
+				},
+				Selector: "demo.k8s.io/foo=foo",
+			},
+		},
+		{
+			name: "ready",
+			foo:  newFoo("foo"),
+			kubeObjects: func(foo *v1beta1.Foo) []runtime.Object {
+				return []runtime.Object{NewConfigMap(foo, nil), readyDeployment(foo)}
+			},
+			kubeVerbs: []string{"get configmaps", "get deployments"},
+			status:    &readyStatus,
+		},
+		{
+			name: "status unchanged",
+			foo: func() *v1beta1.Foo {
+				foo := newFoo("foo")
+				foo.Status = readyStatus
+				return foo
+			}(),
+			kubeObjects: func(foo *v1beta1.Foo) []runtime.Object {
+				return []runtime.Object{NewConfigMap(foo, nil), readyDeployment(foo)}
+			},
+			kubeVerbs: []string{"get configmaps", "get deployments"},
+		},
+		{
+			name: "ConfigMap and Deployment updated",
+			foo:  newFoo("foo"),
+			kubeObjects: func(foo *v1beta1.Foo) []runtime.Object {
+				changed := foo.DeepCopy()
+				changed.Spec.Image = "busybox:1.35"
+				changed.Spec.Config.Msg = "bye"
+				return []runtime.Object{NewConfigMap(changed, nil), readyDeployment(changed)}
+			},
+			kubeVerbs: []string{"get configmaps", "update configmaps", "get deployments", "update deployments"},
+			status:    &readyStatus,
+		},
+		{
+			name: "Config missing",
+			foo: func() *v1beta1.Foo {
+				foo := newFoo("foo")
+				foo.Spec.ConfigName = "missing"
+				return foo
+			}(),
+			kubeObjects: func(foo *v1beta1.Foo) []runtime.Object {
+				return []runtime.Object{readyDeployment(foo)}
+			},
+			kubeVerbs: []string{"get deployments"},
+			status: &v1beta1.FooStatus{
+				Phase: v1beta1.FooPhaseProcessing,
+				Conditions: []v1beta1.FooCondition{
+					{Type: v1beta1.FooConditionTypeConfig, Status: metav1.ConditionFalse},
+					{Type: v1beta1.FooConditionTypeWorker, Status: metav1.ConditionTrue},
+				},
+				Replicas: 1,
+				Selector: "demo.k8s.io/foo=foo",
+			},
+		},
+		{
+			name: "Deployment of someone else",
+			foo:  newFoo("foo"),
+			kubeObjects: func(foo *v1beta1.Foo) []runtime.Object {
+				deployment := NewDeployment(foo)
+				deployment.OwnerReferences = nil
+				return []runtime.Object{NewConfigMap(foo, nil), deployment}
+			},
+			kubeVerbs: []string{"get configmaps", "get deployments"},
+			wantErr:   "not owned by the Foo",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var kubeObjects []runtime.Object
+			if tc.kubeObjects != nil {
+				kubeObjects = tc.kubeObjects(tc.foo)
+			}
+			c, demoClient, kubeClient := newTestController(t, []runtime.Object{tc.foo}, kubeObjects...)
+
+			err := c.sync(context.Background(), "default/foo")
+			if len(tc.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("expected an error containing %q, got %v", tc.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got := verbs(kubeClient.Actions()); !slices.Equal(got, tc.kubeVerbs) {
+				t.Errorf("expected %v in the backing cluster, got %v", tc.kubeVerbs, got)
+			}
+			for _, action := range kubeClient.Actions() {
+				if action, ok := action.(k8stesting.CreateAction); ok {
+					owner := metav1.GetControllerOf(action.GetObject().(metav1.Object))
+					if owner == nil || owner.APIVersion != "demo.k8s.io/v1beta1" || owner.UID != tc.foo.UID {
+						t.Errorf("expected the %s to be owned by the v1beta1 Foo, got %+v", action.GetResource().Resource, owner)
+					}
+				}
+			}
+
+			actions := demoClient.Actions()
+			if tc.status == nil {
+				if len(actions) > 0 {
+					t.Errorf("expected the foo to be left alone, got %v", verbs(actions))
+				}
+				return
+			}
+			if got := verbs(actions); !slices.Equal(got, []string{"update foos"}) {
+				t.Fatalf("expected the status to be updated, got %v", got)
+			}
+			if got := actions[0].(k8stesting.UpdateAction).GetObject().(*v1beta1.Foo).Status; !apiequality.Semantic.DeepEqual(got, *tc.status) {
+				t.Errorf("expected status %+v, got %+v", *tc.status, got)
+			}
+		})
+	}
+}
+
+// TestSyncConfig checks that the ConfigMap carries the messages of the Config
+// the foo refers to.
+func TestSyncConfig(t *testing.T) {
+	foo := newFoo("foo")
+	foo.Spec.ConfigName = "config"
+	config := &v1alpha1.Config{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: metav1.NamespaceDefault},
+		Spec:       v1alpha1.ConfigSpec{Msg: "from the config"},
+	}
+	c, _, kubeClient := newTestController(t, []runtime.Object{foo, config})
+	if err := c.sync(context.Background(), "default/foo"); err != nil {
+		t.Fatal(err)
+	}
+	configMap, err := kubeClient.CoreV1().ConfigMaps(metav1.NamespaceDefault).Get(context.Background(), "foo", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if configMap.Data["msg"] != "from the config" {
+		t.Errorf("expected the message of the Config, got %v", configMap.Data)
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("FooStatusSubresource=%v", enabled), func(t *testing.T) {
+			featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.FooStatusSubresource, enabled)
+			foo := newFoo("foo")
+			c, demoClient, _ := newTestController(t, []runtime.Object{foo})
+			if err := c.updateStatus(context.Background(), foo, v1beta1.FooStatus{Phase: v1beta1.FooPhaseReady}); err != nil {
+				t.Fatal(err)
+			}
+			actions := demoClient.Actions()
+			if len(actions) != 1 {
+				t.Fatalf("expected one update, got %v", verbs(actions))
+			}
+			if got := actions[0].GetSubresource(); enabled != (got == "status") {
+				t.Errorf("expected the status subresource to be used: %v, got subresource %q", enabled, got)
+			}
+		})
+	}
+}
+
+// TestSyncV1alpha1 syncs a server that serves only v1alpha1 Foos.
+func TestSyncV1alpha1(t *testing.T) {
+	foo := &v1alpha1.Foo{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: metav1.NamespaceDefault, UID: "uid-foo"},
+		Spec:       v1alpha1.FooSpec{Image: "busybox:1.36", Config: v1alpha1.FooConfig{Msg: "hello"}, Replicas: ptr.To[int32](1)},
+	}
+	demoClient := demofake.NewSimpleClientset(foo)
+	kubeClient := kubefake.NewSimpleClientset()
+	fooInformer := informers.NewSharedInformerFactory(demoClient, 0).Demo().V1alpha1().Foos()
+	if err := fooInformer.Informer().GetIndexer().Add(foo); err != nil {
+		t.Fatal(err)
+	}
+	c := NewController(NewV1alpha1Foos(demoClient, fooInformer), kubeClient, nil)
+	t.Cleanup(c.queue.ShutDown)
+	demoClient.ClearActions()
+
+	if err := c.sync(context.Background(), "default/foo"); err != nil {
+		t.Fatal(err)
+	}
+	deployment, err := kubeClient.AppsV1().Deployments(metav1.NamespaceDefault).Get(context.Background(), "foo", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner := metav1.GetControllerOf(deployment); owner == nil || owner.APIVersion != "demo.k8s.io/v1alpha1" || owner.UID != foo.UID {
+		t.Errorf("expected the Deployment to be owned by the v1alpha1 Foo, got %+v", owner)
+	}
+	if image := deployment.Spec.Template.Spec.Containers[0].Image; image != "busybox:1.36" {
+		t.Errorf("expected the image of the foo, got %q", image)
+	}
+	actions := demoClient.Actions()
+	if len(actions) != 1 || actions[0].GetVerb() != "update" || actions[0].GetResource().Version != "v1alpha1" {
+		t.Fatalf("expected the v1alpha1 foo to be updated, got %v", actions)
+	}
+	if updated := actions[0].(k8stesting.UpdateAction).GetObject().(*v1alpha1.Foo); updated.Status.Phase != "Processing" || updated.Spec.Image != foo.Spec.Image {
+		t.Errorf("expected the converted foo with its status, got %+v", updated)
+	}
+}
+
+// TestFinalizeFlow syncs a deleted foo until it is gone, feeding what the
+// controller wrote back to its informer.
+func TestFinalizeFlow(t *testing.T) {
+	foo := deletedFoo("foo", -time.Second, v1beta1.FooCleanupFinalizer)
+	c, demoClient, kubeClient := newTestController(t, []runtime.Object{foo}, NewDeployment(foo), NewConfigMap(foo, nil))
+	ctx := context.Background()
+
+	var kubeVerbs, demoVerbs []string
+	for i := 0; i < 5; i++ {
+		if err := c.sync(ctx, "default/foo"); err != nil {
+			t.Fatal(err)
+		}
+		kubeVerbs = append(kubeVerbs, verbs(kubeClient.Actions())...)
+		demoVerbs = append(demoVerbs, verbs(demoClient.Actions())...)
+		kubeClient.ClearActions()
+		demoClient.ClearActions()
+
+		indexer := c.foos.Informer().GetIndexer()
+		current, err := demoClient.DemoV1beta1().Foos(metav1.NamespaceDefault).Get(ctx, "foo", metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			err = indexer.Delete(foo)
+		} else if err == nil {
+			err = indexer.Update(current)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		demoClient.ClearActions()
+	}
+
+	if want := []string{"get deployments", "delete deployments", "get deployments", "get configmaps", "delete configmaps", "get deployments", "get configmaps"}; !slices.Equal(kubeVerbs, want) {
+		t.Errorf("expected %v in the backing cluster, got %v", want, kubeVerbs)
+	}
+	if want := []string{"update foos", "delete foos"}; !slices.Equal(demoVerbs, want) {
+		t.Errorf("expected %v of foos, got %v", want, demoVerbs)
+	}
+	if _, err := c.foos.Get(metav1.NamespaceDefault, "foo"); !errors.IsNotFound(err) {
+		t.Errorf("expected the foo to be gone, got %v", err)
+	}
+}
+
+func TestConfigEvents(t *testing.T) {
+	config := func(immutable bool, msg string) *v1alpha1.Config {
+		return &v1alpha1.Config{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: metav1.NamespaceDefault},
+			Spec:       v1alpha1.ConfigSpec{Msg: msg},
+			Immutable:  ptr.To(immutable),
+		}
+	}
+	user, other := newFoo("user"), newFoo("other")
+	user.Spec.ConfigName = "config"
+	other.Spec.ConfigName = "other"
+
+	tests := []struct {
+		name  string
+		event func(c *Controller)
+		// queued are the keys of the foos that are synced
+		queued []string
+	}{
+		{
+			name:   "mutable Config updated",
+			event:  func(c *Controller) { c.updateConfig(config(false, "hello"), config(false, "changed")) },
+			queued: []string{"default/user"},
+		},
+		{
+			name:   "made immutable",
+			event:  func(c *Controller) { c.updateConfig(config(false, "hello"), config(true, "changed")) },
+			queued: []string{"default/user"},
+		},
+		{
+			name:  "immutable Config updated",
+			event: func(c *Controller) { c.updateConfig(config(true, "hello"), config(true, "hello")) },
+		},
+		{
+			name:   "immutable Config deleted",
+			event:  func(c *Controller) { c.enqueueFoosOf(config(true, "hello")) },
+			queued: []string{"default/user"},
+		},
+		{
+			name: "deleted Config missed by the watch",
+			event: func(c *Controller) {
+				c.enqueueFoosOf(cache.DeletedFinalStateUnknown{Key: "default/config", Obj: config(true, "hello")})
+			},
+			queued: []string{"default/user"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _, _ := newTestController(t, []runtime.Object{user, other})
+			tc.event(c)
+			var queued []string
+			for c.queue.Len() > 0 {
+				key, _ := c.queue.Get()
+				queued = append(queued, key.(string))
+				c.queue.Done(key)
+			}
+			if !slices.Equal(queued, tc.queued) {
+				t.Errorf("expected %v to be queued, got %v", tc.queued, queued)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package foo
+
+import (
+	"context"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/install"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	clientset "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned"
+	demov1alpha1informers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions/demo/v1alpha1"
+	demoinformers "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions/demo/v1beta1"
+	demov1alpha1listers "github.com/guodoliu/apiserver/pkg/generated/listers/demo/v1alpha1"
+	demolisters "github.com/guodoliu/apiserver/pkg/generated/listers/demo/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Foos reads and writes the Foos of one served version for the controller,
+// which works on v1beta1 Foos whatever the version.
+type Foos interface {
+	// GroupVersion is the version the objects of the backing cluster refer to
+	// their Foo with
+	GroupVersion() schema.GroupVersion
+	Informer() cache.SharedIndexInformer
+	Get(namespace, name string) (*v1beta1.Foo, error)
+	List(namespace string) ([]*v1beta1.Foo, error)
+	// Update writes foo, through foos/status if status is set
+	Update(ctx context.Context, foo *v1beta1.Foo, status bool) error
+	Delete(ctx context.Context, namespace, name string, options metav1.DeleteOptions) error
+}
+
+// NewV1beta1Foos returns the Foos of v1beta1, the version the controller
+// prefers.
+func NewV1beta1Foos(client clientset.Interface, informer demoinformers.FooInformer) Foos {
+	return &v1beta1Foos{client: client, informer: informer.Informer(), lister: informer.Lister()}
+}
+
+type v1beta1Foos struct {
+	client   clientset.Interface
+	informer cache.SharedIndexInformer
+	lister   demolisters.FooLister
+}
+
+func (f *v1beta1Foos) GroupVersion() schema.GroupVersion   { return v1beta1.SchemeGroupVersion }
+func (f *v1beta1Foos) Informer() cache.SharedIndexInformer { return f.informer }
+
+func (f *v1beta1Foos) Get(namespace, name string) (*v1beta1.Foo, error) {
+	return f.lister.Foos(namespace).Get(name)
+}
+
+func (f *v1beta1Foos) List(namespace string) ([]*v1beta1.Foo, error) {
+	return f.lister.Foos(namespace).List(labels.Everything())
+}
+
+func (f *v1beta1Foos) Update(ctx context.Context, foo *v1beta1.Foo, status bool) error {
+	var err error
+	if status {
+		_, err = f.client.DemoV1beta1().Foos(foo.Namespace).UpdateStatus(ctx, foo, metav1.UpdateOptions{})
+	} else {
+		_, err = f.client.DemoV1beta1().Foos(foo.Namespace).Update(ctx, foo, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+func (f *v1beta1Foos) Delete(ctx context.Context, namespace, name string, options metav1.DeleteOptions) error {
+	return f.client.DemoV1beta1().Foos(namespace).Delete(ctx, name, options)
+}
+
+// scheme converts between the versions of Foo, through the internal version.
+var scheme = runtime.NewScheme()
+
+func init() {
+	install.Install(scheme)
+}
+
+func convert(in, out runtime.Object) error {
+	internal := &demo.Foo{}
+	if err := scheme.Convert(in, internal, nil); err != nil {
+		return err
+	}
+	return scheme.Convert(internal, out, nil)
+}
+
+// NewV1alpha1Foos returns the Foos of v1alpha1, for servers that do not
+// serve v1beta1. They are converted from and to v1beta1.
+func NewV1alpha1Foos(client clientset.Interface, informer demov1alpha1informers.FooInformer) Foos {
+	return &v1alpha1Foos{client: client, informer: informer.Informer(), lister: informer.Lister()}
+}
+
+type v1alpha1Foos struct {
+	client   clientset.Interface
+	informer cache.SharedIndexInformer
+	lister   demov1alpha1listers.FooLister
+}
+
+func (f *v1alpha1Foos) GroupVersion() schema.GroupVersion   { return v1alpha1.SchemeGroupVersion }
+func (f *v1alpha1Foos) Informer() cache.SharedIndexInformer { return f.informer }
+
+func (f *v1alpha1Foos) Get(namespace, name string) (*v1beta1.Foo, error) {
+	foo, err := f.lister.Foos(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	// the conversion may share memory with the cached foo, which the
+	// controller copies before it changes anything
+	converted := &v1beta1.Foo{}
+	return converted, convert(foo, converted)
+}
+
+func (f *v1alpha1Foos) List(namespace string) ([]*v1beta1.Foo, error) {
+	foos, err := f.lister.Foos(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]*v1beta1.Foo, len(foos))
+	for i, foo := range foos {
+		converted[i] = &v1beta1.Foo{}
+		if err := convert(foo, converted[i]); err != nil {
+			return nil, err
+		}
+	}
+	return converted, nil
+}
+
+func (f *v1alpha1Foos) Update(ctx context.Context, foo *v1beta1.Foo, status bool) error {
+	converted := &v1alpha1.Foo{}
+	if err := convert(foo, converted); err != nil {
+		return err
+	}
+	var err error
+	if status {
+		_, err = f.client.DemoV1alpha1().Foos(foo.Namespace).UpdateStatus(ctx, converted, metav1.UpdateOptions{})
+	} else {
+		_, err = f.client.DemoV1alpha1().Foos(foo.Namespace).Update(ctx, converted, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+func (f *v1alpha1Foos) Delete(ctx context.Context, namespace, name string, options metav1.DeleteOptions) error {
+	return f.client.DemoV1alpha1().Foos(namespace).Delete(ctx, name, options)
+}
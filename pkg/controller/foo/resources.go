@@ -0,0 +1,92 @@
+package foo
+
+import (
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// FooNameLabel is set on every object the controller creates for a Foo
+	FooNameLabel = "demo.k8s.io/foo"
+	// ConfigMountPath is where the Foo's ConfigMap is mounted in its container
+	ConfigMountPath = "/etc/foo"
+)
+
+// NewConfigMap returns the ConfigMap that carries the configuration of foo.
+// It lives next to foo, in the same namespace of the backing cluster. The
+// messages come from config, the Config foo refers to, if it is set.
+func NewConfigMap(foo *v1beta1.Foo, config *v1alpha1.Config) *corev1.ConfigMap {
+	data := map[string]string{
+		"msg":  foo.Spec.Config.Msg,
+		"msg1": foo.Spec.Config.Msg1,
+	}
+	if config != nil {
+		data = map[string]string{
+			"msg":  config.Spec.Msg,
+			"msg1": config.Spec.Msg1,
+		}
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: newObjectMeta(foo),
+		Data:       data,
+	}
+}
+
+// NewDeployment returns the Deployment that runs spec.replicas workers with the
+// image of foo and its ConfigMap mounted at ConfigMountPath.
+func NewDeployment(foo *v1beta1.Foo) *appsv1.Deployment {
+	labels := map[string]string{FooNameLabel: foo.Name}
+	return &appsv1.Deployment{
+		ObjectMeta: newObjectMeta(foo),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(ptr.Deref(foo.Spec.Replicas, 1)),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					TerminationGracePeriodSeconds: foo.Spec.TerminationGracePeriodSeconds,
+					Containers: []corev1.Container{{
+						Name:  "foo",
+						Image: foo.Spec.Image,
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "config",
+							MountPath: ConfigMountPath,
+							ReadOnly:  true,
+						}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "config",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: foo.Name},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// WorkerSelector selects the pods of the workers of foo.
+func WorkerSelector(foo *v1beta1.Foo) labels.Selector {
+	return labels.SelectorFromSet(labels.Set{FooNameLabel: foo.Name})
+}
+
+// newObjectMeta names objects after foo and makes foo their controller, so the
+// garbage collector of the backing cluster removes them with foo.
+func newObjectMeta(foo *v1beta1.Foo) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      foo.Name,
+		Namespace: foo.Namespace,
+		Labels:    map[string]string{FooNameLabel: foo.Name},
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(foo, v1beta1.SchemeGroupVersion.WithKind("Foo")),
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package features
+
+import (
+	"k8s.io/apimachinery/pkg/util/runtime"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	// FooStatusSubresource serves foos/status. Writes to the main foos
+	// endpoint no longer change the status.
+	FooStatusSubresource featuregate.Feature = "FooStatusSubresource"
+
+	// ConfigResource serves the Config kind as configs.demo.k8s.io and lets
+	// a Foo refer to one through spec.configName.
+	ConfigResource featuregate.Feature = "ConfigResource"
+)
+
+func init() {
+	runtime.Must(utilfeature.DefaultMutableFeatureGate.Add(defaultDemoFeatureGates))
+}
+
+// defaultDemoFeatureGates consists of all known demo-specific feature keys.
+// To add a new feature, define a key for it above and add it here. The features
+// are toggled with --feature-gates or features.featureGates in the config file.
+var defaultDemoFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	FooStatusSubresource: {Default: false, PreRelease: featuregate.Alpha},
+	ConfigResource:       {Default: false, PreRelease: featuregate.Alpha},
+}
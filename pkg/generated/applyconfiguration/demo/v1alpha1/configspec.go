@@ -0,0 +1,47 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+// ConfigSpecApplyConfiguration represents an declarative configuration of the ConfigSpec type for use
+// with apply.
+type ConfigSpecApplyConfiguration struct {
+	Msg  *string `json:"msg,omitempty"`
+	Msg1 *string `json:"msg1,omitempty"`
+}
+
+// ConfigSpecApplyConfiguration constructs an declarative configuration of the ConfigSpec type for use with
+// apply.
+func ConfigSpec() *ConfigSpecApplyConfiguration {
+	return &ConfigSpecApplyConfiguration{}
+}
+
+// WithMsg sets the Msg field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Msg field is set to the value of the last call.
+func (b *ConfigSpecApplyConfiguration) WithMsg(value string) *ConfigSpecApplyConfiguration {
+	b.Msg = &value
+	return b
+}
+
+// WithMsg1 sets the Msg1 field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Msg1 field is set to the value of the last call.
+func (b *ConfigSpecApplyConfiguration) WithMsg1(value string) *ConfigSpecApplyConfiguration {
+	b.Msg1 = &value
+	return b
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+// FooSpecApplyConfiguration represents an declarative configuration of the FooSpec type for use
+// with apply.
+type FooSpecApplyConfiguration struct {
+	Image                         *string                      `json:"image,omitempty"`
+	Config                        *FooConfigApplyConfiguration `json:"config,omitempty"`
+	ConfigName                    *string                      `json:"configName,omitempty"`
+	Replicas                      *int32                       `json:"replicas,omitempty"`
+	TerminationGracePeriodSeconds *int64                       `json:"terminationGracePeriodSeconds,omitempty"`
+}
+
+// FooSpecApplyConfiguration constructs an declarative configuration of the FooSpec type for use with
+// apply.
+func FooSpec() *FooSpecApplyConfiguration {
+	return &FooSpecApplyConfiguration{}
+}
+
+// WithImage sets the Image field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Image field is set to the value of the last call.
+func (b *FooSpecApplyConfiguration) WithImage(value string) *FooSpecApplyConfiguration {
+	b.Image = &value
+	return b
+}
+
+// WithConfig sets the Config field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Config field is set to the value of the last call.
+func (b *FooSpecApplyConfiguration) WithConfig(value *FooConfigApplyConfiguration) *FooSpecApplyConfiguration {
+	b.Config = value
+	return b
+}
+
+// WithConfigName sets the ConfigName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ConfigName field is set to the value of the last call.
+func (b *FooSpecApplyConfiguration) WithConfigName(value string) *FooSpecApplyConfiguration {
+	b.ConfigName = &value
+	return b
+}
+
+// WithReplicas sets the Replicas field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Replicas field is set to the value of the last call.
+func (b *FooSpecApplyConfiguration) WithReplicas(value int32) *FooSpecApplyConfiguration {
+	b.Replicas = &value
+	return b
+}
+
+// WithTerminationGracePeriodSeconds sets the TerminationGracePeriodSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TerminationGracePeriodSeconds field is set to the value of the last call.
+func (b *FooSpecApplyConfiguration) WithTerminationGracePeriodSeconds(value int64) *FooSpecApplyConfiguration {
+	b.TerminationGracePeriodSeconds = &value
+	return b
+}
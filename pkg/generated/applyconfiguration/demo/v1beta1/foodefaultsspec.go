@@ -0,0 +1,93 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/client-go/applyconfigurations/meta/v1"
+)
+
+// FooDefaultsSpecApplyConfiguration represents an declarative configuration of the FooDefaultsSpec type for use
+// with apply.
+type FooDefaultsSpecApplyConfiguration struct {
+	Selector      *v1.LabelSelectorApplyConfiguration `json:"selector,omitempty"`
+	Priority      *int32                              `json:"priority,omitempty"`
+	ImageRegistry *string                             `json:"imageRegistry,omitempty"`
+	ImageTag      *string                             `json:"imageTag,omitempty"`
+	Msg           *string                             `json:"msg,omitempty"`
+	Labels        map[string]string                   `json:"labels,omitempty"`
+}
+
+// FooDefaultsSpecApplyConfiguration constructs an declarative configuration of the FooDefaultsSpec type for use with
+// apply.
+func FooDefaultsSpec() *FooDefaultsSpecApplyConfiguration {
+	return &FooDefaultsSpecApplyConfiguration{}
+}
+
+// WithSelector sets the Selector field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Selector field is set to the value of the last call.
+func (b *FooDefaultsSpecApplyConfiguration) WithSelector(value *v1.LabelSelectorApplyConfiguration) *FooDefaultsSpecApplyConfiguration {
+	b.Selector = value
+	return b
+}
+
+// WithPriority sets the Priority field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Priority field is set to the value of the last call.
+func (b *FooDefaultsSpecApplyConfiguration) WithPriority(value int32) *FooDefaultsSpecApplyConfiguration {
+	b.Priority = &value
+	return b
+}
+
+// WithImageRegistry sets the ImageRegistry field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ImageRegistry field is set to the value of the last call.
+func (b *FooDefaultsSpecApplyConfiguration) WithImageRegistry(value string) *FooDefaultsSpecApplyConfiguration {
+	b.ImageRegistry = &value
+	return b
+}
+
+// WithImageTag sets the ImageTag field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ImageTag field is set to the value of the last call.
+func (b *FooDefaultsSpecApplyConfiguration) WithImageTag(value string) *FooDefaultsSpecApplyConfiguration {
+	b.ImageTag = &value
+	return b
+}
+
+// WithMsg sets the Msg field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Msg field is set to the value of the last call.
+func (b *FooDefaultsSpecApplyConfiguration) WithMsg(value string) *FooDefaultsSpecApplyConfiguration {
+	b.Msg = &value
+	return b
+}
+
+// WithLabels puts the entries into the Labels field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the entries provided by each call will be put on the Labels field,
+// overwriting an existing map entries in Labels field with the same key.
+func (b *FooDefaultsSpecApplyConfiguration) WithLabels(entries map[string]string) *FooDefaultsSpecApplyConfiguration {
+	if b.Labels == nil && len(entries) > 0 {
+		b.Labels = make(map[string]string, len(entries))
+	}
+	for k, v := range entries {
+		b.Labels[k] = v
+	}
+	return b
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package applyconfiguration
+
+import (
+	v1alpha1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	v1beta1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	demov1alpha1 "github.com/guodoliu/apiserver/pkg/generated/applyconfiguration/demo/v1alpha1"
+	demov1beta1 "github.com/guodoliu/apiserver/pkg/generated/applyconfiguration/demo/v1beta1"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ForKind returns an apply configuration type for the given GroupVersionKind, or nil if no
+// apply configuration type exists for the given GroupVersionKind.
+func ForKind(kind schema.GroupVersionKind) interface{} {
+	switch kind {
+	// Group=demo.k8s.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithKind("Config"):
+		return &demov1alpha1.ConfigApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ConfigSpec"):
+		return &demov1alpha1.ConfigSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("Foo"):
+		return &demov1alpha1.FooApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("FooCondition"):
+		return &demov1alpha1.FooConditionApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("FooConfig"):
+		return &demov1alpha1.FooConfigApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("FooSpec"):
+		return &demov1alpha1.FooSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("FooStatus"):
+		return &demov1alpha1.FooStatusApplyConfiguration{}
+
+		// Group=demo.k8s.io, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithKind("Foo"):
+		return &demov1beta1.FooApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FooCondition"):
+		return &demov1beta1.FooConditionApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FooConfig"):
+		return &demov1beta1.FooConfigApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FooDefaults"):
+		return &demov1beta1.FooDefaultsApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FooDefaultsSpec"):
+		return &demov1beta1.FooDefaultsSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FooSpec"):
+		return &demov1beta1.FooSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FooStatus"):
+		return &demov1beta1.FooStatusApplyConfiguration{}
+
+	}
+	return nil
+}
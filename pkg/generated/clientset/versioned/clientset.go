@@ -22,6 +22,7 @@ import (
 	"net/http"
 
 	demov1alpha1 "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/typed/demo/v1alpha1"
+	demov1beta1 "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/typed/demo/v1beta1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -30,12 +31,14 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	DemoV1alpha1() demov1alpha1.DemoV1alpha1Interface
+	DemoV1beta1() demov1beta1.DemoV1beta1Interface
 }
 
 // Clientset contains the clients for groups.
 type Clientset struct {
 	*discovery.DiscoveryClient
 	demoV1alpha1 *demov1alpha1.DemoV1alpha1Client
+	demoV1beta1  *demov1beta1.DemoV1beta1Client
 }
 
 // DemoV1alpha1 retrieves the DemoV1alpha1Client
@@ -43,6 +46,11 @@ func (c *Clientset) DemoV1alpha1() demov1alpha1.DemoV1alpha1Interface {
 	return c.demoV1alpha1
 }
 
+// DemoV1beta1 retrieves the DemoV1beta1Client
+func (c *Clientset) DemoV1beta1() demov1beta1.DemoV1beta1Interface {
+	return c.demoV1beta1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -91,6 +99,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.demoV1beta1, err = demov1beta1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -113,6 +125,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.demoV1alpha1 = demov1alpha1.New(c)
+	cs.demoV1beta1 = demov1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -21,6 +21,8 @@ import (
 	clientset "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned"
 	demov1alpha1 "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/typed/demo/v1alpha1"
 	fakedemov1alpha1 "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/typed/demo/v1alpha1/fake"
+	demov1beta1 "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/typed/demo/v1beta1"
+	fakedemov1beta1 "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/typed/demo/v1beta1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -82,3 +84,8 @@ var (
 func (c *Clientset) DemoV1alpha1() demov1alpha1.DemoV1alpha1Interface {
 	return &fakedemov1alpha1.FakeDemoV1alpha1{Fake: &c.Fake}
 }
+
+// DemoV1beta1 retrieves the DemoV1beta1Client
+func (c *Clientset) DemoV1beta1() demov1beta1.DemoV1beta1Interface {
+	return &fakedemov1beta1.FakeDemoV1beta1{Fake: &c.Fake}
+}
@@ -19,6 +19,7 @@ package fake
 
 import (
 	demov1alpha1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	demov1beta1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -31,6 +32,7 @@ var codecs = serializer.NewCodecFactory(scheme)
 
 var localSchemeBuilder = runtime.SchemeBuilder{
 	demov1alpha1.AddToScheme,
+	demov1beta1.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
@@ -0,0 +1,207 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+	"time"
+
+	v1alpha1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	demov1alpha1 "github.com/guodoliu/apiserver/pkg/generated/applyconfiguration/demo/v1alpha1"
+	scheme "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ConfigsGetter has a method to return a ConfigInterface.
+// A group's client should implement this interface.
+type ConfigsGetter interface {
+	Configs(namespace string) ConfigInterface
+}
+
+// ConfigInterface has methods to work with Config resources.
+type ConfigInterface interface {
+	Create(ctx context.Context, config *v1alpha1.Config, opts v1.CreateOptions) (*v1alpha1.Config, error)
+	Update(ctx context.Context, config *v1alpha1.Config, opts v1.UpdateOptions) (*v1alpha1.Config, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.Config, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ConfigList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Config, err error)
+	Apply(ctx context.Context, config *demov1alpha1.ConfigApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Config, err error)
+	ConfigExpansion
+}
+
+// configs implements ConfigInterface
+type configs struct {
+	client rest.Interface
+	ns     string
+}
+
+// newConfigs returns a Configs
+func newConfigs(c *DemoV1alpha1Client, namespace string) *configs {
+	return &configs{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the config, and returns the corresponding config object, and an error if there is any.
+func (c *configs) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.Config, err error) {
+	result = &v1alpha1.Config{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("configs").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of Configs that match those selectors.
+func (c *configs) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ConfigList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.ConfigList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("configs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested configs.
+func (c *configs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("configs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a config and creates it.  Returns the server's representation of the config, and an error, if there is any.
+func (c *configs) Create(ctx context.Context, config *v1alpha1.Config, opts v1.CreateOptions) (result *v1alpha1.Config, err error) {
+	result = &v1alpha1.Config{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("configs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(config).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a config and updates it. Returns the server's representation of the config, and an error, if there is any.
+func (c *configs) Update(ctx context.Context, config *v1alpha1.Config, opts v1.UpdateOptions) (result *v1alpha1.Config, err error) {
+	result = &v1alpha1.Config{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("configs").
+		Name(config.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(config).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the config and deletes it. Returns an error if one occurs.
+func (c *configs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("configs").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *configs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("configs").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched config.
+func (c *configs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.Config, err error) {
+	result = &v1alpha1.Config{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("configs").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied config.
+func (c *configs) Apply(ctx context.Context, config *demov1alpha1.ConfigApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Config, err error) {
+	if config == nil {
+		return nil, fmt.Errorf("config provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	name := config.Name
+	if name == nil {
+		return nil, fmt.Errorf("config.Name must be provided to Apply")
+	}
+	result = &v1alpha1.Config{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("configs").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -27,14 +27,19 @@ import (
 
 type DemoV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	ConfigsGetter
 	FoosGetter
 }
 
-// DemoV1alpha1Client is used to interact with features provided by the demo.guodoliu.bytedance.com group.
+// DemoV1alpha1Client is used to interact with features provided by the demo.k8s.io group.
 type DemoV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *DemoV1alpha1Client) Configs(namespace string) ConfigInterface {
+	return newConfigs(c, namespace)
+}
+
 func (c *DemoV1alpha1Client) Foos(namespace string) FooInterface {
 	return newFoos(c, namespace)
 }
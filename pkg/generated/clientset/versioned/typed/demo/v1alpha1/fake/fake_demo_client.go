@@ -27,6 +27,10 @@ type FakeDemoV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeDemoV1alpha1) Configs(namespace string) v1alpha1.ConfigInterface {
+	return &FakeConfigs{c, namespace}
+}
+
 func (c *FakeDemoV1alpha1) Foos(namespace string) v1alpha1.FooInterface {
 	return &FakeFoos{c, namespace}
 }
@@ -19,8 +19,11 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1alpha1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	demov1alpha1 "github.com/guodoliu/apiserver/pkg/generated/applyconfiguration/demo/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
 	types "k8s.io/apimachinery/pkg/types"
@@ -138,3 +141,48 @@ func (c *FakeFoos) Patch(ctx context.Context, name string, pt types.PatchType, d
 	}
 	return obj.(*v1alpha1.Foo), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied foo.
+func (c *FakeFoos) Apply(ctx context.Context, foo *demov1alpha1.FooApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Foo, err error) {
+	if foo == nil {
+		return nil, fmt.Errorf("foo provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(foo)
+	if err != nil {
+		return nil, err
+	}
+	name := foo.Name
+	if name == nil {
+		return nil, fmt.Errorf("foo.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(foosResource, c.ns, *name, types.ApplyPatchType, data), &v1alpha1.Foo{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Foo), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeFoos) ApplyStatus(ctx context.Context, foo *demov1alpha1.FooApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.Foo, err error) {
+	if foo == nil {
+		return nil, fmt.Errorf("foo provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(foo)
+	if err != nil {
+		return nil, err
+	}
+	name := foo.Name
+	if name == nil {
+		return nil, fmt.Errorf("foo.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(foosResource, c.ns, *name, types.ApplyPatchType, data, "status"), &v1alpha1.Foo{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.Foo), err
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1beta1 "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/typed/demo/v1beta1"
+	rest "k8s.io/client-go/rest"
+	testing "k8s.io/client-go/testing"
+)
+
+type FakeDemoV1beta1 struct {
+	*testing.Fake
+}
+
+func (c *FakeDemoV1beta1) Foos(namespace string) v1beta1.FooInterface {
+	return &FakeFoos{c, namespace}
+}
+
+func (c *FakeDemoV1beta1) FooDefaults(namespace string) v1beta1.FooDefaultsInterface {
+	return &FakeFooDefaults{c, namespace}
+}
+
+func (c *FakeDemoV1beta1) FooReviews() v1beta1.FooReviewInterface {
+	return &FakeFooReviews{c}
+}
+
+func (c *FakeDemoV1beta1) FooSummaries() v1beta1.FooSummaryInterface {
+	return &FakeFooSummaries{c}
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *FakeDemoV1beta1) RESTClient() rest.Interface {
+	var ret *rest.RESTClient
+	return ret
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	demov1beta1 "github.com/guodoliu/apiserver/pkg/generated/applyconfiguration/demo/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeFooDefaults implements FooDefaultsInterface
+type FakeFooDefaults struct {
+	Fake *FakeDemoV1beta1
+	ns   string
+}
+
+var foodefaultsResource = v1beta1.SchemeGroupVersion.WithResource("foodefaults")
+
+var foodefaultsKind = v1beta1.SchemeGroupVersion.WithKind("FooDefaults")
+
+// Get takes name of the fooDefaults, and returns the corresponding fooDefaults object, and an error if there is any.
+func (c *FakeFooDefaults) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.FooDefaults, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(foodefaultsResource, c.ns, name), &v1beta1.FooDefaults{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FooDefaults), err
+}
+
+// List takes label and field selectors, and returns the list of FooDefaults that match those selectors.
+func (c *FakeFooDefaults) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.FooDefaultsList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(foodefaultsResource, foodefaultsKind, c.ns, opts), &v1beta1.FooDefaultsList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1beta1.FooDefaultsList{ListMeta: obj.(*v1beta1.FooDefaultsList).ListMeta}
+	for _, item := range obj.(*v1beta1.FooDefaultsList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested fooDefaults.
+func (c *FakeFooDefaults) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(foodefaultsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a fooDefaults and creates it.  Returns the server's representation of the fooDefaults, and an error, if there is any.
+func (c *FakeFooDefaults) Create(ctx context.Context, fooDefaults *v1beta1.FooDefaults, opts v1.CreateOptions) (result *v1beta1.FooDefaults, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(foodefaultsResource, c.ns, fooDefaults), &v1beta1.FooDefaults{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FooDefaults), err
+}
+
+// Update takes the representation of a fooDefaults and updates it. Returns the server's representation of the fooDefaults, and an error, if there is any.
+func (c *FakeFooDefaults) Update(ctx context.Context, fooDefaults *v1beta1.FooDefaults, opts v1.UpdateOptions) (result *v1beta1.FooDefaults, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(foodefaultsResource, c.ns, fooDefaults), &v1beta1.FooDefaults{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FooDefaults), err
+}
+
+// Delete takes name of the fooDefaults and deletes it. Returns an error if one occurs.
+func (c *FakeFooDefaults) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(foodefaultsResource, c.ns, name, opts), &v1beta1.FooDefaults{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeFooDefaults) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(foodefaultsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1beta1.FooDefaultsList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched fooDefaults.
+func (c *FakeFooDefaults) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.FooDefaults, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(foodefaultsResource, c.ns, name, pt, data, subresources...), &v1beta1.FooDefaults{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FooDefaults), err
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied fooDefaults.
+func (c *FakeFooDefaults) Apply(ctx context.Context, fooDefaults *demov1beta1.FooDefaultsApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FooDefaults, err error) {
+	if fooDefaults == nil {
+		return nil, fmt.Errorf("fooDefaults provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(fooDefaults)
+	if err != nil {
+		return nil, err
+	}
+	name := fooDefaults.Name
+	if name == nil {
+		return nil, fmt.Errorf("fooDefaults.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(foodefaultsResource, c.ns, *name, types.ApplyPatchType, data), &v1beta1.FooDefaults{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FooDefaults), err
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1beta1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeFooReviews implements FooReviewInterface
+type FakeFooReviews struct {
+	Fake *FakeDemoV1beta1
+}
+
+var fooreviewsResource = v1beta1.SchemeGroupVersion.WithResource("fooreviews")
+
+var fooreviewsKind = v1beta1.SchemeGroupVersion.WithKind("FooReview")
+
+// Create takes the representation of a fooReview and creates it.  Returns the server's representation of the fooReview, and an error, if there is any.
+func (c *FakeFooReviews) Create(ctx context.Context, fooReview *v1beta1.FooReview, opts v1.CreateOptions) (result *v1beta1.FooReview, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(fooreviewsResource, fooReview), &v1beta1.FooReview{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FooReview), err
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1beta1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeFooSummaries implements FooSummaryInterface
+type FakeFooSummaries struct {
+	Fake *FakeDemoV1beta1
+}
+
+var foosummariesResource = v1beta1.SchemeGroupVersion.WithResource("foosummaries")
+
+var foosummariesKind = v1beta1.SchemeGroupVersion.WithKind("FooSummary")
+
+// Get takes name of the fooSummary, and returns the corresponding fooSummary object, and an error if there is any.
+func (c *FakeFooSummaries) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.FooSummary, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(foosummariesResource, name), &v1beta1.FooSummary{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FooSummary), err
+}
+
+// List takes label and field selectors, and returns the list of FooSummaries that match those selectors.
+func (c *FakeFooSummaries) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.FooSummaryList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(foosummariesResource, foosummariesKind, opts), &v1beta1.FooSummaryList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1beta1.FooSummaryList{ListMeta: obj.(*v1beta1.FooSummaryList).ListMeta}
+	for _, item := range obj.(*v1beta1.FooSummaryList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested fooSummaries.
+func (c *FakeFooSummaries) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(foosummariesResource, opts))
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+	"time"
+
+	v1beta1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	demov1beta1 "github.com/guodoliu/apiserver/pkg/generated/applyconfiguration/demo/v1beta1"
+	scheme "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// FooDefaultsGetter has a method to return a FooDefaultsInterface.
+// A group's client should implement this interface.
+type FooDefaultsGetter interface {
+	FooDefaults(namespace string) FooDefaultsInterface
+}
+
+// FooDefaultsInterface has methods to work with FooDefaults resources.
+type FooDefaultsInterface interface {
+	Create(ctx context.Context, fooDefaults *v1beta1.FooDefaults, opts v1.CreateOptions) (*v1beta1.FooDefaults, error)
+	Update(ctx context.Context, fooDefaults *v1beta1.FooDefaults, opts v1.UpdateOptions) (*v1beta1.FooDefaults, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1beta1.FooDefaults, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1beta1.FooDefaultsList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.FooDefaults, err error)
+	Apply(ctx context.Context, fooDefaults *demov1beta1.FooDefaultsApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FooDefaults, err error)
+	FooDefaultsExpansion
+}
+
+// fooDefaults implements FooDefaultsInterface
+type fooDefaults struct {
+	client rest.Interface
+	ns     string
+}
+
+// newFooDefaults returns a FooDefaults
+func newFooDefaults(c *DemoV1beta1Client, namespace string) *fooDefaults {
+	return &fooDefaults{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the fooDefaults, and returns the corresponding fooDefaults object, and an error if there is any.
+func (c *fooDefaults) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.FooDefaults, err error) {
+	result = &v1beta1.FooDefaults{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("foodefaults").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of FooDefaults that match those selectors.
+func (c *fooDefaults) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.FooDefaultsList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1beta1.FooDefaultsList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("foodefaults").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested fooDefaults.
+func (c *fooDefaults) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("foodefaults").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a fooDefaults and creates it.  Returns the server's representation of the fooDefaults, and an error, if there is any.
+func (c *fooDefaults) Create(ctx context.Context, fooDefaults *v1beta1.FooDefaults, opts v1.CreateOptions) (result *v1beta1.FooDefaults, err error) {
+	result = &v1beta1.FooDefaults{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("foodefaults").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(fooDefaults).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a fooDefaults and updates it. Returns the server's representation of the fooDefaults, and an error, if there is any.
+func (c *fooDefaults) Update(ctx context.Context, fooDefaults *v1beta1.FooDefaults, opts v1.UpdateOptions) (result *v1beta1.FooDefaults, err error) {
+	result = &v1beta1.FooDefaults{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("foodefaults").
+		Name(fooDefaults.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(fooDefaults).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the fooDefaults and deletes it. Returns an error if one occurs.
+func (c *fooDefaults) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("foodefaults").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *fooDefaults) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("foodefaults").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched fooDefaults.
+func (c *fooDefaults) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.FooDefaults, err error) {
+	result = &v1beta1.FooDefaults{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("foodefaults").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied fooDefaults.
+func (c *fooDefaults) Apply(ctx context.Context, fooDefaults *demov1beta1.FooDefaultsApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FooDefaults, err error) {
+	if fooDefaults == nil {
+		return nil, fmt.Errorf("fooDefaults provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(fooDefaults)
+	if err != nil {
+		return nil, err
+	}
+	name := fooDefaults.Name
+	if name == nil {
+		return nil, fmt.Errorf("fooDefaults.Name must be provided to Apply")
+	}
+	result = &v1beta1.FooDefaults{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("foodefaults").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"context"
+
+	v1beta1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	scheme "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rest "k8s.io/client-go/rest"
+)
+
+// FooReviewsGetter has a method to return a FooReviewInterface.
+// A group's client should implement this interface.
+type FooReviewsGetter interface {
+	FooReviews() FooReviewInterface
+}
+
+// FooReviewInterface has methods to work with FooReview resources.
+type FooReviewInterface interface {
+	Create(ctx context.Context, fooReview *v1beta1.FooReview, opts v1.CreateOptions) (*v1beta1.FooReview, error)
+	FooReviewExpansion
+}
+
+// fooReviews implements FooReviewInterface
+type fooReviews struct {
+	client rest.Interface
+}
+
+// newFooReviews returns a FooReviews
+func newFooReviews(c *DemoV1beta1Client) *fooReviews {
+	return &fooReviews{
+		client: c.RESTClient(),
+	}
+}
+
+// Create takes the representation of a fooReview and creates it.  Returns the server's representation of the fooReview, and an error, if there is any.
+func (c *fooReviews) Create(ctx context.Context, fooReview *v1beta1.FooReview, opts v1.CreateOptions) (result *v1beta1.FooReview, err error) {
+	result = &v1beta1.FooReview{}
+	err = c.client.Post().
+		Resource("fooreviews").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(fooReview).
+		Do(ctx).
+		Into(result)
+	return
+}
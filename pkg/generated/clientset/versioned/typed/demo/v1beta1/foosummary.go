@@ -0,0 +1,97 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"context"
+	"time"
+
+	v1beta1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	scheme "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// FooSummariesGetter has a method to return a FooSummaryInterface.
+// A group's client should implement this interface.
+type FooSummariesGetter interface {
+	FooSummaries() FooSummaryInterface
+}
+
+// FooSummaryInterface has methods to work with FooSummary resources.
+type FooSummaryInterface interface {
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1beta1.FooSummary, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1beta1.FooSummaryList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	FooSummaryExpansion
+}
+
+// fooSummaries implements FooSummaryInterface
+type fooSummaries struct {
+	client rest.Interface
+}
+
+// newFooSummaries returns a FooSummaries
+func newFooSummaries(c *DemoV1beta1Client) *fooSummaries {
+	return &fooSummaries{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the fooSummary, and returns the corresponding fooSummary object, and an error if there is any.
+func (c *fooSummaries) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.FooSummary, err error) {
+	result = &v1beta1.FooSummary{}
+	err = c.client.Get().
+		Resource("foosummaries").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of FooSummaries that match those selectors.
+func (c *fooSummaries) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.FooSummaryList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1beta1.FooSummaryList{}
+	err = c.client.Get().
+		Resource("foosummaries").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested fooSummaries.
+func (c *fooSummaries) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("foosummaries").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
@@ -19,6 +19,7 @@ package demo
 
 import (
 	v1alpha1 "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions/demo/v1alpha1"
+	v1beta1 "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions/demo/v1beta1"
 	internalinterfaces "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions/internalinterfaces"
 )
 
@@ -26,6 +27,8 @@ import (
 type Interface interface {
 	// V1alpha1 provides access to shared informers for resources in V1alpha1.
 	V1alpha1() v1alpha1.Interface
+	// V1beta1 provides access to shared informers for resources in V1beta1.
+	V1beta1() v1beta1.Interface
 }
 
 type group struct {
@@ -43,3 +46,8 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (g *group) V1alpha1() v1alpha1.Interface {
 	return v1alpha1.New(g.factory, g.namespace, g.tweakListOptions)
 }
+
+// V1beta1 returns a new v1beta1.Interface.
+func (g *group) V1beta1() v1beta1.Interface {
+	return v1beta1.New(g.factory, g.namespace, g.tweakListOptions)
+}
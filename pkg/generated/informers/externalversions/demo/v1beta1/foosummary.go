@@ -0,0 +1,88 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"context"
+	time "time"
+
+	demov1beta1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	versioned "github.com/guodoliu/apiserver/pkg/generated/clientset/versioned"
+	internalinterfaces "github.com/guodoliu/apiserver/pkg/generated/informers/externalversions/internalinterfaces"
+	v1beta1 "github.com/guodoliu/apiserver/pkg/generated/listers/demo/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// FooSummaryInformer provides access to a shared informer and lister for
+// FooSummaries.
+type FooSummaryInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1beta1.FooSummaryLister
+}
+
+type fooSummaryInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewFooSummaryInformer constructs a new informer for FooSummary type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFooSummaryInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredFooSummaryInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredFooSummaryInformer constructs a new informer for FooSummary type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredFooSummaryInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.DemoV1beta1().FooSummaries().List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.DemoV1beta1().FooSummaries().Watch(context.TODO(), options)
+			},
+		},
+		&demov1beta1.FooSummary{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *fooSummaryInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredFooSummaryInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *fooSummaryInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&demov1beta1.FooSummary{}, f.defaultInformer)
+}
+
+func (f *fooSummaryInformer) Lister() v1beta1.FooSummaryLister {
+	return v1beta1.NewFooSummaryLister(f.Informer().GetIndexer())
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	v1beta1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -51,10 +52,20 @@ func (f *genericInformer) Lister() cache.GenericLister {
 // TODO extend this to unknown resources with a client pool
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
-	// Group=demo.guodoliu.bytedance.com, Version=v1alpha1
+	// Group=demo.k8s.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("configs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Demo().V1alpha1().Configs().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("foos"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Demo().V1alpha1().Foos().Informer()}, nil
 
+		// Group=demo.k8s.io, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithResource("foos"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Demo().V1beta1().Foos().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("foodefaults"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Demo().V1beta1().FooDefaults().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("foosummaries"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Demo().V1beta1().FooSummaries().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
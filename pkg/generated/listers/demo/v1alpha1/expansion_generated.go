@@ -17,6 +17,14 @@ limitations under the License.
 
 package v1alpha1
 
+// ConfigListerExpansion allows custom methods to be added to
+// ConfigLister.
+type ConfigListerExpansion interface{}
+
+// ConfigNamespaceListerExpansion allows custom methods to be added to
+// ConfigNamespaceLister.
+type ConfigNamespaceListerExpansion interface{}
+
 // FooListerExpansion allows custom methods to be added to
 // FooLister.
 type FooListerExpansion interface{}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1beta1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// FooDefaultsLister helps list FooDefaults.
+// All objects returned here must be treated as read-only.
+type FooDefaultsLister interface {
+	// List lists all FooDefaults in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1beta1.FooDefaults, err error)
+	// FooDefaults returns an object that can list and get FooDefaults.
+	FooDefaults(namespace string) FooDefaultsNamespaceLister
+	FooDefaultsListerExpansion
+}
+
+// fooDefaultsLister implements the FooDefaultsLister interface.
+type fooDefaultsLister struct {
+	indexer cache.Indexer
+}
+
+// NewFooDefaultsLister returns a new FooDefaultsLister.
+func NewFooDefaultsLister(indexer cache.Indexer) FooDefaultsLister {
+	return &fooDefaultsLister{indexer: indexer}
+}
+
+// List lists all FooDefaults in the indexer.
+func (s *fooDefaultsLister) List(selector labels.Selector) (ret []*v1beta1.FooDefaults, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1beta1.FooDefaults))
+	})
+	return ret, err
+}
+
+// FooDefaults returns an object that can list and get FooDefaults.
+func (s *fooDefaultsLister) FooDefaults(namespace string) FooDefaultsNamespaceLister {
+	return fooDefaultsNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// FooDefaultsNamespaceLister helps list and get FooDefaults.
+// All objects returned here must be treated as read-only.
+type FooDefaultsNamespaceLister interface {
+	// List lists all FooDefaults in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1beta1.FooDefaults, err error)
+	// Get retrieves the FooDefaults from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1beta1.FooDefaults, error)
+	FooDefaultsNamespaceListerExpansion
+}
+
+// fooDefaultsNamespaceLister implements the FooDefaultsNamespaceLister
+// interface.
+type fooDefaultsNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all FooDefaults in the indexer for a given namespace.
+func (s fooDefaultsNamespaceLister) List(selector labels.Selector) (ret []*v1beta1.FooDefaults, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1beta1.FooDefaults))
+	})
+	return ret, err
+}
+
+// Get retrieves the FooDefaults from the indexer for a given namespace and name.
+func (s fooDefaultsNamespaceLister) Get(name string) (*v1beta1.FooDefaults, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1beta1.Resource("foodefaults"), name)
+	}
+	return obj.(*v1beta1.FooDefaults), nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1beta1 "github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// FooSummaryLister helps list FooSummaries.
+// All objects returned here must be treated as read-only.
+type FooSummaryLister interface {
+	// List lists all FooSummaries in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1beta1.FooSummary, err error)
+	// Get retrieves the FooSummary from the index for a given name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1beta1.FooSummary, error)
+	FooSummaryListerExpansion
+}
+
+// fooSummaryLister implements the FooSummaryLister interface.
+type fooSummaryLister struct {
+	indexer cache.Indexer
+}
+
+// NewFooSummaryLister returns a new FooSummaryLister.
+func NewFooSummaryLister(indexer cache.Indexer) FooSummaryLister {
+	return &fooSummaryLister{indexer: indexer}
+}
+
+// List lists all FooSummaries in the indexer.
+func (s *fooSummaryLister) List(selector labels.Selector) (ret []*v1beta1.FooSummary, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1beta1.FooSummary))
+	})
+	return ret, err
+}
+
+// Get retrieves the FooSummary from the index for a given name.
+func (s *fooSummaryLister) Get(name string) (*v1beta1.FooSummary, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1beta1.Resource("foosummary"), name)
+	}
+	return obj.(*v1beta1.FooSummary), nil
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/registry"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/generic"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+func NewREST(scheme *runtime.Scheme, opsGetter generic.RESTOptionsGetter) (*registry.REST, error) {
+	strategy := NewStrategy(scheme)
+
+	store := &genericregistry.Store{
+		NewFunc: func() runtime.Object {
+			return &demo.Config{}
+		},
+		NewListFunc: func() runtime.Object {
+			return &demo.ConfigList{}
+		},
+		PredicateFunc:             MatchConfig,
+		DefaultQualifiedResource:  demo.Resource("configs"),
+		SingularQualifiedResource: demo.Resource("config"),
+		CreateStrategy:            strategy,
+		UpdateStrategy:            strategy,
+		DeleteStrategy:            strategy,
+
+		TableConvertor: rest.NewDefaultTableConvertor(demo.Resource("configs")),
+	}
+	options := &generic.StoreOptions{RESTOptions: opsGetter, AttrFunc: GetAttrs}
+	if err := store.CompleteWithOptions(options); err != nil {
+		return nil, err
+	}
+	return &registry.REST{Store: store, ShortNameList: []string{"dcfg"}, CategoryList: []string{"demo"}}, nil
+}
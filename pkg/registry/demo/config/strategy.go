@@ -0,0 +1,91 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/utils/ptr"
+)
+
+func NewStrategy(typer runtime.ObjectTyper) configStrategy {
+	return configStrategy{typer, names.SimpleNameGenerator}
+}
+
+func GetAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
+	config, ok := obj.(*demo.Config)
+	if !ok {
+		return nil, nil, fmt.Errorf("given object is not a Config")
+	}
+	return config.ObjectMeta.Labels, SelectableFields(config), nil
+}
+
+type configStrategy struct {
+	runtime.ObjectTyper
+	names.NameGenerator
+}
+
+func SelectableFields(obj *demo.Config) fields.Set {
+	return generic.ObjectMetaFieldsSet(&obj.ObjectMeta, true)
+}
+
+func MatchConfig(label labels.Selector, field fields.Selector) storage.SelectionPredicate {
+	return storage.SelectionPredicate{
+		Label:    label,
+		Field:    field,
+		GetAttrs: GetAttrs,
+	}
+}
+
+func (configStrategy) NamespaceScoped() bool                                         { return true }
+func (configStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object)      {}
+func (configStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {}
+
+func (configStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	return field.ErrorList{}
+}
+
+func (configStrategy) Canonicalize(obj runtime.Object) {}
+
+func (configStrategy) WarningsOnCreate(ctx context.Context, obj runtime.Object) []string {
+	return nil
+}
+
+func (configStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+func (configStrategy) AllowUnconditionalUpdate() bool {
+	return false
+}
+
+func (configStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	return validateConfigUpdate(obj.(*demo.Config), old.(*demo.Config))
+}
+
+// validateConfigUpdate keeps an immutable config as it is, like the
+// validation of immutable ConfigMaps does.
+func validateConfigUpdate(config, oldConfig *demo.Config) field.ErrorList {
+	var allErrs field.ErrorList
+	if !ptr.Deref(oldConfig.Immutable, false) {
+		return allErrs
+	}
+	if !ptr.Deref(config.Immutable, false) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("immutable"), "field is immutable when `immutable` is set"))
+	}
+	if !apiequality.Semantic.DeepEqual(config.Spec, oldConfig.Spec) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "field is immutable when `immutable` is set"))
+	}
+	return allErrs
+}
+
+func (configStrategy) WarningsOnUpdate(ctx context.Context, obj, old runtime.Object) []string {
+	return nil
+}
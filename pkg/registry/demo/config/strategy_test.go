@@ -0,0 +1,83 @@
+package config
+
+import (
+	"context"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"testing"
+)
+
+func TestValidateConfigUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		immutable *bool
+		update    func(config *demo.Config)
+		// errs are the fields that may not change
+		errs []string
+	}{
+		{
+			name:   "spec of a mutable Config",
+			update: func(config *demo.Config) { config.Spec.Msg = "changed" },
+		},
+		{
+			name:      "made immutable",
+			immutable: ptr.To(false),
+			update: func(config *demo.Config) {
+				config.Spec.Msg = "changed"
+				config.Immutable = ptr.To(true)
+			},
+		},
+		{
+			name:      "spec of an immutable Config",
+			immutable: ptr.To(true),
+			update:    func(config *demo.Config) { config.Spec.Msg1 = "changed" },
+			errs:      []string{"spec"},
+		},
+		{
+			name:      "immutable unset",
+			immutable: ptr.To(true),
+			update:    func(config *demo.Config) { config.Immutable = nil },
+			errs:      []string{"immutable"},
+		},
+		{
+			name:      "immutable set to false with a new spec",
+			immutable: ptr.To(true),
+			update: func(config *demo.Config) {
+				config.Immutable = ptr.To(false)
+				config.Spec.Msg = "changed"
+			},
+			errs: []string{"immutable", "spec"},
+		},
+		{
+			name:      "metadata of an immutable Config",
+			immutable: ptr.To(true),
+			update: func(config *demo.Config) {
+				config.Labels = map[string]string{"team": "demo"}
+				config.Annotations = map[string]string{"note": "changed"}
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			old := &demo.Config{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: metav1.NamespaceDefault, ResourceVersion: "1"},
+				Spec:       demo.ConfigSpec{Msg: "hello", Msg1: "hello again"},
+				Immutable:  tc.immutable,
+			}
+			config := old.DeepCopy()
+			tc.update(config)
+
+			errs := NewStrategy(runtime.NewScheme()).ValidateUpdate(context.Background(), config, old)
+			if len(errs) != len(tc.errs) {
+				t.Fatalf("expected errors for %v, got %v", tc.errs, errs)
+			}
+			for i, err := range errs {
+				if err.Field != tc.errs[i] {
+					t.Errorf("expected an error for %s, got %v", tc.errs[i], err)
+				}
+			}
+		})
+	}
+}
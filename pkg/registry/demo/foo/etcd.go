@@ -1,16 +1,22 @@
 package foo
 
 import (
+	"context"
 	"github.com/guodoliu/apiserver/pkg/apis/demo"
 	"github.com/guodoliu/apiserver/pkg/registry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/registry/generic"
 	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
 	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
 )
 
-func NewREST(scheme *runtime.Scheme, opsGetter generic.RESTOptionsGetter) (*registry.REST, error) {
-	strategy := NewStrategy(scheme)
+// NewREST returns the storage of foos. cleanupFinalizer is passed to
+// NewStrategy.
+func NewREST(scheme *runtime.Scheme, opsGetter generic.RESTOptionsGetter, cleanupFinalizer bool) (*registry.REST, error) {
+	strategy := NewStrategy(scheme, cleanupFinalizer)
 
 	store := &genericregistry.Store{
 		NewFunc: func() runtime.Object {
@@ -25,12 +31,70 @@ func NewREST(scheme *runtime.Scheme, opsGetter generic.RESTOptionsGetter) (*regi
 		CreateStrategy:            strategy,
 		UpdateStrategy:            strategy,
 		DeleteStrategy:            strategy,
+		ResetFieldsStrategy:       strategy,
 
 		TableConvertor: rest.NewDefaultTableConvertor(demo.Resource("foos")),
 	}
-	options := &generic.StoreOptions{RESTOptions: opsGetter, AttrFunc: GetAttrs}
+	options := &generic.StoreOptions{
+		RESTOptions: opsGetter,
+		AttrFunc:    GetAttrs,
+		TriggerFunc: map[string]storage.IndexerFunc{"spec.image": ImageTriggerFunc},
+		Indexers:    Indexers(),
+	}
 	if err := store.CompleteWithOptions(options); err != nil {
 		return nil, err
 	}
-	return &registry.REST{Store: store}, nil
+	return &registry.REST{Store: store, ShortNameList: []string{"fo"}, CategoryList: []string{"demo", "all"}}, nil
+}
+
+// NewStatusREST returns a RESTStorage for foos/status that shares the store of foos.
+func NewStatusREST(scheme *runtime.Scheme, foos *registry.REST) *StatusREST {
+	statusStrategy := NewStatusStrategy(NewStrategy(scheme, false))
+	statusStore := *foos.Store
+	statusStore.UpdateStrategy = statusStrategy
+	statusStore.ResetFieldsStrategy = statusStrategy
+	// status updates never change the spec, so there is no revision to record
+	statusStore.AfterUpdate = nil
+	return &StatusREST{store: &statusStore}
+}
+
+// StatusREST implements the REST endpoint for changing the status of a foo.
+type StatusREST struct {
+	store *genericregistry.Store
+}
+
+var (
+	_ rest.Patcher             = &StatusREST{}
+	_ rest.ResetFieldsStrategy = &StatusREST{}
+)
+
+func (r *StatusREST) New() runtime.Object {
+	return &demo.Foo{}
+}
+
+// Destroy cleans up resources on shutdown.
+func (r *StatusREST) Destroy() {
+	// Given that underlying store is shared with REST,
+	// we don't destroy it here explicitly.
+}
+
+// Get retrieves the object from the storage. It is required to support Patch.
+func (r *StatusREST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	return r.store.Get(ctx, name, options)
+}
+
+// Update alters the status subset of an object.
+func (r *StatusREST) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	// We are explicitly setting forceAllowCreate to false in the call to the underlying storage because
+	// subresources should never allow create on update.
+	return r.store.Update(ctx, name, objInfo, createValidation, updateValidation, false, options)
+}
+
+// GetResetFields implements rest.ResetFieldsStrategy
+func (r *StatusREST) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	return r.store.GetResetFields()
+}
+
+func (r *StatusREST) ConvertToTable(ctx context.Context, object runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
+	return r.store.ConvertToTable(ctx, object, tableOptions)
 }
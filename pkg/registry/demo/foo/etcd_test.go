@@ -0,0 +1,260 @@
+package foo
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/install"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/registry"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/watch"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage/etcd3/testserver"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/utils/ptr"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newCachedREST returns the storage of foos on a test etcd, behind a watch
+// cache like the server sets up. cleanupFinalizer is passed to NewREST.
+func newCachedREST(b testing.TB, cleanupFinalizer bool) *registry.REST {
+	client := testserver.RunEtcd(b, nil)
+	scheme := runtime.NewScheme()
+	install.Install(scheme)
+	codecs := serializer.NewCodecFactory(scheme)
+
+	config := storagebackend.NewDefaultConfig("/registry/demo", codecs.LegacyCodec(v1alpha1.SchemeGroupVersion))
+	config.Transport.ServerList = client.Endpoints()
+	r, err := NewREST(scheme, generic.RESTOptions{
+		StorageConfig:           config.ForResource(demo.Resource("foos")),
+		Decorator:               genericregistry.StorageWithCacher(),
+		DeleteCollectionWorkers: 1,
+		ResourcePrefix:          "demo.k8s.io/foos",
+	}, cleanupFinalizer)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(r.Destroy)
+	return r
+}
+
+// image and imageLabel tell the foos apart. Watches and lists select by the
+// image through the trigger and the index, or by the label, which the watch
+// cache can only match against every foo.
+func image(i int) string      { return fmt.Sprintf("img-%d:1.0", i) }
+func imageLabel(i int) string { return fmt.Sprintf("img-%d", i) }
+
+// createFoos creates n foos in namespace default, each with its own image,
+// and returns them with the resource version of the last write.
+func createFoos(b *testing.B, r *registry.REST, n int) ([]*demo.Foo, string) {
+	ctx := genericapirequest.WithNamespace(context.Background(), metav1.NamespaceDefault)
+	foos := make([]*demo.Foo, n)
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+	for worker := 0; worker < 16; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := worker; i < n; i += 16 {
+				obj, err := r.Create(ctx, &demo.Foo{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("foo-%d", i), Labels: map[string]string{"image": imageLabel(i)}},
+					Spec:       demo.FooSpec{Image: image(i), Replicas: 1},
+				}, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+				if err != nil {
+					errs <- err
+					return
+				}
+				foos[i] = obj.(*demo.Foo)
+			}
+		}(worker)
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		b.Fatal(err)
+	}
+
+	// a list from etcd is newer than every create
+	list, err := r.List(ctx, &metainternalversion.ListOptions{Limit: 1})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return foos, list.(*demo.FooList).ResourceVersion
+}
+
+// BenchmarkFilteredWatch updates foos one at a time, each of them watched by
+// its own watcher, and measures how long the update takes to reach the
+// watcher. The watch cache hands an event for an image only to the watchers
+// of that image, so by image it stays about as fast as the foos and watchers
+// grow, whereas by label every watcher looks at every event.
+func BenchmarkFilteredWatch(b *testing.B) {
+	for _, selector := range []string{"image", "label"} {
+		for _, n := range []int{500, 2000, 4000} {
+			b.Run(fmt.Sprintf("by=%s/foos=%d", selector, n), func(b *testing.B) {
+				r := newCachedREST(b, false)
+				foos, resourceVersion := createFoos(b, r, n)
+				ctx := genericapirequest.WithNamespace(context.Background(), metav1.NamespaceDefault)
+
+				watchFoo := func(i int, resourceVersion string) watch.Interface {
+					options := &metainternalversion.ListOptions{ResourceVersion: resourceVersion}
+					if selector == "image" {
+						options.FieldSelector = fields.OneTermEqualSelector("spec.image", image(i))
+					} else {
+						options.LabelSelector = labels.SelectorFromSet(labels.Set{"image": imageLabel(i)})
+					}
+					w, err := r.Watch(ctx, options)
+					if err != nil {
+						b.Fatal(err)
+					}
+					return w
+				}
+				watchers := make([]watch.Interface, n)
+				for i := range watchers {
+					watchers[i] = watchFoo(i, resourceVersion)
+				}
+				defer func() {
+					for _, w := range watchers {
+						w.Stop()
+					}
+				}()
+
+				// the watch cache closes watchers that fall behind, which
+				// busy watchers by label do; they are watched again
+				rewatches := 0
+				b.ResetTimer()
+				for op := 0; op < b.N; op++ {
+					i := op % n
+					foo := foos[i].DeepCopy()
+					foo.Spec.Config.Msg = strconv.Itoa(op)
+					obj, _, err := r.Update(ctx, foo.Name, rest.DefaultUpdatedObjectInfo(foo), rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+					if err != nil {
+						b.Fatal(err)
+					}
+					foos[i] = obj.(*demo.Foo)
+					for received := false; !received; {
+						select {
+						case event, ok := <-watchers[i].ResultChan():
+							if !ok {
+								// from just before the update, which the
+								// watch cache still holds
+								rewatches++
+								updated, _ := strconv.ParseUint(foos[i].ResourceVersion, 10, 64)
+								watchers[i] = watchFoo(i, strconv.FormatUint(updated-1, 10))
+								continue
+							}
+							// the watch cache wraps what it sends
+							if accessor, err := meta.Accessor(event.Object); err != nil || event.Type != watch.Modified || accessor.GetName() != foo.Name {
+								b.Fatalf("expected foo %s to be modified, got %s of %v", foo.Name, event.Type, event.Object)
+							}
+							received = true
+						case <-time.After(time.Minute):
+							b.Fatalf("the update of foo %s did not reach its watcher", foo.Name)
+						}
+					}
+				}
+				b.ReportMetric(float64(rewatches)/float64(b.N), "rewatches/op")
+			})
+		}
+	}
+}
+
+// BenchmarkFilteredList lists the foo of one image from the watch cache,
+// which looks it up in the image index, or the foo of one label, which it
+// finds by matching every foo.
+func BenchmarkFilteredList(b *testing.B) {
+	for _, selector := range []string{"image", "label"} {
+		for _, n := range []int{500, 2000, 4000} {
+			b.Run(fmt.Sprintf("by=%s/foos=%d", selector, n), func(b *testing.B) {
+				r := newCachedREST(b, false)
+				_, resourceVersion := createFoos(b, r, n)
+				ctx := genericapirequest.WithNamespace(context.Background(), metav1.NamespaceDefault)
+
+				b.ResetTimer()
+				for op := 0; op < b.N; op++ {
+					i := op % n
+					// the watch cache serves lists not older than the creates
+					options := &metainternalversion.ListOptions{ResourceVersion: resourceVersion}
+					if selector == "image" {
+						options.FieldSelector = fields.OneTermEqualSelector("spec.image", image(i))
+					} else {
+						options.LabelSelector = labels.SelectorFromSet(labels.Set{"image": imageLabel(i)})
+					}
+					list, err := r.List(ctx, options)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if items := list.(*demo.FooList).Items; len(items) != 1 {
+						b.Fatalf("expected one foo with image %s, got %d", image(i), len(items))
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestGracefulDelete deletes foos with spec.terminationGracePeriodSeconds.
+// Only a foo a controller looks after, through the cleanup finalizer, waits
+// for its grace period, which the controller ends.
+func TestGracefulDelete(t *testing.T) {
+	for _, cleanupFinalizer := range []bool{false, true} {
+		t.Run(fmt.Sprintf("cleanupFinalizer=%v", cleanupFinalizer), func(t *testing.T) {
+			r := newCachedREST(t, cleanupFinalizer)
+			ctx := genericapirequest.WithNamespace(context.Background(), metav1.NamespaceDefault)
+			grace := int64(30)
+			if _, err := r.Create(ctx, &demo.Foo{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec:       demo.FooSpec{Image: "busybox:1.36", Replicas: 1, TerminationGracePeriodSeconds: &grace},
+			}, rest.ValidateAllObjectFunc, &metav1.CreateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+
+			_, deleted, err := r.Delete(ctx, "foo", rest.ValidateAllObjectFunc, &metav1.DeleteOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if deleted == cleanupFinalizer {
+				t.Fatalf("expected the foo to be deleted right away: %v, got %v", !cleanupFinalizer, deleted)
+			}
+			obj, err := r.Get(ctx, "foo", &metav1.GetOptions{})
+			if !cleanupFinalizer {
+				if !apierrors.IsNotFound(err) {
+					t.Fatalf("expected the foo to be gone, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			foo := obj.(*demo.Foo)
+			if foo.DeletionTimestamp == nil || ptr.Deref(foo.DeletionGracePeriodSeconds, 0) != grace || foo.Status.Phase != demo.FooPhaseTerminating {
+				t.Fatalf("expected the foo to be Terminating for %ds, got %v", grace, foo.ObjectMeta)
+			}
+
+			// what the controller does once it cleaned up and the grace period is over
+			foo.Finalizers = nil
+			if _, _, err := r.Update(ctx, "foo", rest.DefaultUpdatedObjectInfo(foo), rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.Get(ctx, "foo", &metav1.GetOptions{}); err != nil {
+				t.Fatalf("expected the foo to stay for its grace period, got %v", err)
+			}
+			if _, deleted, err := r.Delete(ctx, "foo", rest.ValidateAllObjectFunc, &metav1.DeleteOptions{GracePeriodSeconds: ptr.To[int64](0)}); err != nil || !deleted {
+				t.Fatalf("expected the foo to be deleted, got %v, %v", deleted, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package foo
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"github.com/guodoliu/apiserver/pkg/registry"
+	"hash/fnv"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RevisionFooLabel is set on every FooRevision to the name of its Foo,
+// shortened to the length label values allow
+const RevisionFooLabel = "demo.k8s.io/foo"
+
+const revisionHookTimeout = 10 * time.Second
+
+// NewHistoryStorage records a FooRevision in revisions whenever a foo is
+// created with or updated to a new spec, keeping the newest historyLimit
+// revisions of every foo. It returns the storage of foos/history and
+// foos/rollback.
+func NewHistoryStorage(foos, revisions *registry.REST, historyLimit int) (*HistoryREST, *RollbackREST) {
+	h := &revisionHistory{revisions: revisions.Store, limit: historyLimit}
+	foos.Store.AfterCreate = func(obj runtime.Object, options *metav1.CreateOptions) {
+		if len(options.DryRun) > 0 {
+			return
+		}
+		foo := obj.(*demo.Foo)
+		ctx, cancel := hookContext(foo)
+		defer cancel()
+		// revisions of an earlier foo of the same name would clash
+		if err := h.deleteAll(ctx, foo); err != nil {
+			klog.ErrorS(err, "Failed to delete stale foo revisions", "foo", klog.KObj(foo))
+		}
+		if err := h.record(ctx, foo); err != nil {
+			klog.ErrorS(err, "Failed to record foo revision", "foo", klog.KObj(foo))
+		}
+	}
+	foos.Store.AfterUpdate = func(obj runtime.Object, options *metav1.UpdateOptions) {
+		if len(options.DryRun) > 0 {
+			return
+		}
+		foo := obj.(*demo.Foo)
+		ctx, cancel := hookContext(foo)
+		defer cancel()
+		if err := h.record(ctx, foo); err != nil {
+			klog.ErrorS(err, "Failed to record foo revision", "foo", klog.KObj(foo))
+		}
+	}
+	foos.Store.AfterDelete = func(obj runtime.Object, options *metav1.DeleteOptions) {
+		if len(options.DryRun) > 0 {
+			return
+		}
+		foo := obj.(*demo.Foo)
+		ctx, cancel := hookContext(foo)
+		defer cancel()
+		if err := h.deleteAll(ctx, foo); err != nil {
+			klog.ErrorS(err, "Failed to delete foo revisions", "foo", klog.KObj(foo))
+		}
+	}
+	return &HistoryREST{foos: foos.Store, history: h}, &RollbackREST{foos: foos.Store, history: h}
+}
+
+// hookContext is the context of the store hooks, which run after the
+// request has been answered by storage and are not passed its context.
+func hookContext(foo *demo.Foo) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(genericapirequest.WithNamespace(context.Background(), foo.Namespace), revisionHookTimeout)
+}
+
+type revisionHistory struct {
+	revisions *genericregistry.Store
+	limit     int
+}
+
+// list returns the revisions of foo, oldest first.
+func (h *revisionHistory) list(ctx context.Context, foo *demo.Foo) (*demo.FooRevisionList, error) {
+	obj, err := h.revisions.List(ctx, &metainternalversion.ListOptions{
+		LabelSelector: revisionSelector(foo),
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := obj.(*demo.FooRevisionList)
+	items := list.Items[:0]
+	for _, revision := range list.Items {
+		if metav1.IsControlledBy(&revision, foo) {
+			items = append(items, revision)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Revision < items[j].Revision
+	})
+	list.Items = items
+	return list, nil
+}
+
+// record adds a revision for the spec of foo unless it is the spec of the
+// newest revision, then drops revisions beyond the limit. Scaling a foo does
+// not add a revision. Concurrent updates of a foo pick the same revision
+// number, so the ones that lose the race look at the history again.
+func (h *revisionHistory) record(ctx context.Context, foo *demo.Foo) error {
+	return retry.OnError(retry.DefaultRetry, errors.IsAlreadyExists, func() error {
+		return h.recordOnce(ctx, foo)
+	})
+}
+
+func (h *revisionHistory) recordOnce(ctx context.Context, foo *demo.Foo) error {
+	list, err := h.list(ctx, foo)
+	if err != nil {
+		return err
+	}
+	revisions := list.Items
+	next := int64(1)
+	if n := len(revisions); n > 0 {
+		newest := revisions[n-1].Spec
+		newest.Replicas = foo.Spec.Replicas
+		if apiequality.Semantic.DeepEqual(newest, foo.Spec) {
+			return nil
+		}
+		next = revisions[n-1].Revision + 1
+	}
+
+	revision := &demo.FooRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            revisionName(foo.Name, next),
+			Namespace:       foo.Namespace,
+			Labels:          map[string]string{RevisionFooLabel: shortName(foo.Name, validation.LabelValueMaxLength)},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(foo, v1beta1.SchemeGroupVersion.WithKind("Foo"))},
+		},
+		Revision: next,
+		Spec:     *foo.Spec.DeepCopy(),
+	}
+	if _, err := h.revisions.Create(ctx, revision, rest.ValidateAllObjectFunc, &metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	revisions = append(revisions, *revision)
+
+	for i := 0; i < len(revisions)-h.limit; i++ {
+		if err := h.delete(ctx, revisions[i].Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revisionName returns <foo>-<revision>, shortening the name of the foo if
+// the result would be too long for an object name.
+func revisionName(fooName string, revision int64) string {
+	suffix := fmt.Sprintf("-%d", revision)
+	return shortName(fooName, validation.DNS1123SubdomainMaxLength-len(suffix)) + suffix
+}
+
+func revisionSelector(foo *demo.Foo) labels.Selector {
+	return labels.SelectorFromSet(labels.Set{RevisionFooLabel: shortName(foo.Name, validation.LabelValueMaxLength)})
+}
+
+// shortName returns name if it has at most max characters. Longer names are
+// cut and end with a hash of the whole name, so that names sharing a prefix
+// stay apart.
+func shortName(name string, max int) string {
+	if len(name) <= max {
+		return name
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	sum := fmt.Sprintf("%08x", hash.Sum32())
+	return strings.TrimRight(name[:max-len(sum)-1], ".-") + "-" + sum
+}
+
+func (h *revisionHistory) deleteAll(ctx context.Context, foo *demo.Foo) error {
+	obj, err := h.revisions.List(ctx, &metainternalversion.ListOptions{
+		LabelSelector: revisionSelector(foo),
+	})
+	if err != nil {
+		return err
+	}
+	for _, revision := range obj.(*demo.FooRevisionList).Items {
+		if err := h.delete(ctx, revision.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *revisionHistory) delete(ctx context.Context, name string) error {
+	_, _, err := h.revisions.Delete(ctx, name, rest.ValidateAllObjectFunc, &metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// HistoryREST implements the foos/history subresource, which lists the
+// revisions of a foo.
+type HistoryREST struct {
+	foos    *genericregistry.Store
+	history *revisionHistory
+}
+
+var _ rest.Getter = &HistoryREST{}
+
+func (r *HistoryREST) New() runtime.Object {
+	return &demo.FooRevisionList{}
+}
+
+// Destroy cleans up resources on shutdown.
+func (r *HistoryREST) Destroy() {
+	// Given that underlying stores are shared with other storage,
+	// we don't destroy them here explicitly.
+}
+
+func (r *HistoryREST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	obj, err := r.foos.Get(ctx, name, options)
+	if err != nil {
+		return nil, err
+	}
+	return r.history.list(ctx, obj.(*demo.Foo))
+}
+
+// RollbackREST implements the foos/rollback subresource, which restores the
+// spec of a foo from one of its revisions.
+type RollbackREST struct {
+	foos    *genericregistry.Store
+	history *revisionHistory
+}
+
+var _ rest.NamedCreater = &RollbackREST{}
+
+func (r *RollbackREST) New() runtime.Object {
+	return &demo.FooRollback{}
+}
+
+// Destroy cleans up resources on shutdown.
+func (r *RollbackREST) Destroy() {
+	// Given that underlying stores are shared with other storage,
+	// we don't destroy them here explicitly.
+}
+
+// Create updates the foo with the spec of the requested revision, except for
+// the replicas which are kept. The update goes through the foo strategy like
+// any other, and records a new revision.
+func (r *RollbackREST) Create(ctx context.Context, name string, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	rollback, ok := obj.(*demo.FooRollback)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("not a FooRollback: %T", obj))
+	}
+	if len(rollback.Name) > 0 && rollback.Name != name {
+		return nil, errors.NewBadRequest("name in URL does not match name in FooRollback object")
+	}
+	if rollback.Revision < 1 {
+		return nil, errors.NewInvalid(demo.Kind("FooRollback"), name, field.ErrorList{
+			field.Invalid(field.NewPath("revision"), rollback.Revision, "must be greater than 0"),
+		})
+	}
+	if createValidation != nil {
+		if err := createValidation(ctx, rollback.DeepCopyObject()); err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := r.foos.Get(ctx, name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	foo := current.(*demo.Foo)
+	list, err := r.history.list(ctx, foo)
+	if err != nil {
+		return nil, err
+	}
+	var target *demo.FooRevision
+	for i := range list.Items {
+		if list.Items[i].Revision == rollback.Revision {
+			target = &list.Items[i]
+		}
+	}
+	if target == nil {
+		return nil, errors.NewNotFound(demo.Resource("foorevisions"), revisionName(name, rollback.Revision))
+	}
+
+	updated, _, err := r.foos.Update(ctx, name, rest.DefaultUpdatedObjectInfo(nil, func(ctx context.Context, _, oldObj runtime.Object) (runtime.Object, error) {
+		old := oldObj.(*demo.Foo)
+		if old.UID != foo.UID {
+			return nil, errors.NewConflict(demo.Resource("foos"), name, fmt.Errorf("the foo was replaced during the rollback"))
+		}
+		restored := old.DeepCopy()
+		restored.Spec = *target.Spec.DeepCopy()
+		restored.Spec.Replicas = old.Spec.Replicas
+		return restored, nil
+	}), rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{
+		DryRun:       options.DryRun,
+		FieldManager: options.FieldManager,
+	})
+	return updated, err
+}
@@ -0,0 +1,40 @@
+package foo
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation"
+	"strings"
+	"testing"
+)
+
+func TestRevisionName(t *testing.T) {
+	long := strings.Repeat("a", validation.DNS1123SubdomainMaxLength)
+	tests := []struct {
+		name     string
+		fooName  string
+		revision int64
+		want     string
+	}{
+		{name: "short", fooName: "foo", revision: 3, want: "foo-3"},
+		{name: "fits exactly", fooName: long[:251], revision: 1, want: long[:251] + "-1"},
+		{name: "too long", fooName: long, revision: 12},
+		{name: "too long with a dot where it is cut", fooName: long[:241] + "." + long[:12], revision: 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := revisionName(tc.fooName, tc.revision)
+			if len(tc.want) > 0 && got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+			if errs := validation.IsDNS1123Subdomain(got); len(errs) > 0 {
+				t.Errorf("%q is not a valid name: %v", got, errs)
+			}
+			if label := shortName(tc.fooName, validation.LabelValueMaxLength); len(validation.IsValidLabelValue(label)) > 0 {
+				t.Errorf("%q is not a valid label value: %v", label, validation.IsValidLabelValue(label))
+			}
+		})
+	}
+
+	if revisionName(long, 1) == revisionName(long[:252]+"b", 1) {
+		t.Errorf("long names that only differ at the end got the same revision name")
+	}
+}
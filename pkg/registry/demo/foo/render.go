@@ -0,0 +1,109 @@
+package foo
+
+import (
+	"context"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	foocontroller "github.com/guodoliu/apiserver/pkg/controller/foo"
+	"github.com/guodoliu/apiserver/pkg/registry"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+// NewRenderREST returns the storage of foos/render. configs is nil unless
+// Configs are served.
+func NewRenderREST(scheme *runtime.Scheme, foos, configs *registry.REST) *RenderREST {
+	r := &RenderREST{scheme: scheme, foos: foos.Store}
+	if configs != nil {
+		r.configs = configs.Store
+	}
+	return r
+}
+
+// RenderREST implements the foos/render subresource. It builds the objects
+// of a foo with the same functions the foo controller uses, so what it
+// returns is what the controller applies.
+type RenderREST struct {
+	scheme  *runtime.Scheme
+	foos    *genericregistry.Store
+	configs *genericregistry.Store
+}
+
+var _ rest.Getter = &RenderREST{}
+
+func (r *RenderREST) New() runtime.Object {
+	return &demo.FooRendering{}
+}
+
+// Destroy cleans up resources on shutdown.
+func (r *RenderREST) Destroy() {
+	// Given that underlying store is shared with REST,
+	// we don't destroy it here explicitly.
+}
+
+func (r *RenderREST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	obj, err := r.foos.Get(ctx, name, options)
+	if err != nil {
+		return nil, err
+	}
+	foo := obj.(*demo.Foo)
+	// the controller works on v1beta1
+	versioned := &v1beta1.Foo{}
+	if err := r.scheme.Convert(foo, versioned, nil); err != nil {
+		return nil, err
+	}
+
+	rendering := &demo.FooRendering{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            foo.Name,
+			Namespace:       foo.Namespace,
+			UID:             foo.UID,
+			ResourceVersion: foo.ResourceVersion,
+		},
+		Spec: foo.Spec,
+	}
+	// like the controller, leave out the ConfigMap while the Config is missing
+	var objects []runtime.Object
+	config, err := r.config(ctx, foo)
+	switch {
+	case err == nil:
+		objects = append(objects, foocontroller.NewConfigMap(versioned, config))
+	case !errors.IsNotFound(err):
+		return nil, err
+	}
+	objects = append(objects, foocontroller.NewDeployment(versioned))
+
+	codec := kubescheme.Codecs.LegacyCodec(corev1.SchemeGroupVersion, appsv1.SchemeGroupVersion)
+	for _, object := range objects {
+		data, err := runtime.Encode(codec, object)
+		if err != nil {
+			return nil, err
+		}
+		rendering.Objects = append(rendering.Objects, runtime.RawExtension{Raw: data})
+	}
+	return rendering, nil
+}
+
+// config returns the Config foo refers to, or nil if it refers to none or
+// Configs are not served.
+func (r *RenderREST) config(ctx context.Context, foo *demo.Foo) (*v1alpha1.Config, error) {
+	if len(foo.Spec.ConfigName) == 0 || r.configs == nil {
+		return nil, nil
+	}
+	obj, err := r.configs.Get(ctx, foo.Spec.ConfigName, &metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	config := &v1alpha1.Config{}
+	if err := r.scheme.Convert(obj, config, nil); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
@@ -0,0 +1,172 @@
+package foo
+
+import (
+	"context"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/install"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	foocontroller "github.com/guodoliu/apiserver/pkg/controller/foo"
+	"github.com/guodoliu/apiserver/pkg/features"
+	configstorage "github.com/guodoliu/apiserver/pkg/registry/demo/config"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage/etcd3/testserver"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ConfigResource, true)
+
+	client := testserver.RunEtcd(t, nil)
+	scheme := runtime.NewScheme()
+	install.Install(scheme)
+	codecs := serializer.NewCodecFactory(scheme)
+	config := storagebackend.NewDefaultConfig("/registry/demo", codecs.LegacyCodec(v1alpha1.SchemeGroupVersion))
+	config.Transport.ServerList = client.Endpoints()
+	restOptions := func(resource string) generic.RESTOptions {
+		return generic.RESTOptions{
+			StorageConfig:           config.ForResource(demo.Resource(resource)),
+			Decorator:               generic.UndecoratedStorage,
+			DeleteCollectionWorkers: 1,
+			ResourcePrefix:          "demo.k8s.io/" + resource,
+		}
+	}
+	foos, err := NewREST(scheme, restOptions("foos"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(foos.Destroy)
+	configs, err := configstorage.NewREST(scheme, restOptions("configs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(configs.Destroy)
+	r := NewRenderREST(scheme, foos, configs)
+
+	ctx := genericapirequest.WithNamespace(context.Background(), metav1.NamespaceDefault)
+	_, err = configs.Create(ctx, &demo.Config{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+		Spec:       demo.ConfigSpec{Msg: "from the config", Msg1: "shared"},
+	}, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		spec v1beta1.FooSpec
+		// wantData is the data of the ConfigMap, nil if there is none
+		wantData     map[string]string
+		wantReplicas int32
+	}{
+		{
+			name:         "defaulting only",
+			spec:         v1beta1.FooSpec{Image: "busybox:1.36"},
+			wantData:     map[string]string{"msg": "", "msg1": ""},
+			wantReplicas: 1,
+		},
+		{
+			name:         "inline config",
+			spec:         v1beta1.FooSpec{Image: "busybox:1.36", Config: v1beta1.FooConfig{Msg: "hello", Msg1: "world"}, Replicas: ptr.To[int32](3)},
+			wantData:     map[string]string{"msg": "hello", "msg1": "world"},
+			wantReplicas: 3,
+		},
+		{
+			name:         "config present",
+			spec:         v1beta1.FooSpec{Image: "busybox:1.36", Config: v1beta1.FooConfig{Msg: "inline"}, ConfigName: "shared"},
+			wantData:     map[string]string{"msg": "from the config", "msg1": "shared"},
+			wantReplicas: 1,
+		},
+		{
+			name:         "config missing",
+			spec:         v1beta1.FooSpec{Image: "busybox:1.36", ConfigName: "missing"},
+			wantReplicas: 1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// create the foo the way the server does: defaulted in v1beta1
+			versioned := &v1beta1.Foo{ObjectMeta: metav1.ObjectMeta{Name: strings.ReplaceAll(tc.name, " ", "-")}, Spec: tc.spec}
+			scheme.Default(versioned)
+			foo := &demo.Foo{}
+			if err := scheme.Convert(versioned, foo, nil); err != nil {
+				t.Fatal(err)
+			}
+			obj, err := foos.Create(ctx, foo, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			created := obj.(*demo.Foo)
+
+			obj, err = r.Get(ctx, created.Name, &metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			rendering := obj.(*demo.FooRendering)
+			if rendering.Name != created.Name || rendering.UID != created.UID || rendering.ResourceVersion != created.ResourceVersion {
+				t.Errorf("expected the rendering to be of %s/%s at %s, got %+v", created.Name, created.UID, created.ResourceVersion, rendering.ObjectMeta)
+			}
+
+			var configMap *corev1.ConfigMap
+			var deployment *appsv1.Deployment
+			for _, raw := range rendering.Objects {
+				object, _, err := kubescheme.Codecs.UniversalDeserializer().Decode(raw.Raw, nil, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				switch object := object.(type) {
+				case *corev1.ConfigMap:
+					configMap = object
+				case *appsv1.Deployment:
+					deployment = object
+				default:
+					t.Fatalf("unexpected object %T", object)
+				}
+				accessor := object.(metav1.Object)
+				if owners := accessor.GetOwnerReferences(); len(owners) != 1 || owners[0].UID != created.UID || owners[0].Controller == nil || !*owners[0].Controller {
+					t.Errorf("expected %T to be controlled by the foo, got %+v", object, owners)
+				}
+			}
+
+			switch {
+			case tc.wantData == nil && configMap != nil:
+				t.Errorf("expected no ConfigMap while the Config is missing, got %+v", configMap)
+			case tc.wantData != nil && configMap == nil:
+				t.Errorf("expected a ConfigMap")
+			case tc.wantData != nil && !maps.Equal(configMap.Data, tc.wantData):
+				t.Errorf("expected ConfigMap data %v, got %v", tc.wantData, configMap.Data)
+			}
+
+			if deployment == nil {
+				t.Fatal("expected a Deployment")
+			}
+			if got := *deployment.Spec.Replicas; got != tc.wantReplicas {
+				t.Errorf("expected %d replicas, got %d", tc.wantReplicas, got)
+			}
+			container := deployment.Spec.Template.Spec.Containers[0]
+			if container.Image != tc.spec.Image {
+				t.Errorf("expected image %s, got %s", tc.spec.Image, container.Image)
+			}
+			if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].MountPath != foocontroller.ConfigMountPath {
+				t.Errorf("expected the ConfigMap mounted at %s, got %+v", foocontroller.ConfigMountPath, container.VolumeMounts)
+			}
+			if name := deployment.Spec.Template.Spec.Volumes[0].ConfigMap.Name; name != created.Name {
+				t.Errorf("expected the volume of ConfigMap %s, got %s", created.Name, name)
+			}
+		})
+	}
+}
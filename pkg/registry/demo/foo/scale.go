@@ -0,0 +1,176 @@
+package foo
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/registry"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	autoscaling "k8s.io/client-go/scale/scheme"
+)
+
+// NewScaleREST returns the storage of foos/scale, which shares the store of foos.
+func NewScaleREST(foos *registry.REST) *ScaleREST {
+	return &ScaleREST{store: foos.Store}
+}
+
+// ScaleREST implements the foos/scale subresource. It serves autoscaling/v1
+// Scale, so kubectl scale and the HorizontalPodAutoscaler can drive the
+// replicas of a foo.
+type ScaleREST struct {
+	store *genericregistry.Store
+}
+
+var (
+	_ rest.Patcher                  = &ScaleREST{}
+	_ rest.GroupVersionKindProvider = &ScaleREST{}
+)
+
+func (r *ScaleREST) GroupVersionKind(containingGV schema.GroupVersion) schema.GroupVersionKind {
+	return autoscalingv1.SchemeGroupVersion.WithKind("Scale")
+}
+
+func (r *ScaleREST) New() runtime.Object {
+	return &autoscaling.Scale{}
+}
+
+// Destroy cleans up resources on shutdown.
+func (r *ScaleREST) Destroy() {
+	// Given that underlying store is shared with REST,
+	// we don't destroy it here explicitly.
+}
+
+func (r *ScaleREST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	obj, err := r.store.Get(ctx, name, options)
+	if err != nil {
+		return nil, err
+	}
+	return scaleFromFoo(obj.(*demo.Foo))
+}
+
+// Update sets the replicas of the foo from the updated scale. The update goes
+// through the foo strategy like any other.
+func (r *ScaleREST) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	obj, _, err := r.store.Update(ctx, name, &scaleUpdatedObjectInfo{name: name, reqObjInfo: objInfo},
+		toScaleCreateValidation(createValidation),
+		toScaleUpdateValidation(updateValidation),
+		false, options)
+	if err != nil {
+		return nil, false, err
+	}
+	scale, err := scaleFromFoo(obj.(*demo.Foo))
+	if err != nil {
+		return nil, false, errors.NewBadRequest(fmt.Sprintf("%v", err))
+	}
+	return scale, false, nil
+}
+
+func (r *ScaleREST) ConvertToTable(ctx context.Context, object runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
+	return rest.NewDefaultTableConvertor(demo.Resource("foos/scale")).ConvertToTable(ctx, object, tableOptions)
+}
+
+// admission and validation of the scale subresource see the scale, not the foo
+func toScaleCreateValidation(f rest.ValidateObjectFunc) rest.ValidateObjectFunc {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, obj runtime.Object) error {
+		scale, err := scaleFromFoo(obj.(*demo.Foo))
+		if err != nil {
+			return err
+		}
+		return f(ctx, scale)
+	}
+}
+
+func toScaleUpdateValidation(f rest.ValidateObjectUpdateFunc) rest.ValidateObjectUpdateFunc {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, obj, old runtime.Object) error {
+		newScale, err := scaleFromFoo(obj.(*demo.Foo))
+		if err != nil {
+			return err
+		}
+		oldScale, err := scaleFromFoo(old.(*demo.Foo))
+		if err != nil {
+			return err
+		}
+		return f(ctx, newScale, oldScale)
+	}
+}
+
+func scaleFromFoo(foo *demo.Foo) (*autoscaling.Scale, error) {
+	var selector *metav1.LabelSelector
+	if len(foo.Status.Selector) > 0 {
+		var err error
+		if selector, err = metav1.ParseToLabelSelector(foo.Status.Selector); err != nil {
+			return nil, err
+		}
+	}
+	return &autoscaling.Scale{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              foo.Name,
+			Namespace:         foo.Namespace,
+			UID:               foo.UID,
+			ResourceVersion:   foo.ResourceVersion,
+			CreationTimestamp: foo.CreationTimestamp,
+		},
+		Spec: autoscaling.ScaleSpec{
+			Replicas: foo.Spec.Replicas,
+		},
+		Status: autoscaling.ScaleStatus{
+			Replicas: foo.Status.Replicas,
+			Selector: selector,
+		},
+	}, nil
+}
+
+// scaleUpdatedObjectInfo turns the updated scale of a request into the
+// updated foo.
+type scaleUpdatedObjectInfo struct {
+	name       string
+	reqObjInfo rest.UpdatedObjectInfo
+}
+
+func (i *scaleUpdatedObjectInfo) Preconditions() *metav1.Preconditions {
+	return i.reqObjInfo.Preconditions()
+}
+
+func (i *scaleUpdatedObjectInfo) UpdatedObject(ctx context.Context, oldObj runtime.Object) (runtime.Object, error) {
+	foo := oldObj.DeepCopyObject().(*demo.Foo)
+	oldScale, err := scaleFromFoo(foo)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := i.reqObjInfo.UpdatedObject(ctx, oldScale)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, errors.NewBadRequest("nil update passed to Scale")
+	}
+	scale, ok := obj.(*autoscaling.Scale)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected input object type to be Scale, but %T", obj))
+	}
+	if errs := apivalidation.ValidateNonnegativeField(int64(scale.Spec.Replicas), field.NewPath("spec", "replicas")); len(errs) > 0 {
+		return nil, errors.NewInvalid(autoscaling.Kind("Scale"), i.name, errs)
+	}
+
+	foo.Spec.Replicas = scale.Spec.Replicas
+	// a resource version in the scale makes the update conditional
+	if len(scale.ResourceVersion) > 0 {
+		foo.ResourceVersion = scale.ResourceVersion
+	}
+	return foo, nil
+}
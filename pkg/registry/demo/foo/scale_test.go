@@ -0,0 +1,128 @@
+package foo
+
+import (
+	"context"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	autoscaling "k8s.io/client-go/scale/scheme"
+	"testing"
+)
+
+func TestScale(t *testing.T) {
+	foos := newCachedREST(t, false)
+	r := NewScaleREST(foos)
+	ctx := genericapirequest.WithNamespace(context.Background(), metav1.NamespaceDefault)
+	obj, err := foos.Create(ctx, &demo.Foo{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec:       demo.FooSpec{Image: "busybox:1.36", Replicas: 1},
+	}, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	foo := obj.(*demo.Foo)
+
+	obj, err = r.Get(ctx, "foo", &metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scale := obj.(*autoscaling.Scale)
+	if scale.Name != foo.Name || scale.UID != foo.UID || scale.ResourceVersion != foo.ResourceVersion || scale.Spec.Replicas != 1 {
+		t.Errorf("expected the scale of the foo with 1 replica, got %+v", scale)
+	}
+
+	update := func(scale *autoscaling.Scale) (*autoscaling.Scale, error) {
+		obj, _, err := r.Update(ctx, "foo", rest.DefaultUpdatedObjectInfo(scale), rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return obj.(*autoscaling.Scale), nil
+	}
+
+	scaled := scale.DeepCopy()
+	scaled.Spec.Replicas = 3
+	got, err := update(scaled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Spec.Replicas != 3 || got.ResourceVersion == scale.ResourceVersion {
+		t.Errorf("expected the scale to be updated to 3 replicas, got %+v", got)
+	}
+	obj, err = foos.Get(ctx, "foo", &metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replicas := obj.(*demo.Foo).Spec.Replicas; replicas != 3 {
+		t.Errorf("expected the foo to have 3 replicas, got %d", replicas)
+	}
+
+	negative := got.DeepCopy()
+	negative.Spec.Replicas = -1
+	if _, err := update(negative); !apierrors.IsInvalid(err) {
+		t.Errorf("expected negative replicas to be invalid, got %v", err)
+	}
+
+	// the scale read before the last update is stale
+	stale := scale.DeepCopy()
+	stale.Spec.Replicas = 5
+	if _, err := update(stale); !apierrors.IsConflict(err) {
+		t.Errorf("expected a stale resource version to conflict, got %v", err)
+	}
+	// without a resource version the update is unconditional
+	stale.ResourceVersion = ""
+	if got, err := update(stale); err != nil || got.Spec.Replicas != 5 {
+		t.Errorf("expected an unconditional update to 5 replicas, got %+v, %v", got, err)
+	}
+
+	if _, err := r.Get(ctx, "missing", &metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the scale of a missing foo to be not found, got %v", err)
+	}
+}
+
+func TestScaleFromFoo(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		want     *metav1.LabelSelector
+		wantErr  bool
+	}{
+		{name: "no selector"},
+		{
+			name:     "equality",
+			selector: "demo.k8s.io/foo=foo",
+			want:     &metav1.LabelSelector{MatchLabels: map[string]string{"demo.k8s.io/foo": "foo"}},
+		},
+		{
+			name:     "set based",
+			selector: "tier in (a,b)",
+			want: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}},
+			}},
+		},
+		{name: "invalid", selector: "tier in (a", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scale, err := scaleFromFoo(&demo.Foo{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec:       demo.FooSpec{Replicas: 2},
+				Status:     demo.FooStatus{Replicas: 1, Selector: tc.selector},
+			})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if scale.Spec.Replicas != 2 || scale.Status.Replicas != 1 {
+				t.Errorf("expected 2 desired and 1 current replicas, got %+v", scale)
+			}
+			if !equality.Semantic.DeepEqual(scale.Status.Selector, tc.want) {
+				t.Errorf("expected selector %+v, got %+v", tc.want, scale.Status.Selector)
+			}
+		})
+	}
+}
@@ -4,17 +4,32 @@ import (
 	"context"
 	"fmt"
 	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1alpha1"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	"github.com/guodoliu/apiserver/pkg/features"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/registry/rest"
 	"k8s.io/apiserver/pkg/storage"
 	"k8s.io/apiserver/pkg/storage/names"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"slices"
 )
 
-func NewStrategy(typer runtime.ObjectTyper) fooStrategy {
-	return fooStrategy{typer, names.SimpleNameGenerator}
+var _ rest.RESTGracefulDeleteStrategy = fooStrategy{}
+
+// NewStrategy returns the strategy of foos. cleanupFinalizer adds
+// demo.FooCleanupFinalizer to new foos; only set it when a controller that
+// removes the finalizer runs, or foos can never be deleted.
+func NewStrategy(typer runtime.ObjectTyper, cleanupFinalizer bool) fooStrategy {
+	return fooStrategy{typer, names.SimpleNameGenerator, cleanupFinalizer}
 }
 
 func GetAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
@@ -28,32 +43,150 @@ func GetAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
 type fooStrategy struct {
 	runtime.ObjectTyper
 	names.NameGenerator
+
+	cleanupFinalizer bool
 }
 
 func SelectableFields(obj *demo.Foo) fields.Set {
-	return generic.ObjectMetaFieldsSet(&obj.ObjectMeta, true)
+	fooSpecificFieldsSet := fields.Set{
+		"spec.image":   obj.Spec.Image,
+		"status.phase": string(obj.Status.Phase),
+	}
+	return generic.AddObjectMetaFieldsSet(fooSpecificFieldsSet, &obj.ObjectMeta, true)
 }
 
 func MatchFoo(label labels.Selector, field fields.Selector) storage.SelectionPredicate {
 	return storage.SelectionPredicate{
-		Label:    label,
-		Field:    field,
-		GetAttrs: GetAttrs,
+		Label:       label,
+		Field:       field,
+		GetAttrs:    GetAttrs,
+		IndexFields: []string{"metadata.namespace", "spec.image", "status.phase"},
+	}
+}
+
+// Indexers lets the watch cache answer lists by namespace, image or phase
+// without looking at every foo.
+func Indexers() *cache.Indexers {
+	return &cache.Indexers{
+		storage.FieldIndex("metadata.namespace"): NamespaceIndexFunc,
+		storage.FieldIndex("spec.image"):         ImageIndexFunc,
+		storage.FieldIndex("status.phase"):       PhaseIndexFunc,
+	}
+}
+
+func NamespaceIndexFunc(obj interface{}) ([]string, error) {
+	foo, ok := obj.(*demo.Foo)
+	if !ok {
+		return nil, fmt.Errorf("not a foo")
+	}
+	return []string{foo.Namespace}, nil
+}
+
+func ImageIndexFunc(obj interface{}) ([]string, error) {
+	foo, ok := obj.(*demo.Foo)
+	if !ok {
+		return nil, fmt.Errorf("not a foo")
+	}
+	return []string{foo.Spec.Image}, nil
+}
+
+func PhaseIndexFunc(obj interface{}) ([]string, error) {
+	foo, ok := obj.(*demo.Foo)
+	if !ok {
+		return nil, fmt.Errorf("not a foo")
 	}
+	return []string{string(foo.Status.Phase)}, nil
+}
+
+// ImageTriggerFunc only wakes up watchers whose field selector matches the
+// image of the changed foo. The watch cache supports a single trigger.
+func ImageTriggerFunc(obj runtime.Object) string {
+	return obj.(*demo.Foo).Spec.Image
 }
 
-func (fooStrategy) NamespaceScoped() bool                                         { return true }
-func (fooStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object)      {}
-func (fooStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {}
+func (fooStrategy) NamespaceScoped() bool { return true }
+
+// GetResetFields returns the fields PrepareForCreate and PrepareForUpdate
+// reset, so that server-side apply does not record managers as their owners.
+func (fooStrategy) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.FooStatusSubresource) {
+		return nil
+	}
+	return resetFields(fieldpath.MakePathOrDie("status"))
+}
+
+// resetFields returns paths for every served version.
+func resetFields(paths ...fieldpath.Path) map[fieldpath.APIVersion]*fieldpath.Set {
+	return map[fieldpath.APIVersion]*fieldpath.Set{
+		fieldpath.APIVersion(v1alpha1.SchemeGroupVersion.String()): fieldpath.NewSet(paths...),
+		fieldpath.APIVersion(v1beta1.SchemeGroupVersion.String()):  fieldpath.NewSet(paths...),
+	}
+}
+
+func (s fooStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	foo := obj.(*demo.Foo)
+	if utilfeature.DefaultFeatureGate.Enabled(features.FooStatusSubresource) {
+		foo.Status = demo.FooStatus{}
+	}
+	dropDisabledFields(foo, nil)
+	// the controller removes the finalizer once it cleaned up after the foo
+	if s.cleanupFinalizer && !slices.Contains(foo.Finalizers, demo.FooCleanupFinalizer) {
+		foo.Finalizers = append(foo.Finalizers, demo.FooCleanupFinalizer)
+	}
+}
+
+func (fooStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	newFoo := obj.(*demo.Foo)
+	oldFoo := old.(*demo.Foo)
+	if utilfeature.DefaultFeatureGate.Enabled(features.FooStatusSubresource) {
+		newFoo.Status = oldFoo.Status
+	}
+	dropDisabledFields(newFoo, oldFoo)
+}
+
+// dropDisabledFields removes fields guarded by disabled feature gates, unless
+// the old object already uses them.
+func dropDisabledFields(foo, oldFoo *demo.Foo) {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ConfigResource) && (oldFoo == nil || len(oldFoo.Spec.ConfigName) == 0) {
+		foo.Spec.ConfigName = ""
+	}
+}
 
 func (fooStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
-	return field.ErrorList{}
+	return validateFooSpec(&obj.(*demo.Foo).Spec, field.NewPath("spec"))
+}
+
+func validateFooSpec(spec *demo.FooSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := apivalidation.ValidateNonnegativeField(int64(spec.Replicas), fldPath.Child("replicas"))
+	if spec.TerminationGracePeriodSeconds != nil {
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeField(*spec.TerminationGracePeriodSeconds, fldPath.Child("terminationGracePeriodSeconds"))...)
+	}
+	return allErrs
 }
 
 func (fooStrategy) Canonicalize(obj runtime.Object) {}
 
+// CheckGracefulDelete marks a deleted foo Terminating. Unless the delete
+// options set a grace period, the foo is given
+// spec.terminationGracePeriodSeconds, and without one it is deleted as soon
+// as its finalizers are removed. Only the controller ends a grace period, so
+// a foo without demo.FooCleanupFinalizer, which no controller looks after, is
+// deleted right away.
+func (fooStrategy) CheckGracefulDelete(ctx context.Context, obj runtime.Object, options *metav1.DeleteOptions) bool {
+	foo := obj.(*demo.Foo)
+	foo.Status.Phase = demo.FooPhaseTerminating
+	if !slices.Contains(foo.Finalizers, demo.FooCleanupFinalizer) {
+		return false
+	}
+	if options.GracePeriodSeconds == nil && foo.Spec.TerminationGracePeriodSeconds != nil {
+		period := *foo.Spec.TerminationGracePeriodSeconds
+		options.GracePeriodSeconds = &period
+	}
+	return options.GracePeriodSeconds != nil && *options.GracePeriodSeconds > 0
+}
+
 func (fooStrategy) WarningsOnCreate(ctx context.Context, obj runtime.Object) []string {
-	return nil
+	return warningsForFoo(obj.(*demo.Foo), nil)
 }
 
 func (fooStrategy) AllowCreateOnUpdate() bool {
@@ -65,9 +198,37 @@ func (fooStrategy) AllowUnconditionalUpdate() bool {
 }
 
 func (fooStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
-	return field.ErrorList{}
+	return validateFooSpec(&obj.(*demo.Foo).Spec, field.NewPath("spec"))
 }
 
 func (fooStrategy) WarningsOnUpdate(ctx context.Context, obj, old runtime.Object) []string {
+	return warningsForFoo(obj.(*demo.Foo), old.(*demo.Foo))
+}
+
+type fooStatusStrategy struct {
+	fooStrategy
+}
+
+// NewStatusStrategy creates a strategy for the foos/status subresource,
+// which only lets the status change.
+func NewStatusStrategy(strategy fooStrategy) fooStatusStrategy {
+	return fooStatusStrategy{strategy}
+}
+
+func (fooStatusStrategy) GetResetFields() map[fieldpath.APIVersion]*fieldpath.Set {
+	return resetFields(fieldpath.MakePathOrDie("spec"))
+}
+
+func (fooStatusStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	newFoo := obj.(*demo.Foo)
+	oldFoo := old.(*demo.Foo)
+	newFoo.Spec = oldFoo.Spec
+}
+
+func (fooStatusStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	return field.ErrorList{}
+}
+
+func (fooStatusStrategy) WarningsOnUpdate(ctx context.Context, obj, old runtime.Object) []string {
 	return nil
 }
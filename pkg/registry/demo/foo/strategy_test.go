@@ -0,0 +1,113 @@
+package foo
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/features"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"testing"
+)
+
+func TestCheckGracefulDelete(t *testing.T) {
+	tests := []struct {
+		name          string
+		finalizer     bool
+		specPeriod    *int64
+		optionsPeriod *int64
+		graceful      bool
+		period        *int64
+	}{
+		{name: "no grace period", finalizer: true},
+		{name: "spec period", finalizer: true, specPeriod: ptr.To[int64](30), graceful: true, period: ptr.To[int64](30)},
+		{name: "options override the spec", finalizer: true, specPeriod: ptr.To[int64](30), optionsPeriod: ptr.To[int64](5), graceful: true, period: ptr.To[int64](5)},
+		{name: "options end the period", finalizer: true, specPeriod: ptr.To[int64](30), optionsPeriod: ptr.To[int64](0)},
+		{name: "spec period without finalizer", specPeriod: ptr.To[int64](30)},
+		{name: "options period without finalizer", optionsPeriod: ptr.To[int64](5)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			foo := &demo.Foo{Spec: demo.FooSpec{TerminationGracePeriodSeconds: tc.specPeriod}}
+			if tc.finalizer {
+				foo.Finalizers = []string{demo.FooCleanupFinalizer}
+			}
+			options := &metav1.DeleteOptions{GracePeriodSeconds: tc.optionsPeriod}
+			graceful := NewStrategy(runtime.NewScheme(), true).CheckGracefulDelete(context.Background(), foo, options)
+			if graceful != tc.graceful {
+				t.Errorf("expected graceful %v, got %v", tc.graceful, graceful)
+			}
+			if tc.graceful && ptr.Deref(options.GracePeriodSeconds, -1) != ptr.Deref(tc.period, -1) {
+				t.Errorf("expected a grace period of %v, got %v", ptr.Deref(tc.period, -1), ptr.Deref(options.GracePeriodSeconds, -1))
+			}
+			if foo.Status.Phase != demo.FooPhaseTerminating {
+				t.Errorf("expected the foo to be Terminating, got %q", foo.Status.Phase)
+			}
+		})
+	}
+}
+
+func TestDropDisabledFields(t *testing.T) {
+	withConfig := func(name string) *demo.Foo {
+		return &demo.Foo{Spec: demo.FooSpec{Image: "busybox:1.36", ConfigName: name}}
+	}
+	tests := []struct {
+		name    string
+		enabled bool
+		// old is nil on create
+		old  *demo.Foo
+		keep bool
+	}{
+		{name: "created with the gate off"},
+		{name: "created with the gate on", enabled: true, keep: true},
+		{name: "set on update with the gate off", old: withConfig("")},
+		{name: "kept on update with the gate off", old: withConfig("old"), keep: true},
+		{name: "set on update with the gate on", enabled: true, old: withConfig(""), keep: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ConfigResource, tc.enabled)
+			strategy := NewStrategy(runtime.NewScheme(), false)
+			foo := withConfig("new")
+			if tc.old == nil {
+				strategy.PrepareForCreate(context.Background(), foo)
+			} else {
+				strategy.PrepareForUpdate(context.Background(), foo, tc.old)
+			}
+			if kept := foo.Spec.ConfigName == "new"; kept != tc.keep {
+				t.Errorf("expected configName to be kept: %v, got %q", tc.keep, foo.Spec.ConfigName)
+			}
+			if foo.Spec.Image != "busybox:1.36" {
+				t.Errorf("expected the other fields to stay, got %+v", foo.Spec)
+			}
+		})
+	}
+}
+
+func TestGetResetFields(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("FooStatusSubresource=%v", enabled), func(t *testing.T) {
+			featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.FooStatusSubresource, enabled)
+			strategy := NewStrategy(runtime.NewScheme(), false)
+			fields := strategy.GetResetFields()
+			if !enabled {
+				if fields != nil {
+					t.Errorf("expected no reset fields without foos/status, got %v", fields)
+				}
+				return
+			}
+			for _, version := range []string{"demo.k8s.io/v1alpha1", "demo.k8s.io/v1beta1"} {
+				if set := fields[fieldpath.APIVersion(version)]; set == nil || !set.Has(fieldpath.MakePathOrDie("status")) {
+					t.Errorf("expected status to be reset in %s, got %v", version, set)
+				}
+				if set := NewStatusStrategy(strategy).GetResetFields()[fieldpath.APIVersion(version)]; set == nil || !set.Has(fieldpath.MakePathOrDie("spec")) {
+					t.Errorf("expected spec to be reset on foos/status in %s, got %v", version, set)
+				}
+			}
+		})
+	}
+}
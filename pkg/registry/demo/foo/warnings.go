@@ -0,0 +1,57 @@
+package foo
+
+import (
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"strings"
+)
+
+// maxConfigSize is the most data a ConfigMap of the backing cluster holds.
+// The messages of a foo end up in one.
+const maxConfigSize = 1024 * 1024
+
+// configSizeWarningThreshold is the size of the messages of a foo above which
+// creating or updating it warns.
+const configSizeWarningThreshold = maxConfigSize * 9 / 10
+
+// warningsForFoo returns the warnings for creating foo or, if oldFoo is set,
+// for updating oldFoo to foo. An update only warns about what it changes.
+// Requests against v1alpha1 foos get a deprecation warning from the
+// APILifecycle methods of the v1alpha1 types in addition.
+func warningsForFoo(foo, oldFoo *demo.Foo) []string {
+	var warnings []string
+	specPath := field.NewPath("spec")
+
+	if len(foo.Spec.Image) > 0 && (oldFoo == nil || foo.Spec.Image != oldFoo.Spec.Image) {
+		if tag, pinned := imageTag(foo.Spec.Image); !pinned && (len(tag) == 0 || tag == "latest") {
+			warnings = append(warnings, fmt.Sprintf("%s: %q does not pin a version; the workers may run different images, use a tag other than latest or a digest",
+				specPath.Child("image"), foo.Spec.Image))
+		}
+	}
+	if len(foo.Spec.Config.Msg1) > 0 && (oldFoo == nil || len(oldFoo.Spec.Config.Msg1) == 0) {
+		warnings = append(warnings, fmt.Sprintf("%s: deprecated, put the whole message in %s",
+			specPath.Child("config", "msg1"), specPath.Child("config", "msg")))
+	}
+	if size := configSize(foo); size > configSizeWarningThreshold && (oldFoo == nil || size > configSize(oldFoo)) {
+		warnings = append(warnings, fmt.Sprintf("%s: the messages take %d bytes, close to the %d bytes the ConfigMap of the Foo can hold",
+			specPath.Child("config"), size, maxConfigSize))
+	}
+	return warnings
+}
+
+// imageTag returns the tag of image, and whether image is pinned by a digest.
+func imageTag(image string) (tag string, pinned bool) {
+	if strings.Contains(image, "@") {
+		return "", true
+	}
+	// a colon before the last slash separates the port of the registry
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:], false
+	}
+	return "", false
+}
+
+func configSize(foo *demo.Foo) int {
+	return len("msg") + len(foo.Spec.Config.Msg) + len("msg1") + len(foo.Spec.Config.Msg1)
+}
@@ -0,0 +1,89 @@
+package foo
+
+import (
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"strings"
+	"testing"
+)
+
+func TestImageTag(t *testing.T) {
+	tests := []struct {
+		image  string
+		tag    string
+		pinned bool
+	}{
+		{image: "busybox"},
+		{image: "busybox:latest", tag: "latest"},
+		{image: "busybox:1.36", tag: "1.36"},
+		{image: "host:5000/img"},
+		{image: "host:5000/img:latest", tag: "latest"},
+		{image: "host:5000/team/img:v2", tag: "v2"},
+		{image: "busybox@sha256:9ae97d36d26566ff84e8893c64a6dc4fe8ca6d1144bf5b87b2b85a32def253c7", pinned: true},
+		{image: "busybox:latest@sha256:9ae97d36d26566ff84e8893c64a6dc4fe8ca6d1144bf5b87b2b85a32def253c7", pinned: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.image, func(t *testing.T) {
+			tag, pinned := imageTag(tc.image)
+			if tag != tc.tag || pinned != tc.pinned {
+				t.Errorf("expected tag %q and pinned %v, got %q and %v", tc.tag, tc.pinned, tag, pinned)
+			}
+		})
+	}
+}
+
+func TestWarningsForFoo(t *testing.T) {
+	foo := func(image, msg, msg1 string) *demo.Foo {
+		return &demo.Foo{Spec: demo.FooSpec{Image: image, Config: demo.FooConfig{Msg: msg, Msg1: msg1}}}
+	}
+	// configSize counts the keys too
+	large := strings.Repeat("x", configSizeWarningThreshold)
+	small := strings.Repeat("x", configSizeWarningThreshold-len("msg")-len("msg1"))
+
+	tests := []struct {
+		name   string
+		foo    *demo.Foo
+		oldFoo *demo.Foo
+		// want are substrings of the expected warnings, in order
+		want []string
+	}{
+		{name: "tagged", foo: foo("busybox:1.36", "hi", "")},
+		{name: "no image", foo: foo("", "hi", "")},
+		{name: "untagged", foo: foo("busybox", "hi", ""), want: []string{`spec.image: "busybox" does not pin a version`}},
+		{name: "latest", foo: foo("busybox:latest", "hi", ""), want: []string{`spec.image: "busybox:latest" does not pin a version`}},
+		{name: "registry port without tag", foo: foo("host:5000/img", "hi", ""), want: []string{`spec.image: "host:5000/img" does not pin a version`}},
+		{name: "registry port with tag", foo: foo("host:5000/img:v1", "hi", "")},
+		{name: "digest", foo: foo("busybox@sha256:9ae97d36d26566ff84e8893c64a6dc4fe8ca6d1144bf5b87b2b85a32def253c7", "hi", "")},
+		{name: "msg1", foo: foo("busybox:1.36", "hi", "there"), want: []string{"spec.config.msg1: deprecated"}},
+		{
+			name: "everything",
+			foo:  foo("busybox", large, "there"),
+			want: []string{"spec.image", "spec.config.msg1", "spec.config: the messages take"},
+		},
+
+		{name: "update keeping an untagged image", foo: foo("busybox", "new", ""), oldFoo: foo("busybox", "old", "")},
+		{name: "update to an untagged image", foo: foo("busybox", "hi", ""), oldFoo: foo("busybox:1.36", "hi", ""), want: []string{"spec.image"}},
+		{name: "update to another untagged image", foo: foo("alpine", "hi", ""), oldFoo: foo("busybox", "hi", ""), want: []string{"spec.image"}},
+		{name: "update keeping msg1", foo: foo("busybox:1.36", "hi", "new"), oldFoo: foo("busybox:1.36", "hi", "old")},
+		{name: "update setting msg1", foo: foo("busybox:1.36", "hi", "new"), oldFoo: foo("busybox:1.36", "hi", ""), want: []string{"spec.config.msg1"}},
+
+		{name: "at the size threshold", foo: foo("busybox:1.36", small, "")},
+		{name: "above the size threshold", foo: foo("busybox:1.36", large, ""), want: []string{"spec.config: the messages take"}},
+		{name: "update growing above the threshold", foo: foo("busybox:1.36", large, ""), oldFoo: foo("busybox:1.36", "hi", ""), want: []string{"spec.config"}},
+		{name: "update growing while above the threshold", foo: foo("busybox:1.36", large+"x", ""), oldFoo: foo("busybox:1.36", large, ""), want: []string{"spec.config"}},
+		{name: "update shrinking while above the threshold", foo: foo("busybox:1.36", large, ""), oldFoo: foo("busybox:1.36", large+"x", "")},
+		{name: "update keeping the size above the threshold", foo: foo("busybox:1.36", large, ""), oldFoo: foo("busybox:1.36", large, "")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := warningsForFoo(tc.foo, tc.oldFoo)
+			if len(got) != len(tc.want) {
+				t.Fatalf("expected %d warnings, got %q", len(tc.want), got)
+			}
+			for i := range got {
+				if !strings.Contains(got[i], tc.want[i]) {
+					t.Errorf("expected warning %d to contain %q, got %q", i, tc.want[i], got[i])
+				}
+			}
+		})
+	}
+}
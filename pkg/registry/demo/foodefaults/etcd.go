@@ -0,0 +1,36 @@
+package foodefaults
+
+import (
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/registry"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/generic"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+func NewREST(scheme *runtime.Scheme, opsGetter generic.RESTOptionsGetter, applied bool) (*registry.REST, error) {
+	strategy := NewStrategy(scheme, applied)
+
+	store := &genericregistry.Store{
+		NewFunc: func() runtime.Object {
+			return &demo.FooDefaults{}
+		},
+		NewListFunc: func() runtime.Object {
+			return &demo.FooDefaultsList{}
+		},
+		PredicateFunc:             MatchFooDefaults,
+		DefaultQualifiedResource:  demo.Resource("foodefaults"),
+		SingularQualifiedResource: demo.Resource("foodefaults"),
+		CreateStrategy:            strategy,
+		UpdateStrategy:            strategy,
+		DeleteStrategy:            strategy,
+
+		TableConvertor: rest.NewDefaultTableConvertor(demo.Resource("foodefaults")),
+	}
+	options := &generic.StoreOptions{RESTOptions: opsGetter, AttrFunc: GetAttrs}
+	if err := store.CompleteWithOptions(options); err != nil {
+		return nil, err
+	}
+	return &registry.REST{Store: store, ShortNameList: []string{"fdef"}, CategoryList: []string{"demo"}}, nil
+}
@@ -0,0 +1,136 @@
+package foodefaults
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/names"
+	"regexp"
+	"strings"
+)
+
+// MaxPriority is the highest priority of a FooDefaults
+const MaxPriority = 1000
+
+// precedence is how the FooDefaults admission plugin orders the FooDefaults
+// that select the same Foo
+const precedence = "of the FooDefaults that select a Foo, the one with the highest priority supplies a field, and of equal priorities the first by name"
+
+// tagRegexp is what a registry accepts as an image tag
+var tagRegexp = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+
+// notAppliedWarning is returned on writes while no admission plugin applies
+// FooDefaults
+const notAppliedWarning = "FooDefaults are not applied to new Foos: this server runs without the FooDefaults admission plugin, see --enable-admission and --enable-admission-plugins"
+
+// NewStrategy returns the strategy of FooDefaults. applied tells whether the
+// FooDefaults admission plugin applies them to new Foos; writes warn if not.
+func NewStrategy(typer runtime.ObjectTyper, applied bool) fooDefaultsStrategy {
+	return fooDefaultsStrategy{typer, names.SimpleNameGenerator, applied}
+}
+
+func GetAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
+	defaults, ok := obj.(*demo.FooDefaults)
+	if !ok {
+		return nil, nil, fmt.Errorf("given object is not a FooDefaults")
+	}
+	return defaults.ObjectMeta.Labels, SelectableFields(defaults), nil
+}
+
+type fooDefaultsStrategy struct {
+	runtime.ObjectTyper
+	names.NameGenerator
+
+	applied bool
+}
+
+func SelectableFields(obj *demo.FooDefaults) fields.Set {
+	return generic.ObjectMetaFieldsSet(&obj.ObjectMeta, true)
+}
+
+func MatchFooDefaults(label labels.Selector, field fields.Selector) storage.SelectionPredicate {
+	return storage.SelectionPredicate{
+		Label:    label,
+		Field:    field,
+		GetAttrs: GetAttrs,
+	}
+}
+
+func (fooDefaultsStrategy) NamespaceScoped() bool                                         { return true }
+func (fooDefaultsStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object)      {}
+func (fooDefaultsStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {}
+
+func (fooDefaultsStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	return validateFooDefaultsSpec(&obj.(*demo.FooDefaults).Spec, field.NewPath("spec"))
+}
+
+func validateFooDefaultsSpec(spec *demo.FooDefaultsSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec.Selector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(spec.Selector, metav1validation.LabelSelectorValidationOptions{}, fldPath.Child("selector"))...)
+	}
+	if spec.Priority < 0 || spec.Priority > MaxPriority {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("priority"), spec.Priority,
+			fmt.Sprintf("must be between 0 and %d; %s", MaxPriority, precedence)))
+	}
+	if len(spec.ImageRegistry) > 0 {
+		allErrs = append(allErrs, validateImageRegistry(spec.ImageRegistry, fldPath.Child("imageRegistry"))...)
+	}
+	if len(spec.ImageTag) > 0 && !tagRegexp.MatchString(spec.ImageTag) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("imageTag"), spec.ImageTag,
+			"must start with a letter, digit or underscore, may contain periods and dashes, and be at most 128 characters"))
+	}
+	allErrs = append(allErrs, metav1validation.ValidateLabels(spec.Labels, fldPath.Child("labels"))...)
+	return allErrs
+}
+
+// validateImageRegistry requires a registry host, optionally with a port and
+// a path. Without a host the registry would be read as part of the image name.
+func validateImageRegistry(registry string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch host, _, _ := strings.Cut(registry, "/"); {
+	case strings.Contains(registry, "://"):
+		allErrs = append(allErrs, field.Invalid(fldPath, registry, "must not have a scheme"))
+	case strings.HasSuffix(registry, "/") || strings.ContainsAny(registry, "@ \t\n"):
+		allErrs = append(allErrs, field.Invalid(fldPath, registry, "must be a registry host with an optional port and path"))
+	case !strings.ContainsAny(host, ".:") && host != "localhost":
+		allErrs = append(allErrs, field.Invalid(fldPath, registry, "must start with a registry host, which contains a period or a port or is localhost"))
+	}
+	return allErrs
+}
+
+func (fooDefaultsStrategy) Canonicalize(obj runtime.Object) {}
+
+func (s fooDefaultsStrategy) WarningsOnCreate(ctx context.Context, obj runtime.Object) []string {
+	return s.warnings()
+}
+
+func (fooDefaultsStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+func (fooDefaultsStrategy) AllowUnconditionalUpdate() bool {
+	return false
+}
+
+func (fooDefaultsStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	return validateFooDefaultsSpec(&obj.(*demo.FooDefaults).Spec, field.NewPath("spec"))
+}
+
+func (s fooDefaultsStrategy) WarningsOnUpdate(ctx context.Context, obj, old runtime.Object) []string {
+	return s.warnings()
+}
+
+func (s fooDefaultsStrategy) warnings() []string {
+	if !s.applied {
+		return []string{notAppliedWarning}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package fooreview
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/v1beta1"
+	foostorage "github.com/guodoliu/apiserver/pkg/registry/demo/foo"
+	"k8s.io/apimachinery/pkg/api/errors"
+	genericvalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/api/validation/path"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/warning"
+	"sync"
+)
+
+// REST implements fooreviews. Reviews run the steps of creating a Foo,
+// defaulting, mutating admission, validation and validating admission, and
+// report their outcome instead of storing anything.
+type REST struct {
+	strategy  rest.RESTCreateStrategy
+	admission admission.Interface
+	objects   admission.ObjectInterfaces
+}
+
+var (
+	_ rest.Creater              = &REST{}
+	_ rest.Scoper               = &REST{}
+	_ rest.SingularNameProvider = &REST{}
+)
+
+// NewREST returns the storage of fooreviews. admissionControl may be nil if
+// the server runs without admission. cleanupFinalizer must match the storage
+// of foos, so that reviews show the finalizers a created foo would get.
+func NewREST(scheme *runtime.Scheme, admissionControl admission.Interface, cleanupFinalizer bool) *REST {
+	return &REST{
+		strategy:  foostorage.NewStrategy(scheme, cleanupFinalizer),
+		admission: admissionControl,
+		objects:   admission.NewObjectInterfacesFromScheme(scheme),
+	}
+}
+
+func (r *REST) New() runtime.Object {
+	return &demo.FooReview{}
+}
+
+// Destroy cleans up resources on shutdown.
+func (r *REST) Destroy() {
+	// Given no underlying store, we don't destroy anything
+	// here explicitly.
+}
+
+func (r *REST) NamespaceScoped() bool {
+	return false
+}
+
+func (r *REST) GetSingularName() string {
+	return "fooreview"
+}
+
+func (r *REST) Create(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	review, ok := obj.(*demo.FooReview)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("not a FooReview: %T", obj))
+	}
+	if createValidation != nil {
+		if err := createValidation(ctx, review.DeepCopyObject()); err != nil {
+			return nil, err
+		}
+	}
+
+	foo := review.Spec.Foo.DeepCopy()
+	if len(foo.Namespace) == 0 {
+		foo.Namespace = metav1.NamespaceDefault
+	}
+	if len(foo.GenerateName) > 0 && len(foo.Name) == 0 {
+		foo.Name = r.strategy.GenerateName(foo.GenerateName)
+	}
+	rest.FillObjectMetaSystemFields(foo)
+
+	recorder := &warningRecorder{}
+	ctx = warning.WithWarningRecorder(genericapirequest.WithNamespace(ctx, foo.Namespace), recorder)
+	var causes []metav1.StatusCause
+
+	attrs := r.attributes(ctx, foo)
+	if mutator, ok := r.admission.(admission.MutationInterface); ok && mutator.Handles(admission.Create) {
+		if err := mutator.Admit(ctx, attrs, r.objects); err != nil {
+			causes = append(causes, statusCauses(err)...)
+		}
+	}
+
+	r.strategy.PrepareForCreate(ctx, foo)
+	errs := r.strategy.Validate(ctx, foo)
+	errs = append(errs, genericvalidation.ValidateObjectMetaAccessor(foo, r.strategy.NamespaceScoped(), path.ValidatePathSegmentName, field.NewPath("metadata"))...)
+	if len(errs) > 0 {
+		causes = append(causes, statusCauses(errors.NewInvalid(demo.Kind("Foo"), foo.Name, errs))...)
+	}
+	for _, w := range r.strategy.WarningsOnCreate(ctx, foo) {
+		warning.AddWarning(ctx, "", w)
+	}
+	r.strategy.Canonicalize(foo)
+
+	if validator, ok := r.admission.(admission.ValidationInterface); ok && validator.Handles(admission.Create) {
+		if err := validator.Validate(ctx, attrs, r.objects); err != nil {
+			causes = append(causes, statusCauses(err)...)
+		}
+	}
+
+	review.Status = demo.FooReviewStatus{
+		Allowed:  len(causes) == 0,
+		Foo:      *foo,
+		Errors:   causes,
+		Warnings: recorder.warnings,
+	}
+	return review, nil
+}
+
+func (r *REST) attributes(ctx context.Context, foo *demo.Foo) admission.Attributes {
+	userInfo, _ := genericapirequest.UserFrom(ctx)
+	return admission.NewAttributesRecord(foo, nil,
+		v1beta1.SchemeGroupVersion.WithKind("Foo"), foo.Namespace, foo.Name,
+		v1beta1.SchemeGroupVersion.WithResource("foos"), "", admission.Create,
+		&metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}, true, userInfo)
+}
+
+// statusCauses lists the causes of err, or err itself if it has none.
+func statusCauses(err error) []metav1.StatusCause {
+	if status, ok := err.(errors.APIStatus); ok {
+		if details := status.Status().Details; details != nil && len(details.Causes) > 0 {
+			return details.Causes
+		}
+		return []metav1.StatusCause{{Type: metav1.CauseType(status.Status().Reason), Message: status.Status().Message}}
+	}
+	return []metav1.StatusCause{{Message: err.Error()}}
+}
+
+// warningRecorder collects the warnings of a review, which are returned in
+// its status rather than as warning headers.
+type warningRecorder struct {
+	lock     sync.Mutex
+	warnings []string
+}
+
+func (r *warningRecorder) AddWarning(_, text string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.warnings = append(r.warnings, text)
+}
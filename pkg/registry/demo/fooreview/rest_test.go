@@ -0,0 +1,159 @@
+package fooreview
+
+import (
+	"context"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/apis/demo/install"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/warning"
+	"strings"
+	"testing"
+)
+
+// testAdmission labels the foos it admits and forbids foos named "forbidden".
+type testAdmission struct {
+	*admission.Handler
+}
+
+var (
+	_ admission.MutationInterface   = testAdmission{}
+	_ admission.ValidationInterface = testAdmission{}
+)
+
+func (testAdmission) Admit(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	foo := a.GetObject().(*demo.Foo)
+	if foo.Labels == nil {
+		foo.Labels = map[string]string{}
+	}
+	foo.Labels["admitted"] = "true"
+	return nil
+}
+
+func (testAdmission) Validate(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces) error {
+	if a.GetName() == "forbidden" {
+		return admission.NewForbidden(a, errors.NewBadRequest("foos named forbidden are not allowed"))
+	}
+	return nil
+}
+
+func TestCreate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	install.Install(scheme)
+	r := NewREST(scheme, testAdmission{admission.NewHandler(admission.Create)}, true)
+
+	tests := []struct {
+		name        string
+		foo         demo.Foo
+		wantAllowed bool
+		// wantErrors are parts of the messages of the expected errors
+		wantErrors   []string
+		wantWarnings []string
+	}{
+		{
+			name:        "valid",
+			foo:         demo.Foo{ObjectMeta: metav1.ObjectMeta{Name: "valid"}, Spec: demo.FooSpec{Image: "busybox:1.36", Replicas: 1}},
+			wantAllowed: true,
+		},
+		{
+			name:       "invalid",
+			foo:        demo.Foo{ObjectMeta: metav1.ObjectMeta{Name: "in/valid"}, Spec: demo.FooSpec{Image: "busybox:1.36", Replicas: -1}},
+			wantErrors: []string{"greater than or equal to 0", "may not contain"},
+		},
+		{
+			name:       "forbidden by admission",
+			foo:        demo.Foo{ObjectMeta: metav1.ObjectMeta{Name: "forbidden"}, Spec: demo.FooSpec{Image: "busybox:1.36", Replicas: 1}},
+			wantErrors: []string{"foos named forbidden are not allowed"},
+		},
+		{
+			name:         "warnings",
+			foo:          demo.Foo{ObjectMeta: metav1.ObjectMeta{Name: "unpinned"}, Spec: demo.FooSpec{Image: "busybox", Replicas: 1}},
+			wantAllowed:  true,
+			wantWarnings: []string{"spec.image"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// warnings of the review must not become warnings of the request
+			headers := &warningRecorder{}
+			ctx := warning.WithWarningRecorder(context.Background(), headers)
+			obj, err := r.Create(ctx, &demo.FooReview{Spec: demo.FooReviewSpec{Foo: tc.foo}}, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			status := obj.(*demo.FooReview).Status
+
+			if status.Allowed != tc.wantAllowed {
+				t.Errorf("expected allowed %v, got %v with %+v", tc.wantAllowed, status.Allowed, status.Errors)
+			}
+			if len(status.Errors) != len(tc.wantErrors) {
+				t.Errorf("expected %d errors, got %+v", len(tc.wantErrors), status.Errors)
+			}
+			for _, want := range tc.wantErrors {
+				if !hasCause(status.Errors, want) {
+					t.Errorf("expected an error about %q, got %+v", want, status.Errors)
+				}
+			}
+			if len(status.Warnings) != len(tc.wantWarnings) {
+				t.Errorf("expected %d warnings, got %q", len(tc.wantWarnings), status.Warnings)
+			}
+			for i := range tc.wantWarnings {
+				if i < len(status.Warnings) && !strings.HasPrefix(status.Warnings[i], tc.wantWarnings[i]) {
+					t.Errorf("expected a warning about %s, got %q", tc.wantWarnings[i], status.Warnings[i])
+				}
+			}
+			if len(headers.warnings) > 0 {
+				t.Errorf("expected no warning headers, got %q", headers.warnings)
+			}
+
+			foo := status.Foo
+			if foo.Namespace != metav1.NamespaceDefault {
+				t.Errorf("expected the foo in namespace default, got %q", foo.Namespace)
+			}
+			if len(foo.UID) == 0 || foo.CreationTimestamp.IsZero() {
+				t.Errorf("expected the system fields of the foo to be filled, got %+v", foo.ObjectMeta)
+			}
+			if foo.Labels["admitted"] != "true" {
+				t.Errorf("expected the foo to be mutated by admission, got labels %v", foo.Labels)
+			}
+			if len(foo.Finalizers) != 1 || foo.Finalizers[0] != demo.FooCleanupFinalizer {
+				t.Errorf("expected the cleanup finalizer, got %v", foo.Finalizers)
+			}
+		})
+	}
+}
+
+func TestCreateWithoutAdmission(t *testing.T) {
+	scheme := runtime.NewScheme()
+	install.Install(scheme)
+	r := NewREST(scheme, nil, false)
+	obj, err := r.Create(context.Background(), &demo.FooReview{Spec: demo.FooReviewSpec{Foo: demo.Foo{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "foo-"},
+		Spec:       demo.FooSpec{Image: "busybox:1.36", Replicas: 1},
+	}}}, nil, &metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := obj.(*demo.FooReview).Status
+	if !status.Allowed {
+		t.Errorf("expected the foo to be allowed, got %+v", status.Errors)
+	}
+	if !strings.HasPrefix(status.Foo.Name, "foo-") || len(status.Foo.Name) == len("foo-") {
+		t.Errorf("expected a name generated from foo-, got %q", status.Foo.Name)
+	}
+	if len(status.Foo.Finalizers) > 0 {
+		t.Errorf("expected no finalizers, got %v", status.Foo.Finalizers)
+	}
+}
+
+func hasCause(causes []metav1.StatusCause, message string) bool {
+	for _, cause := range causes {
+		if strings.Contains(cause.Message, message) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package foorevision
+
+import (
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/registry"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/generic"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// NewREST returns the storage of foo revisions. It is not served on its own;
+// the foo registry writes revisions and serves them as foos/history.
+func NewREST(scheme *runtime.Scheme, opsGetter generic.RESTOptionsGetter) (*registry.REST, error) {
+	strategy := NewStrategy(scheme)
+
+	store := &genericregistry.Store{
+		NewFunc: func() runtime.Object {
+			return &demo.FooRevision{}
+		},
+		NewListFunc: func() runtime.Object {
+			return &demo.FooRevisionList{}
+		},
+		PredicateFunc:             MatchFooRevision,
+		DefaultQualifiedResource:  demo.Resource("foorevisions"),
+		SingularQualifiedResource: demo.Resource("foorevision"),
+		CreateStrategy:            strategy,
+		UpdateStrategy:            strategy,
+		DeleteStrategy:            strategy,
+
+		TableConvertor: rest.NewDefaultTableConvertor(demo.Resource("foorevisions")),
+	}
+	options := &generic.StoreOptions{RESTOptions: opsGetter, AttrFunc: GetAttrs}
+	if err := store.CompleteWithOptions(options); err != nil {
+		return nil, err
+	}
+	return &registry.REST{Store: store}, nil
+}
@@ -0,0 +1,89 @@
+package foorevision
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/names"
+)
+
+func NewStrategy(typer runtime.ObjectTyper) fooRevisionStrategy {
+	return fooRevisionStrategy{typer, names.SimpleNameGenerator}
+}
+
+func GetAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
+	revision, ok := obj.(*demo.FooRevision)
+	if !ok {
+		return nil, nil, fmt.Errorf("given object is not a FooRevision")
+	}
+	return revision.ObjectMeta.Labels, SelectableFields(revision), nil
+}
+
+type fooRevisionStrategy struct {
+	runtime.ObjectTyper
+	names.NameGenerator
+}
+
+func SelectableFields(obj *demo.FooRevision) fields.Set {
+	return generic.ObjectMetaFieldsSet(&obj.ObjectMeta, true)
+}
+
+func MatchFooRevision(label labels.Selector, field fields.Selector) storage.SelectionPredicate {
+	return storage.SelectionPredicate{
+		Label:    label,
+		Field:    field,
+		GetAttrs: GetAttrs,
+	}
+}
+
+func (fooRevisionStrategy) NamespaceScoped() bool                                         { return true }
+func (fooRevisionStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object)      {}
+func (fooRevisionStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {}
+
+func (fooRevisionStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	revision := obj.(*demo.FooRevision)
+	allErrs := field.ErrorList{}
+	if revision.Revision < 1 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("revision"), revision.Revision, "must be greater than 0"))
+	}
+	return allErrs
+}
+
+func (fooRevisionStrategy) Canonicalize(obj runtime.Object) {}
+
+func (fooRevisionStrategy) WarningsOnCreate(ctx context.Context, obj runtime.Object) []string {
+	return nil
+}
+
+func (fooRevisionStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+func (fooRevisionStrategy) AllowUnconditionalUpdate() bool {
+	return false
+}
+
+// ValidateUpdate keeps revisions immutable, only their metadata may change.
+func (fooRevisionStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	newRevision := obj.(*demo.FooRevision)
+	oldRevision := old.(*demo.FooRevision)
+	allErrs := field.ErrorList{}
+	if newRevision.Revision != oldRevision.Revision {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("revision"), "field is immutable"))
+	}
+	if !apiequality.Semantic.DeepEqual(newRevision.Spec, oldRevision.Spec) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "field is immutable"))
+	}
+	return allErrs
+}
+
+func (fooRevisionStrategy) WarningsOnUpdate(ctx context.Context, obj, old runtime.Object) []string {
+	return nil
+}
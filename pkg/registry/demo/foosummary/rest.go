@@ -0,0 +1,549 @@
+package foosummary
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/registry"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/klog/v2"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// watchQueueLength is the number of events a summary watcher may fall
+	// behind before it is stopped
+	watchQueueLength = 100
+	// maxTombstones bounds how many deleted summaries are remembered for
+	// watches that start from an older resource version
+	maxTombstones = 1000
+)
+
+// REST implements foosummaries. Summaries are kept in memory from a watch on
+// the foo store, so they cost nothing to read and are never written.
+type REST struct {
+	foos *genericregistry.Store
+
+	tableConvertor rest.TableConvertor
+	synced         chan struct{}
+
+	lock sync.RWMutex
+	// fooFields are the counted fields of every foo by namespace and name
+	fooFields map[string]map[string]countedFields
+	summaries map[string]*summary
+	// relist is set until the foos are listed, and again when a watch can
+	// not be resumed
+	relist bool
+	// tombstones are the summaries deleted since oldestResourceVersion
+	tombstones []*summary
+	// resourceVersion is that of the newest foo event applied
+	resourceVersion uint64
+	// oldestResourceVersion is the oldest resource version a watch can start from
+	oldestResourceVersion uint64
+	watchers              map[*summaryWatcher]struct{}
+}
+
+type summary struct {
+	foos *demo.FooSummary
+	// created is the resource version the summary was added at
+	created uint64
+	// deleted is the resource version the summary was deleted at
+	deleted uint64
+}
+
+// countedFields are the fields of a foo that summaries count
+type countedFields struct {
+	phase      demo.FooPhase
+	image      string
+	conditions []demo.FooCondition
+}
+
+var (
+	_ rest.Getter               = &REST{}
+	_ rest.Lister               = &REST{}
+	_ rest.Watcher              = &REST{}
+	_ rest.Scoper               = &REST{}
+	_ rest.SingularNameProvider = &REST{}
+	_ rest.ShortNamesProvider   = &REST{}
+	_ rest.CategoriesProvider   = &REST{}
+)
+
+// NewREST returns the storage of foosummaries. Summaries are served once Run
+// has listed the foos.
+func NewREST(foos *registry.REST) *REST {
+	return &REST{
+		foos:           foos.Store,
+		tableConvertor: rest.NewDefaultTableConvertor(demo.Resource("foosummaries")),
+		synced:         make(chan struct{}),
+		fooFields:      map[string]map[string]countedFields{},
+		summaries:      map[string]*summary{},
+		relist:         true,
+		watchers:       map[*summaryWatcher]struct{}{},
+	}
+}
+
+// Run lists and watches the foos and keeps the summaries up to date until ctx
+// is done. A watch that ends is resumed, or started over from a new list if
+// its resource version is gone.
+func (r *REST) Run(ctx context.Context) {
+	ctx = genericapirequest.WithNamespace(ctx, metav1.NamespaceAll)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := r.listAndWatch(ctx); err != nil {
+			klog.ErrorS(err, "Failed to watch foos for summaries")
+		}
+	}, time.Second)
+}
+
+func (r *REST) listAndWatch(ctx context.Context) error {
+	r.lock.RLock()
+	resourceVersion, relist := r.resourceVersion, r.relist
+	r.lock.RUnlock()
+
+	if relist {
+		obj, err := r.foos.List(ctx, &metainternalversion.ListOptions{})
+		if err != nil {
+			return err
+		}
+		list := obj.(*demo.FooList)
+		if resourceVersion, err = parseResourceVersion(list.ResourceVersion); err != nil {
+			return err
+		}
+		r.replace(list.Items, resourceVersion)
+	}
+
+	w, err := r.foos.Watch(ctx, &metainternalversion.ListOptions{
+		ResourceVersion:     strconv.FormatUint(resourceVersion, 10),
+		AllowWatchBookmarks: true,
+	})
+	if err != nil {
+		return r.handleWatchError(err)
+	}
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return nil
+			}
+			if event.Type == watch.Error {
+				return r.handleWatchError(errors.FromObject(event.Object))
+			}
+			if err := r.apply(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handleWatchError lists the foos again on the next run if the watch failed
+// because its resource version is gone.
+func (r *REST) handleWatchError(err error) error {
+	if errors.IsResourceExpired(err) || errors.IsGone(err) {
+		r.lock.Lock()
+		r.relist = true
+		r.lock.Unlock()
+		return nil
+	}
+	return err
+}
+
+// replace recounts every summary from a list of all foos.
+func (r *REST) replace(foos []demo.Foo, resourceVersion uint64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.fooFields = map[string]map[string]countedFields{}
+	namespaces := map[string]bool{}
+	for namespace := range r.summaries {
+		namespaces[namespace] = true
+	}
+	for i := range foos {
+		foo := &foos[i]
+		if r.fooFields[foo.Namespace] == nil {
+			r.fooFields[foo.Namespace] = map[string]countedFields{}
+		}
+		r.fooFields[foo.Namespace][foo.Name] = fieldsOf(foo)
+		namespaces[foo.Namespace] = true
+	}
+	r.resourceVersion = resourceVersion
+	r.relist = false
+	if r.oldestResourceVersion == 0 {
+		r.oldestResourceVersion = resourceVersion
+	}
+	for namespace := range namespaces {
+		r.recount(namespace)
+	}
+	select {
+	case <-r.synced:
+	default:
+		close(r.synced)
+	}
+}
+
+// apply counts a foo event in the summary of its namespace.
+func (r *REST) apply(event watch.Event) error {
+	accessor, err := meta.Accessor(event.Object)
+	if err != nil {
+		return err
+	}
+	resourceVersion, err := parseResourceVersion(accessor.GetResourceVersion())
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.resourceVersion = resourceVersion
+	if event.Type == watch.Bookmark {
+		return nil
+	}
+	foo, ok := event.Object.(*demo.Foo)
+	if !ok {
+		return fmt.Errorf("unexpected object in foo watch: %T", event.Object)
+	}
+	switch event.Type {
+	case watch.Added, watch.Modified:
+		if r.fooFields[foo.Namespace] == nil {
+			r.fooFields[foo.Namespace] = map[string]countedFields{}
+		}
+		r.fooFields[foo.Namespace][foo.Name] = fieldsOf(foo)
+	case watch.Deleted:
+		delete(r.fooFields[foo.Namespace], foo.Name)
+	}
+	r.recount(foo.Namespace)
+	return nil
+}
+
+// recount updates the summary of namespace and notifies watchers if it
+// changed. It must be called with the lock held.
+func (r *REST) recount(namespace string) {
+	old := r.summaries[namespace]
+	foos := r.fooFields[namespace]
+	if len(foos) == 0 {
+		delete(r.fooFields, namespace)
+		if old == nil {
+			return
+		}
+		delete(r.summaries, namespace)
+		old.deleted = r.resourceVersion
+		old.foos.ResourceVersion = strconv.FormatUint(r.resourceVersion, 10)
+		r.addTombstone(old)
+		r.broadcast(watch.Deleted, old.foos)
+		return
+	}
+
+	status := count(foos)
+	if old != nil && apiequality.Semantic.DeepEqual(old.foos.Status, status) {
+		return
+	}
+	s := &summary{
+		foos: &demo.FooSummary{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            namespace,
+				ResourceVersion: strconv.FormatUint(r.resourceVersion, 10),
+			},
+			Status: status,
+		},
+		created: r.resourceVersion,
+	}
+	eventType := watch.Added
+	if old != nil {
+		s.created = old.created
+		s.foos.CreationTimestamp = old.foos.CreationTimestamp
+		eventType = watch.Modified
+	} else {
+		s.foos.CreationTimestamp = metav1.Now()
+	}
+	r.summaries[namespace] = s
+	r.broadcast(eventType, s.foos)
+}
+
+func (r *REST) addTombstone(s *summary) {
+	r.tombstones = append(r.tombstones, s)
+	if n := len(r.tombstones) - maxTombstones; n > 0 {
+		// watches from before a forgotten deletion would miss it
+		r.oldestResourceVersion = r.tombstones[n-1].deleted
+		r.tombstones = append([]*summary(nil), r.tombstones[n:]...)
+	}
+}
+
+// broadcast sends an event to every watcher. It must be called with the lock
+// held, so it never waits for a watcher: like the watch cache, it stops
+// watchers that fell too far behind, and their clients resume from the last
+// event they got.
+func (r *REST) broadcast(eventType watch.EventType, obj *demo.FooSummary) {
+	event := watch.Event{Type: eventType, Object: obj.DeepCopy()}
+	for w := range r.watchers {
+		if !w.send(event) {
+			klog.V(2).InfoS("Stopping foo summary watcher that fell behind", "queueLength", cap(w.result))
+			r.stopWatcher(w)
+		}
+	}
+}
+
+// stopWatcher must be called with the lock held.
+func (r *REST) stopWatcher(w *summaryWatcher) {
+	if _, ok := r.watchers[w]; ok {
+		delete(r.watchers, w)
+		close(w.result)
+	}
+}
+
+type summaryWatcher struct {
+	r         *REST
+	predicate storage.SelectionPredicate
+	result    chan watch.Event
+}
+
+var _ watch.Interface = &summaryWatcher{}
+
+// send queues event if it matches, and reports false if the queue is full.
+func (w *summaryWatcher) send(event watch.Event) bool {
+	if ok, err := w.predicate.Matches(event.Object); err != nil || !ok {
+		return true
+	}
+	select {
+	case w.result <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *summaryWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func (w *summaryWatcher) Stop() {
+	w.r.lock.Lock()
+	defer w.r.lock.Unlock()
+	w.r.stopWatcher(w)
+}
+
+func fieldsOf(foo *demo.Foo) countedFields {
+	return countedFields{
+		phase:      foo.Status.Phase,
+		image:      foo.Spec.Image,
+		conditions: foo.Status.Conditions,
+	}
+}
+
+func count(foos map[string]countedFields) demo.FooSummaryStatus {
+	status := demo.FooSummaryStatus{Foos: int32(len(foos))}
+	conditions := map[demo.FooConditionType]*demo.FooConditionSummary{}
+	for _, foo := range foos {
+		if len(foo.phase) > 0 {
+			if status.Phases == nil {
+				status.Phases = map[demo.FooPhase]int32{}
+			}
+			status.Phases[foo.phase]++
+		}
+		if len(foo.image) > 0 {
+			if status.Images == nil {
+				status.Images = map[string]int32{}
+			}
+			status.Images[foo.image]++
+		}
+		for _, condition := range foo.conditions {
+			c := conditions[condition.Type]
+			if c == nil {
+				c = &demo.FooConditionSummary{Type: condition.Type}
+				conditions[condition.Type] = c
+			}
+			switch condition.Status {
+			case metav1.ConditionTrue:
+				c.True++
+			case metav1.ConditionFalse:
+				c.False++
+			default:
+				c.Unknown++
+			}
+		}
+	}
+	for _, c := range conditions {
+		status.Conditions = append(status.Conditions, *c)
+	}
+	sort.Slice(status.Conditions, func(i, j int) bool {
+		return status.Conditions[i].Type < status.Conditions[j].Type
+	})
+	return status
+}
+
+func parseResourceVersion(resourceVersion string) (uint64, error) {
+	if len(resourceVersion) == 0 {
+		return 0, nil
+	}
+	rv, err := strconv.ParseUint(resourceVersion, 10, 64)
+	if err != nil {
+		return 0, errors.NewBadRequest(fmt.Sprintf("invalid resource version %q: %v", resourceVersion, err))
+	}
+	return rv, nil
+}
+
+func (r *REST) New() runtime.Object {
+	return &demo.FooSummary{}
+}
+
+func (r *REST) NewList() runtime.Object {
+	return &demo.FooSummaryList{}
+}
+
+// Destroy cleans up resources on shutdown.
+func (r *REST) Destroy() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for w := range r.watchers {
+		r.stopWatcher(w)
+	}
+}
+
+func (r *REST) NamespaceScoped() bool {
+	return false
+}
+
+func (r *REST) GetSingularName() string {
+	return "foosummary"
+}
+
+func (r *REST) ShortNames() []string {
+	return []string{"fsum"}
+}
+
+func (r *REST) Categories() []string {
+	return []string{"demo"}
+}
+
+// waitForSync blocks until the foos were listed, like reads from a watch
+// cache do until it is initialized.
+func (r *REST) waitForSync(ctx context.Context) error {
+	select {
+	case <-r.synced:
+		return nil
+	case <-ctx.Done():
+		return errors.NewServiceUnavailable("foo summaries are not ready yet")
+	}
+}
+
+func (r *REST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	if err := r.waitForSync(ctx); err != nil {
+		return nil, err
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	s, ok := r.summaries[name]
+	if !ok {
+		return nil, errors.NewNotFound(demo.Resource("foosummaries"), name)
+	}
+	return s.foos.DeepCopy(), nil
+}
+
+func (r *REST) List(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+	if err := r.waitForSync(ctx); err != nil {
+		return nil, err
+	}
+	predicate := matchSummary(options)
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	list := &demo.FooSummaryList{
+		ListMeta: metav1.ListMeta{ResourceVersion: strconv.FormatUint(r.resourceVersion, 10)},
+	}
+	for _, s := range r.summaries {
+		if ok, err := predicate.Matches(s.foos); err != nil {
+			return nil, err
+		} else if ok {
+			list.Items = append(list.Items, *s.foos.DeepCopy())
+		}
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Name < list.Items[j].Name
+	})
+	return list, nil
+}
+
+// Watch starts with the summaries that changed after the requested resource
+// version, or with all of them if none is given, and then follows the foos.
+func (r *REST) Watch(ctx context.Context, options *metainternalversion.ListOptions) (watch.Interface, error) {
+	if err := r.waitForSync(ctx); err != nil {
+		return nil, err
+	}
+	since, err := parseResourceVersion(options.ResourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	predicate := matchSummary(options)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if since > 0 && since < r.oldestResourceVersion {
+		return nil, errors.NewResourceExpired(fmt.Sprintf("too old resource version: %d (%d)", since, r.oldestResourceVersion))
+	}
+	var events []watch.Event
+	for _, s := range r.summaries {
+		switch {
+		case since == 0 || s.created > since:
+			events = append(events, watch.Event{Type: watch.Added, Object: s.foos.DeepCopy()})
+		case resourceVersionOf(s.foos) > since:
+			events = append(events, watch.Event{Type: watch.Modified, Object: s.foos.DeepCopy()})
+		}
+	}
+	if since > 0 {
+		for _, s := range r.tombstones {
+			if s.deleted > since && s.created <= since {
+				events = append(events, watch.Event{Type: watch.Deleted, Object: s.foos.DeepCopy()})
+			}
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return resourceVersionOf(events[i].Object.(*demo.FooSummary)) < resourceVersionOf(events[j].Object.(*demo.FooSummary))
+	})
+	// the lock keeps later changes from being broadcast before these events
+	w := &summaryWatcher{r: r, predicate: predicate, result: make(chan watch.Event, len(events)+watchQueueLength)}
+	for _, event := range events {
+		w.send(event)
+	}
+	r.watchers[w] = struct{}{}
+	return w, nil
+}
+
+func resourceVersionOf(s *demo.FooSummary) uint64 {
+	rv, _ := strconv.ParseUint(s.ResourceVersion, 10, 64)
+	return rv
+}
+
+// matchSummary matches summaries by labels and by metadata.name.
+func matchSummary(options *metainternalversion.ListOptions) storage.SelectionPredicate {
+	label, field := labels.Everything(), fields.Everything()
+	if options != nil && options.LabelSelector != nil {
+		label = options.LabelSelector
+	}
+	if options != nil && options.FieldSelector != nil {
+		field = options.FieldSelector
+	}
+	return storage.SelectionPredicate{
+		Label:    label,
+		Field:    field,
+		GetAttrs: storage.DefaultClusterScopedAttr,
+	}
+}
+
+func (r *REST) ConvertToTable(ctx context.Context, object runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
+	return r.tableConvertor.ConvertToTable(ctx, object, tableOptions)
+}
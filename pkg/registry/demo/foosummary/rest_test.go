@@ -0,0 +1,193 @@
+package foosummary
+
+import (
+	"context"
+	"fmt"
+	"github.com/guodoliu/apiserver/pkg/apis/demo"
+	"github.com/guodoliu/apiserver/pkg/registry"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newFoo(namespace, name string, resourceVersion uint64) *demo.Foo {
+	return &demo.Foo{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, ResourceVersion: strconv.FormatUint(resourceVersion, 10)},
+		Spec:       demo.FooSpec{Image: "busybox:1.36"},
+	}
+}
+
+// applyAll applies foo events in order, failing the test on errors.
+func applyAll(t *testing.T, r *REST, events ...watch.Event) {
+	t.Helper()
+	for _, event := range events {
+		if err := r.apply(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+const eventTimeout = 5 * time.Second
+
+type summaryEvent struct {
+	Type            watch.EventType
+	Name            string
+	ResourceVersion string
+	Foos            int32
+}
+
+// receive reads n events from w.
+func receive(t *testing.T, w watch.Interface, n int) []summaryEvent {
+	t.Helper()
+	var got []summaryEvent
+	for len(got) < n {
+		select {
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				t.Fatalf("watch closed after %v", got)
+			}
+			s := event.Object.(*demo.FooSummary)
+			got = append(got, summaryEvent{event.Type, s.Name, s.ResourceVersion, s.Status.Foos})
+		case <-time.After(eventTimeout):
+			t.Fatalf("expected %d events, got %v", n, got)
+		}
+	}
+	return got
+}
+
+func TestWatchResume(t *testing.T) {
+	r := NewREST(&registry.REST{})
+	// rv 10: a/one. 11: b/one. 12: c/one. 13: a/one deleted, so summary a
+	// is gone. 14: c/one deleted. 15: b/two.
+	r.replace([]demo.Foo{*newFoo("a", "one", 10)}, 10)
+	applyAll(t, r,
+		watch.Event{Type: watch.Added, Object: newFoo("b", "one", 11)},
+		watch.Event{Type: watch.Added, Object: newFoo("c", "one", 12)},
+		watch.Event{Type: watch.Deleted, Object: newFoo("a", "one", 13)},
+		watch.Event{Type: watch.Deleted, Object: newFoo("c", "one", 14)},
+		watch.Event{Type: watch.Added, Object: newFoo("b", "two", 15)},
+	)
+
+	tests := []struct {
+		resourceVersion string
+		want            []summaryEvent
+	}{
+		{
+			resourceVersion: "",
+			want:            []summaryEvent{{watch.Added, "b", "15", 2}},
+		},
+		{
+			// a was known and is gone; c came and went after 10
+			resourceVersion: "10",
+			want:            []summaryEvent{{watch.Deleted, "a", "13", 1}, {watch.Added, "b", "15", 2}},
+		},
+		{
+			resourceVersion: "11",
+			want:            []summaryEvent{{watch.Deleted, "a", "13", 1}, {watch.Modified, "b", "15", 2}},
+		},
+		{
+			resourceVersion: "13",
+			want:            []summaryEvent{{watch.Deleted, "c", "14", 1}, {watch.Modified, "b", "15", 2}},
+		},
+		{
+			resourceVersion: "15",
+		},
+	}
+	for _, tc := range tests {
+		t.Run("from "+tc.resourceVersion, func(t *testing.T) {
+			w, err := r.Watch(context.Background(), &metainternalversion.ListOptions{ResourceVersion: tc.resourceVersion})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Stop()
+			if got := receive(t, w, len(tc.want)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+
+			// later changes follow the initial events
+			before := r.resourceVersion
+			applyAll(t, r, watch.Event{Type: watch.Added, Object: newFoo("d", "one", before+1)})
+			applyAll(t, r, watch.Event{Type: watch.Deleted, Object: newFoo("d", "one", before+2)})
+			want := []summaryEvent{
+				{watch.Added, "d", strconv.FormatUint(before+1, 10), 1},
+				// deleted summaries keep their last counts
+				{watch.Deleted, "d", strconv.FormatUint(before+2, 10), 1},
+			}
+			if got := receive(t, w, 2); !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestWatchExpired(t *testing.T) {
+	r := NewREST(&registry.REST{})
+	r.replace(nil, 1)
+	// ns-i is created at rv i+2 and deleted at rv i+1003
+	rv := uint64(1)
+	for _, eventType := range []watch.EventType{watch.Added, watch.Deleted} {
+		for i := 0; i <= maxTombstones; i++ {
+			rv++
+			applyAll(t, r, watch.Event{Type: eventType, Object: newFoo(fmt.Sprintf("ns-%d", i), "one", rv)})
+		}
+	}
+	if len(r.tombstones) != maxTombstones {
+		t.Fatalf("expected %d tombstones, got %d", maxTombstones, len(r.tombstones))
+	}
+
+	// the deletion of ns-0 at rv 1003 was forgotten
+	_, err := r.Watch(context.Background(), &metainternalversion.ListOptions{ResourceVersion: "1002"})
+	if !errors.IsResourceExpired(err) {
+		t.Errorf("expected a watch from before a forgotten deletion to expire, got %v", err)
+	}
+	w, err := r.Watch(context.Background(), &metainternalversion.ListOptions{ResourceVersion: "1003"})
+	if err != nil {
+		t.Fatalf("expected a watch from the oldest remembered deletion to start, got %v", err)
+	}
+	defer w.Stop()
+	if got := receive(t, w, maxTombstones); got[0].Name != "ns-1" || got[0].Type != watch.Deleted {
+		t.Errorf("expected the deletion of ns-1 first, got %v", got[0])
+	}
+}
+
+func TestSlowWatcher(t *testing.T) {
+	r := NewREST(&registry.REST{})
+	r.replace(nil, 1)
+	slow, err := r.Watch(context.Background(), &metainternalversion.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer slow.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 2*watchQueueLength; i++ {
+			if err := r.apply(watch.Event{Type: watch.Added, Object: newFoo("a", fmt.Sprint(i), uint64(i+2))}); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(eventTimeout):
+		t.Fatal("a watcher that does not read blocked the summaries")
+	}
+	if _, err := r.Get(context.Background(), "a", &metav1.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the watcher gets what fit in its queue, then the watch ends
+	received := 0
+	for range slow.ResultChan() {
+		received++
+	}
+	if received != watchQueueLength {
+		t.Errorf("expected the first %d events before the watch ended, got %d", watchQueueLength, received)
+	}
+}
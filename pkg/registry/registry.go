@@ -3,11 +3,29 @@ package registry
 import (
 	"fmt"
 	genericapiserver "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
 )
 
 // REST implements a RESTStorage for API services against etcd
 type REST struct {
 	*genericapiserver.Store
+	// ShortNameList and CategoryList are published in discovery, so that
+	// kubectl get accepts them in place of the resource
+	ShortNameList []string
+	CategoryList  []string
+}
+
+var (
+	_ rest.ShortNamesProvider = &REST{}
+	_ rest.CategoriesProvider = &REST{}
+)
+
+func (r *REST) ShortNames() []string {
+	return r.ShortNameList
+}
+
+func (r *REST) Categories() []string {
+	return r.CategoryList
 }
 
 func RESTInPeace(storage *REST, err error) *REST {